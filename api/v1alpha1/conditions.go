@@ -0,0 +1,81 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ConditionTypeReady indicates whether the resource is available for use
+	ConditionTypeReady = "Ready"
+	// ConditionTypeSynced indicates whether the upstream resource has been
+	// reconciled with the spec
+	ConditionTypeSynced = "Synced"
+)
+
+const (
+	// ReasonAvailable means the resource is ready to use
+	ReasonAvailable = "Available"
+	// ReasonUnavailable means the resource hasn't been created yet
+	ReasonUnavailable = "Unavailable"
+	// ReasonReconciled means the upstream resource matches the spec
+	ReasonReconciled = "Reconciled"
+	// ReasonReconcileError means the last reconcile failed with an error
+	// that doesn't have a more specific reason
+	ReasonReconcileError = "ReconcileError"
+	// ReasonConflict means an upstream resource with the same name exists
+	// but wasn't created for this resource
+	ReasonConflict = "Conflict"
+	// ReasonAccessDenied means the controller doesn't have permission to
+	// manage the upstream resource
+	ReasonAccessDenied = "AccessDenied"
+	// ReasonPolicyNotFound means a referenced IamPolicy doesn't exist or
+	// doesn't have an arn yet
+	ReasonPolicyNotFound = "PolicyNotFound"
+	// ReasonRoleNotFound means the referenced IamRole doesn't exist or
+	// doesn't have an arn yet
+	ReasonRoleNotFound = "RoleNotFound"
+	// ReasonDocumentNotFound means the ConfigMap or Secret key a policy
+	// document is read from, or the IamPolicyTemplate it's rendered from,
+	// doesn't exist
+	ReasonDocumentNotFound = "DocumentNotFound"
+	// ReasonDocumentTooLarge means a policy document has a statement that
+	// doesn't fit in a managed policy on its own
+	ReasonDocumentTooLarge = "DocumentTooLarge"
+	// ReasonVersionNotFound means the version a policy is pinned to doesn't
+	// exist
+	ReasonVersionNotFound = "VersionNotFound"
+	// ReasonUserNotFound means a referenced IamUser doesn't exist or
+	// doesn't have an arn yet
+	ReasonUserNotFound = "UserNotFound"
+	// ReasonPermissionsBoundaryRequired means a namespaced role can't be
+	// created because the controller doesn't have a default permissions
+	// boundary
+	ReasonPermissionsBoundaryRequired = "PermissionsBoundaryRequired"
+)
+
+// ConditionedStatus is the status shared by all resources
+type ConditionedStatus struct {
+	// ObservedGeneration is the generation of the resource that was
+	// last reconciled
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions describe the state of the resource
+	//+listType=map
+	//+listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+//+kubebuilder:validation:Enum=Delete;Retain
+
+// DeletionPolicy decides what happens to the upstream iam resource when
+// the custom resource is deleted
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete deletes the upstream resource
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyRetain leaves the upstream resource in place and only
+	// removes the marks the controller added to it
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+
+	// AnnotationDeletionPolicy sets the deletion policy of a resource that
+	// doesn't set spec.deletionPolicy
+	AnnotationDeletionPolicy = "aws.jackhoman.com/deletion-policy"
+)
+
+// validateDeletionPolicyAnnotation validates the deletion policy annotation
+// if it's set
+func validateDeletionPolicyAnnotation(obj metav1.Object) field.ErrorList {
+	value, ok := obj.GetAnnotations()[AnnotationDeletionPolicy]
+	if !ok {
+		return nil
+	}
+	switch DeletionPolicy(value) {
+	case DeletionPolicyDelete, DeletionPolicyRetain:
+		return nil
+	}
+	path := field.NewPath("metadata", "annotations").Key(AnnotationDeletionPolicy)
+	return field.ErrorList{field.NotSupported(path, value, []string{string(DeletionPolicyDelete), string(DeletionPolicyRetain)})}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IamGroupSpec defines the desired state of IamGroup. IAM groups can't be
+// tagged, so unlike the other kinds there are no tags
+type IamGroupSpec struct {
+	PolicyRefs []corev1.ObjectReference `json:"policyRefs,omitempty"`
+	// PolicyArns are existing managed policies to attach to the group, such
+	// as AWS managed policies or policies that aren't managed by an IamPolicy
+	PolicyArns []string `json:"policyArns,omitempty"`
+	// Members are the IamUsers in the group. Users that aren't listed are
+	// removed from the upstream group
+	Members []corev1.LocalObjectReference `json:"members,omitempty"`
+	// DeletionPolicy decides if the upstream group is deleted with the
+	// resource. Falls back to the deletion policy annotation and then the
+	// controller default
+	//+optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// IamGroupStatus defines the observed state of IamGroup
+type IamGroupStatus struct {
+	Arn string `json:"arn,omitempty"`
+	// Members are the IamUsers that are in the upstream group
+	Members []corev1.LocalObjectReference `json:"members,omitempty"`
+
+	ConditionedStatus `json:",inline"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="ARN",type="string",JSONPath=".status.arn"
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+//+kubebuilder:printcolumn:name="Synced",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+
+// IamGroup is the Schema for the iamgroups API
+type IamGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   IamGroupSpec   `json:"spec,omitempty"`
+	Status IamGroupStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// IamGroupList contains a list of IamGroup
+type IamGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IamGroup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&IamGroup{}, &IamGroupList{})
+}
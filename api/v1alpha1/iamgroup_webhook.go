@@ -0,0 +1,79 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var iamgrouplog = logf.Log.WithName("iamgroup-resource")
+
+func (r *IamGroup) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-aws-jackhoman-com-v1alpha1-iamgroup,mutating=false,failurePolicy=fail,sideEffects=None,groups=aws.jackhoman.com,resources=iamgroups,verbs=create;update,versions=v1alpha1,name=viamgroup.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &IamGroup{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *IamGroup) ValidateCreate() error {
+	iamgrouplog.Info("validate create", "name", r.Name)
+	return r.validate()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *IamGroup) ValidateUpdate(old runtime.Object) error {
+	iamgrouplog.Info("validate update", "name", r.Name)
+	return r.validate()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *IamGroup) ValidateDelete() error {
+	iamgrouplog.Info("validate delete", "name", r.Name)
+	return nil
+}
+
+func (r *IamGroup) validate() error {
+	path := field.NewPath("spec")
+	errs := validatePolicyArns(path.Child("policyArns"), r.Spec.PolicyArns)
+	members := make(map[string]struct{}, len(r.Spec.Members))
+	for k, member := range r.Spec.Members {
+		path := path.Child("members").Index(k).Child("name")
+		if len(member.Name) == 0 {
+			errs = append(errs, field.Required(path, "member name is required"))
+			continue
+		}
+		if _, ok := members[member.Name]; ok {
+			errs = append(errs, field.Duplicate(path, member.Name))
+		}
+		members[member.Name] = struct{}{}
+	}
+	errs = append(errs, validateDeletionPolicyAnnotation(r)...)
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind(KindIamGroup).GroupKind(), r.Name, errs)
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IamInstanceProfileSpec defines the desired state of IamInstanceProfile
+type IamInstanceProfileSpec struct {
+	// RoleRef is the IamRole in the instance profile. The role is allowed
+	// to be assumed by ec2.amazonaws.com while it's referenced
+	RoleRef corev1.LocalObjectReference `json:"roleRef"`
+	// Tags are added to the upstream instance profile along with any tags
+	// the controller adds automatically
+	Tags map[string]string `json:"tags,omitempty"`
+	// DeletionPolicy decides if the upstream instance profile is deleted with
+	// the resource. Falls back to the deletion policy annotation and then the
+	// controller default
+	//+optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// IamInstanceProfileStatus defines the observed state of IamInstanceProfile
+type IamInstanceProfileStatus struct {
+	Arn string `json:"arn,omitempty"`
+	// RoleArn is the arn of the role in the upstream instance profile
+	RoleArn string `json:"roleArn,omitempty"`
+
+	// ManagedTags are the keys of the spec tags the controller added
+	ManagedTags []string `json:"managedTags,omitempty"`
+
+	ConditionedStatus `json:",inline"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="ARN",type="string",JSONPath=".status.arn"
+//+kubebuilder:printcolumn:name="Role",type="string",JSONPath=".spec.roleRef.name"
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+//+kubebuilder:printcolumn:name="Synced",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+
+// IamInstanceProfile is the Schema for the iaminstanceprofiles API
+type IamInstanceProfile struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   IamInstanceProfileSpec   `json:"spec,omitempty"`
+	Status IamInstanceProfileStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// IamInstanceProfileList contains a list of IamInstanceProfile
+type IamInstanceProfileList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IamInstanceProfile `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&IamInstanceProfile{}, &IamInstanceProfileList{})
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var iaminstanceprofilelog = logf.Log.WithName("iaminstanceprofile-resource")
+
+func (r *IamInstanceProfile) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-aws-jackhoman-com-v1alpha1-iaminstanceprofile,mutating=false,failurePolicy=fail,sideEffects=None,groups=aws.jackhoman.com,resources=iaminstanceprofiles,verbs=create;update,versions=v1alpha1,name=viaminstanceprofile.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &IamInstanceProfile{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *IamInstanceProfile) ValidateCreate() error {
+	iaminstanceprofilelog.Info("validate create", "name", r.Name)
+	return r.validate()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *IamInstanceProfile) ValidateUpdate(old runtime.Object) error {
+	iaminstanceprofilelog.Info("validate update", "name", r.Name)
+	return r.validate()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *IamInstanceProfile) ValidateDelete() error {
+	iaminstanceprofilelog.Info("validate delete", "name", r.Name)
+	return nil
+}
+
+func (r *IamInstanceProfile) validate() error {
+	var errs field.ErrorList
+	if len(r.Spec.RoleRef.Name) == 0 {
+		errs = append(errs, field.Required(field.NewPath("spec", "roleRef", "name"), "role name is required"))
+	}
+	errs = append(errs, validateDeletionPolicyAnnotation(r)...)
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind(KindIamInstanceProfile).GroupKind(), r.Name, errs)
+}
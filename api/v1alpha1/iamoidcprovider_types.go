@@ -0,0 +1,95 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultOIDCClientID is the audience of service account tokens used to
+// assume roles
+const DefaultOIDCClientID = "sts.amazonaws.com"
+
+// IamOIDCProviderSpec defines the desired state of IamOIDCProvider
+type IamOIDCProviderSpec struct {
+	// URL is the issuer url of the cluster, e.g.
+	// https://oidc.eks.us-east-1.amazonaws.com/id/EXAMPLED539D4633E53DE1B716D3041E.
+	// It can't be changed
+	URL string `json:"url"`
+	// ClientIDs are the audiences of the provider. Defaults to
+	// sts.amazonaws.com
+	ClientIDs []string `json:"clientIds,omitempty"`
+	// Thumbprints are the sha1 fingerprints of the certificates of the
+	// issuer. At most 5 are allowed. IAM fetches them when they're empty
+	Thumbprints []string `json:"thumbprints,omitempty"`
+	// Tags are added to the upstream provider along with any tags the
+	// controller adds automatically
+	Tags map[string]string `json:"tags,omitempty"`
+	// DeletionPolicy decides if the upstream provider is deleted with the
+	// resource. Falls back to the deletion policy annotation and then the
+	// controller default
+	//+optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// IamOIDCProviderStatus defines the observed state of IamOIDCProvider
+type IamOIDCProviderStatus struct {
+	Arn string `json:"arn,omitempty"`
+
+	// ManagedTags are the keys of the spec tags the controller added
+	ManagedTags []string `json:"managedTags,omitempty"`
+
+	ConditionedStatus `json:",inline"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="ARN",type="string",JSONPath=".status.arn"
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+//+kubebuilder:printcolumn:name="Synced",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+
+// IamOIDCProvider is the Schema for the iamoidcproviders API
+type IamOIDCProvider struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   IamOIDCProviderSpec   `json:"spec,omitempty"`
+	Status IamOIDCProviderStatus `json:"status,omitempty"`
+}
+
+// GetClientIDs returns the client ids of the provider or the default
+// client id when there are none
+func (in *IamOIDCProvider) GetClientIDs() []string {
+	if len(in.Spec.ClientIDs) == 0 {
+		return []string{DefaultOIDCClientID}
+	}
+	return in.Spec.ClientIDs
+}
+
+//+kubebuilder:object:root=true
+
+// IamOIDCProviderList contains a list of IamOIDCProvider
+type IamOIDCProviderList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IamOIDCProvider `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&IamOIDCProvider{}, &IamOIDCProviderList{})
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/hex"
+	"net/url"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+const (
+	// maxThumbprints is the number of thumbprints IAM allows on a provider
+	maxThumbprints = 5
+	// thumbprintLength is the length of a hex encoded sha1 fingerprint
+	thumbprintLength = 40
+)
+
+// log is for logging in this package.
+var iamoidcproviderlog = logf.Log.WithName("iamoidcprovider-resource")
+
+func (r *IamOIDCProvider) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-aws-jackhoman-com-v1alpha1-iamoidcprovider,mutating=false,failurePolicy=fail,sideEffects=None,groups=aws.jackhoman.com,resources=iamoidcproviders,verbs=create;update,versions=v1alpha1,name=viamoidcprovider.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &IamOIDCProvider{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *IamOIDCProvider) ValidateCreate() error {
+	iamoidcproviderlog.Info("validate create", "name", r.Name)
+	return r.validate(nil)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *IamOIDCProvider) ValidateUpdate(old runtime.Object) error {
+	iamoidcproviderlog.Info("validate update", "name", r.Name)
+	return r.validate(old.(*IamOIDCProvider))
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *IamOIDCProvider) ValidateDelete() error {
+	iamoidcproviderlog.Info("validate delete", "name", r.Name)
+	return nil
+}
+
+// validate checks the spec. The url is part of the upstream arn, so it
+// can't change once the provider is created
+func (r *IamOIDCProvider) validate(old *IamOIDCProvider) error {
+	var errs field.ErrorList
+	path := field.NewPath("spec")
+
+	if parsed, err := url.Parse(r.Spec.URL); err != nil || parsed.Scheme != "https" || len(parsed.Host) == 0 {
+		errs = append(errs, field.Invalid(path.Child("url"), r.Spec.URL, "must be an https url"))
+	}
+	if old != nil && old.Spec.URL != r.Spec.URL {
+		errs = append(errs, field.Forbidden(path.Child("url"), "url can't be changed"))
+	}
+	clientIDs := make(map[string]struct{}, len(r.Spec.ClientIDs))
+	for k, clientID := range r.Spec.ClientIDs {
+		path := path.Child("clientIds").Index(k)
+		if len(clientID) == 0 {
+			errs = append(errs, field.Required(path, "client id can't be empty"))
+			continue
+		}
+		if _, ok := clientIDs[clientID]; ok {
+			errs = append(errs, field.Duplicate(path, clientID))
+		}
+		clientIDs[clientID] = struct{}{}
+	}
+	if len(r.Spec.Thumbprints) > maxThumbprints {
+		errs = append(errs, field.TooMany(path.Child("thumbprints"), len(r.Spec.Thumbprints), maxThumbprints))
+	}
+	for k, thumbprint := range r.Spec.Thumbprints {
+		if _, err := hex.DecodeString(thumbprint); err != nil || len(thumbprint) != thumbprintLength {
+			errs = append(errs, field.Invalid(path.Child("thumbprints").Index(k), thumbprint, "must be a hex encoded sha1 fingerprint"))
+		}
+	}
+	errs = append(errs, validateDeletionPolicyAnnotation(r)...)
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind(KindIamOIDCProvider).GroupKind(), r.Name, errs)
+}
@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -26,9 +27,30 @@ const (
 	PolicyStatementEffectDeny  = "Deny"
 )
 
+//+kubebuilder:validation:Enum=ForAllValues;ForAnyValue
+
+// ConditionQualifier is a set operator that applies a condition operator to
+// a multivalued condition key
+type ConditionQualifier string
+
+const (
+	// ConditionQualifierForAllValues matches when every value in the request
+	// matches one of the condition values
+	ConditionQualifierForAllValues ConditionQualifier = "ForAllValues"
+	// ConditionQualifierForAnyValue matches when at least one value in the
+	// request matches one of the condition values
+	ConditionQualifierForAnyValue ConditionQualifier = "ForAnyValue"
+)
+
 type Condition struct {
-	Key    string   `json:"key"`
-	Values []string `json:"values"`
+	Key string `json:"key"`
+	// Values are strings, numbers or booleans, e.g. false for
+	// aws:SecureTransport
+	Values []apiextensionsv1.JSON `json:"values"`
+	// Qualifier applies the operator to each value of a multivalued key,
+	// e.g. ForAllValues:StringEquals
+	// +optional
+	Qualifier ConditionQualifier `json:"qualifier,omitempty"`
 }
 
 type Conditions struct {
@@ -83,25 +105,112 @@ type Conditions struct {
 	StringNotEqualsIgnoreCaseIfExists []Condition `json:"stringNotEqualsIgnoreCaseIfExists,omitempty"`
 }
 
+// Principal is the principal element of a policy statement. Use an aws
+// principal of "*" to match every principal
+type Principal struct {
+	AWS           []string `json:"aws,omitempty"`
+	Service       []string `json:"service,omitempty"`
+	Federated     []string `json:"federated,omitempty"`
+	CanonicalUser []string `json:"canonicalUser,omitempty"`
+}
+
 type Statement struct {
 	Sid string `json:"sid,omitempty"`
 	// +kubebuilder:validation:Enum=Allow;Deny
-	Effect     string      `json:"effect"`
-	Actions    []string    `json:"action,omitempty"` // this can also be a string
-	Resources  []string    `json:"resource"`
-	Conditions *Conditions `json:"Condition,omitempty"`
+	Effect string `json:"effect"`
+	// Principal and NotPrincipal are only valid in resource based
+	// policies such as trust policies
+	Principal    *Principal `json:"principal,omitempty"`
+	NotPrincipal *Principal `json:"notPrincipal,omitempty"`
+	// Only one of Actions and NotActions may be set
+	Actions    []string `json:"action,omitempty"` // this can also be a string
+	NotActions []string `json:"notAction,omitempty"`
+	// Only one of Resources and NotResources may be set
+	Resources    []string    `json:"resource,omitempty"`
+	NotResources []string    `json:"notResource,omitempty"`
+	Conditions   *Conditions `json:"Condition,omitempty"`
 }
 
 type IamPolicyDocument struct {
+	// Version of the policy language. Defaults to 2012-10-17
+	// +kubebuilder:validation:Enum="2012-10-17";"2008-10-17"
 	Version    string      `json:"version,omitempty"`
 	Statements []Statement `json:"statement"`
 }
 
+// DocumentSource selects the key of a ConfigMap or Secret that holds a
+// policy document. Only one of ConfigMapKeyRef and SecretKeyRef may be set
+type DocumentSource struct {
+	ConfigMapKeyRef *DocumentKeySelector `json:"configMapKeyRef,omitempty"`
+	SecretKeyRef    *DocumentKeySelector `json:"secretKeyRef,omitempty"`
+}
+
+type DocumentKeySelector struct {
+	Name string `json:"name"`
+	// Namespace of the ConfigMap or Secret. Required for an IamPolicy. A
+	// NamespacedIamPolicy can only read from its own namespace
+	//+optional
+	Namespace string `json:"namespace,omitempty"`
+	Key       string `json:"key"`
+}
+
+// PolicyTemplateReference instantiates an IamPolicyTemplate
+type PolicyTemplateReference struct {
+	// Name of the IamPolicyTemplate
+	Name string `json:"name"`
+	// Values of the template parameters by parameter name
+	//+optional
+	Values map[string]string `json:"values,omitempty"`
+}
+
 // IamPolicySpec defines the desired state of IamPolicy
 type IamPolicySpec struct {
-	// Document - Iam policy document
-	Description string            `json:"description,omitempty"`
-	Document    IamPolicyDocument `json:"document"`
+	Description string `json:"description,omitempty"`
+	// Document - Iam policy document. Only one of Document, RawDocument,
+	// DocumentFrom and Template may be set
+	//+optional
+	Document *IamPolicyDocument `json:"document,omitempty"`
+	// RawDocument is an iam policy document in JSON for policies that are
+	// easier to copy than translate, or that use elements Document doesn't
+	// support
+	//+optional
+	RawDocument string `json:"rawDocument,omitempty"`
+	// DocumentFrom reads a raw policy document from a ConfigMap or Secret
+	// key. The policy is updated when the content changes
+	//+optional
+	DocumentFrom *DocumentSource `json:"documentFrom,omitempty"`
+	// Template renders the document from an IamPolicyTemplate
+	//+optional
+	Template *PolicyTemplateReference `json:"template,omitempty"`
+	// Tags are added to the upstream policy along with any tags the
+	// controller adds automatically
+	Tags map[string]string `json:"tags,omitempty"`
+	// DeletionPolicy decides if the upstream policy is deleted with the
+	// resource. Falls back to the deletion policy annotation and then the
+	// controller default
+	//+optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+	// VersionLimit is the number of versions of the upstream policy to keep
+	// for rolling back, including the default version. Falls back to the
+	// controller default
+	//+kubebuilder:validation:Minimum=1
+	//+kubebuilder:validation:Maximum=5
+	//+optional
+	VersionLimit *int32 `json:"versionLimit,omitempty"`
+	// PinnedVersion sets a previous version of the upstream policy, e.g. v3,
+	// as the default version to roll back a change. Changes to the document
+	// aren't applied until the version is unpinned
+	//+kubebuilder:validation:Pattern=`^v[1-9][0-9]*$`
+	//+optional
+	PinnedVersion string `json:"pinnedVersion,omitempty"`
+}
+
+// PolicyVersion is a version of the upstream policy
+type PolicyVersion struct {
+	VersionId  string      `json:"versionId"`
+	CreateDate metav1.Time `json:"createDate,omitempty"`
+	// Default is true for the version in effect
+	Default bool `json:"default,omitempty"`
 }
 
 // IamPolicyStatus defines the observed state of IamPolicy
@@ -109,11 +218,27 @@ type IamPolicyStatus struct {
 	Md5Sum        string                   `json:"md5,omitempty"`
 	Arn           string                   `json:"arn,omitempty"`
 	AttachedRoles []corev1.ObjectReference `json:"attachedRoles,omitempty"`
+	// Shards are the arns of the additional managed policies holding the
+	// statements that don't fit in the policy at Arn. Roles attach and
+	// detach them together with the policy
+	Shards []string `json:"shards,omitempty"`
+	// RetiredShards are the arns of the shards left over from a larger
+	// document. They're deleted once the roles have detached them
+	RetiredShards []string `json:"retiredShards,omitempty"`
+	// Versions are the versions of the upstream policy, newest first
+	Versions []PolicyVersion `json:"versions,omitempty"`
+
+	// ManagedTags are the keys of the spec tags the controller added
+	ManagedTags []string `json:"managedTags,omitempty"`
+
+	ConditionedStatus `json:",inline"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+//+kubebuilder:printcolumn:name="Synced",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
 
 // IamPolicy is the Schema for the iampolicies API
 type IamPolicy struct {
@@ -0,0 +1,134 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/iampolicy"
+)
+
+// log is for logging in this package.
+var iampolicylog = logf.Log.WithName("iampolicy-resource")
+
+func (r *IamPolicy) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-aws-jackhoman-com-v1alpha1-iampolicy,mutating=false,failurePolicy=fail,sideEffects=None,groups=aws.jackhoman.com,resources=iampolicies,verbs=create;update,versions=v1alpha1,name=viampolicy.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &IamPolicy{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *IamPolicy) ValidateCreate() error {
+	iampolicylog.Info("validate create", "name", r.Name)
+	return r.validate()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *IamPolicy) ValidateUpdate(old runtime.Object) error {
+	iampolicylog.Info("validate update", "name", r.Name)
+	return r.validate()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *IamPolicy) ValidateDelete() error {
+	iampolicylog.Info("validate delete", "name", r.Name)
+	return nil
+}
+
+func (r *IamPolicy) validate() error {
+	errs := validateIamPolicySpec(field.NewPath("spec"), &r.Spec, "")
+	errs = append(errs, validateDeletionPolicyAnnotation(r)...)
+	errs = append(errs, validateAdoptAnnotation(r, "policy")...)
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind(KindIamPolicy).GroupKind(), r.Name, errs)
+}
+
+// validateIamPolicySpec validates the spec shared by IamPolicy and
+// NamespacedIamPolicy. namespace is empty for cluster scoped policies
+func validateIamPolicySpec(path *field.Path, spec *IamPolicySpec, namespace string) field.ErrorList {
+	var errs field.ErrorList
+	count := 0
+	if spec.Document != nil {
+		count++
+	}
+	if len(spec.RawDocument) > 0 {
+		count++
+	}
+	if spec.DocumentFrom != nil {
+		count++
+	}
+	if spec.Template != nil {
+		count++
+	}
+	if count > 1 {
+		errs = append(errs, field.Forbidden(path, "only one of document, rawDocument, documentFrom or template may be specified"))
+	}
+	if count == 0 {
+		errs = append(errs, field.Required(path, "one of document, rawDocument, documentFrom or template must be specified"))
+	}
+	if len(spec.RawDocument) > 0 {
+		if err := iampolicy.ValidateDocument(spec.RawDocument); err != nil {
+			errs = append(errs, field.Invalid(path.Child("rawDocument"), spec.RawDocument, err.Error()))
+		}
+	}
+	if spec.DocumentFrom != nil {
+		errs = append(errs, validateDocumentSource(path.Child("documentFrom"), spec.DocumentFrom, namespace)...)
+	}
+	return errs
+}
+
+func validateDocumentSource(path *field.Path, source *DocumentSource, namespace string) field.ErrorList {
+	var errs field.ErrorList
+	if source.ConfigMapKeyRef != nil && source.SecretKeyRef != nil {
+		errs = append(errs, field.Forbidden(path, "only one of configMapKeyRef or secretKeyRef may be specified"))
+	}
+	if source.ConfigMapKeyRef == nil && source.SecretKeyRef == nil {
+		errs = append(errs, field.Required(path, "one of configMapKeyRef or secretKeyRef must be specified"))
+	}
+	selectors := []struct {
+		name     string
+		selector *DocumentKeySelector
+	}{
+		{name: "configMapKeyRef", selector: source.ConfigMapKeyRef},
+		{name: "secretKeyRef", selector: source.SecretKeyRef},
+	}
+	for _, item := range selectors {
+		selector := item.selector
+		if selector == nil {
+			continue
+		}
+		path := path.Child(item.name, "namespace")
+		if len(namespace) == 0 && len(selector.Namespace) == 0 {
+			errs = append(errs, field.Required(path, "namespace is required for cluster scoped policies"))
+		}
+		if len(namespace) > 0 && len(selector.Namespace) > 0 && selector.Namespace != namespace {
+			errs = append(errs, field.Forbidden(path, "must be the namespace of the policy"))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//+kubebuilder:validation:Enum=String;Number;Boolean
+
+// ParameterType is the type of the values a template parameter accepts
+type ParameterType string
+
+const (
+	ParameterTypeString  ParameterType = "String"
+	ParameterTypeNumber  ParameterType = "Number"
+	ParameterTypeBoolean ParameterType = "Boolean"
+)
+
+type TemplateParameter struct {
+	// Name is used to reference the parameter in the document as ${name}
+	//+kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_]*$`
+	Name string `json:"name"`
+	// Type of the parameter. Values are checked against the type when the
+	// template is rendered
+	//+kubebuilder:default=String
+	//+optional
+	Type        ParameterType `json:"type,omitempty"`
+	Description string        `json:"description,omitempty"`
+	// Default is used when a policy doesn't set a value. Parameters without
+	// a default are required
+	//+optional
+	Default *string `json:"default,omitempty"`
+}
+
+// IamPolicyTemplateSpec defines the desired state of IamPolicyTemplate
+type IamPolicyTemplateSpec struct {
+	Parameters []TemplateParameter `json:"parameters,omitempty"`
+	// Document is rendered by replacing ${name} with the value of the
+	// parameter or built in variable. IAM policy variables such as
+	// ${aws:username} are left as is and $${ escapes a literal ${
+	Document IamPolicyDocument `json:"document"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+
+// IamPolicyTemplate is a policy document with parameters that IamPolicies
+// and NamespacedIamPolicies can instantiate
+type IamPolicyTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec IamPolicyTemplateSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// IamPolicyTemplateList contains a list of IamPolicyTemplate
+type IamPolicyTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IamPolicyTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&IamPolicyTemplate{}, &IamPolicyTemplateList{})
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"strconv"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var iampolicytemplatelog = logf.Log.WithName("iampolicytemplate-resource")
+
+func (r *IamPolicyTemplate) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-aws-jackhoman-com-v1alpha1-iampolicytemplate,mutating=false,failurePolicy=fail,sideEffects=None,groups=aws.jackhoman.com,resources=iampolicytemplates,verbs=create;update,versions=v1alpha1,name=viampolicytemplate.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &IamPolicyTemplate{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *IamPolicyTemplate) ValidateCreate() error {
+	iampolicytemplatelog.Info("validate create", "name", r.Name)
+	return r.validate()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *IamPolicyTemplate) ValidateUpdate(old runtime.Object) error {
+	iampolicytemplatelog.Info("validate update", "name", r.Name)
+	return r.validate()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *IamPolicyTemplate) ValidateDelete() error {
+	iampolicytemplatelog.Info("validate delete", "name", r.Name)
+	return nil
+}
+
+func (r *IamPolicyTemplate) validate() error {
+	var errs field.ErrorList
+	names := make(map[string]struct{}, len(r.Spec.Parameters))
+	for k, parameter := range r.Spec.Parameters {
+		path := field.NewPath("spec", "parameters").Index(k)
+		if _, ok := names[parameter.Name]; ok {
+			errs = append(errs, field.Duplicate(path.Child("name"), parameter.Name))
+		}
+		names[parameter.Name] = struct{}{}
+		if parameter.Default != nil {
+			if err := ValidateParameterValue(&parameter, *parameter.Default); err != nil {
+				errs = append(errs, field.Invalid(path.Child("default"), *parameter.Default, err.Error()))
+			}
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind(KindIamPolicyTemplate).GroupKind(), r.Name, errs)
+}
+
+// ValidateParameterValue returns an error if value isn't of the parameter
+// type
+func ValidateParameterValue(parameter *TemplateParameter, value string) error {
+	switch parameter.Type {
+	case ParameterTypeNumber:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("parameter %s must be a number", parameter.Name)
+		}
+	case ParameterTypeBoolean:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("parameter %s must be a boolean", parameter.Name)
+		}
+	}
+	return nil
+}
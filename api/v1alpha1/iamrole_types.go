@@ -24,6 +24,47 @@ import (
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
+// InlinePolicy is a policy document embedded directly in an IamRole
+type InlinePolicy struct {
+	// Name is the name of the inline policy on the upstream role
+	Name     string            `json:"name"`
+	Document IamPolicyDocument `json:"document"`
+}
+
+// PermissionsBoundary references the managed policy used to set the maximum
+// permissions of a role. Only one of Arn or PolicyRef may be set
+type PermissionsBoundary struct {
+	// Arn of an existing managed policy
+	Arn string `json:"arn,omitempty"`
+	// PolicyRef references an IamPolicy whose ARN is used as the boundary
+	PolicyRef *corev1.LocalObjectReference `json:"policyRef,omitempty"`
+}
+
+// TrustConditions restrict when the AWS principals trusted by a role can
+// assume it
+type TrustConditions struct {
+	// ExternalID must be passed by the principal when it assumes the role
+	ExternalID string `json:"externalId,omitempty"`
+	// RequireMFA only allows principals that authenticated with MFA
+	RequireMFA bool `json:"requireMfa,omitempty"`
+	// SourceIdentity is the source identity the principal has to set when it
+	// assumes the role. It may contain * and ? wildcards
+	SourceIdentity string `json:"sourceIdentity,omitempty"`
+}
+
+// IamRoleTrust lists principals outside the cluster that can assume a role.
+// Service account bindings are trusted separately
+type IamRoleTrust struct {
+	// AWS are account ids, or arns of accounts, roles and users that can
+	// assume the role
+	AWS []string `json:"aws,omitempty"`
+	// Services are service principals that can assume the role, such as
+	// lambda.amazonaws.com
+	Services []string `json:"services,omitempty"`
+	// Conditions apply to the AWS principals
+	Conditions *TrustConditions `json:"conditions,omitempty"`
+}
+
 // IamRoleSpec defines the desired state of IamRole
 type IamRoleSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
@@ -33,6 +74,26 @@ type IamRoleSpec struct {
 	Description        string                   `json:"description,omitempty"`
 	MaxDurationSeconds int                      `json:"maxDurationSeconds,omitempty"`
 	PolicyRefs         []corev1.ObjectReference `json:"policyRefs,omitempty"`
+	// PolicyArns are existing managed policies to attach to the role, such as
+	// AWS managed policies or policies that aren't managed by an IamPolicy
+	PolicyArns []string `json:"policyArns,omitempty"`
+	// InlinePolicies are policies embedded in the role. Inline policies
+	// that are not declared here are removed from the upstream role
+	InlinePolicies []InlinePolicy `json:"inlinePolicies,omitempty"`
+	// PermissionsBoundary sets the maximum permissions of the role. When unset
+	// the controller default is used, if one is configured
+	PermissionsBoundary *PermissionsBoundary `json:"permissionsBoundary,omitempty"`
+	// Trust allows AWS accounts, roles and services to assume the role, in
+	// addition to the bound service accounts
+	Trust *IamRoleTrust `json:"trust,omitempty"`
+	// Tags are added to the upstream role along with any tags the
+	// controller adds automatically
+	Tags map[string]string `json:"tags,omitempty"`
+	// DeletionPolicy decides if the upstream role is deleted with the
+	// resource. Falls back to the deletion policy annotation and then the
+	// controller default
+	//+optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
 }
 
 // IamRoleStatus defines the observed state of IamRole
@@ -41,12 +102,24 @@ type IamRoleStatus struct {
 	// Important: Run "make" to regenerate code after modifying this file
 	RoleArn              string                   `json:"arn,omitempty"`
 	BoundServiceAccounts []corev1.ObjectReference `json:"boundServiceAccounts,omitempty"`
+
+	// ManagedTags are the keys of the spec tags the controller added
+	ManagedTags []string `json:"managedTags,omitempty"`
+
+	// DenyAllAWSRemoved is set when the controller removed the DenyAllAWS
+	// statement from the trust policy, so it's only restored on roles that
+	// had it
+	DenyAllAWSRemoved bool `json:"denyAllAWSRemoved,omitempty"`
+
+	ConditionedStatus `json:",inline"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:resource:scope=Cluster
 //+kubebuilder:printcolumn:name="ARN",type="string",JSONPath=".status.arn"
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+//+kubebuilder:printcolumn:name="Synced",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
 
 // IamRole is the Schema for the iamroles API
 type IamRole struct {
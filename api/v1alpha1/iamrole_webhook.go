@@ -17,7 +17,13 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -32,6 +38,10 @@ func (r *IamRole) SetupWebhookWithManager(mgr ctrl.Manager) error {
 		Complete()
 }
 
+// DefaultMaxDurationSeconds is the max session duration for roles that
+// don't specify one. It's the same as the AWS default
+const DefaultMaxDurationSeconds = 3600
+
 //+kubebuilder:webhook:path=/mutate-aws-jackhoman-com-v1alpha1-iamrole,mutating=true,failurePolicy=fail,sideEffects=None,groups=aws.jackhoman.com,resources=iamroles,verbs=create;update,versions=v1alpha1,name=miamrole.kb.io,admissionReviewVersions=v1
 
 var _ webhook.Defaulter = &IamRole{}
@@ -41,7 +51,7 @@ func (r *IamRole) Default() {
 	iamrolelog.Info("default", "name", r.Name)
 
 	if r.Spec.MaxDurationSeconds == 0 {
-		r.Spec.MaxDurationSeconds = 3600
+		r.Spec.MaxDurationSeconds = DefaultMaxDurationSeconds
 	}
 }
 
@@ -52,13 +62,13 @@ var _ webhook.Validator = &IamRole{}
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
 func (r *IamRole) ValidateCreate() error {
 	iamrolelog.Info("validate create", "name", r.Name)
-	return nil
+	return r.validate()
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *IamRole) ValidateUpdate(old runtime.Object) error {
 	iamrolelog.Info("validate update", "name", r.Name)
-	return nil
+	return r.validate()
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -66,3 +76,133 @@ func (r *IamRole) ValidateDelete() error {
 	iamrolelog.Info("validate delete", "name", r.Name)
 	return nil
 }
+
+func (r *IamRole) validate() error {
+	errs := validateIamRoleSpec(field.NewPath("spec"), &r.Spec)
+	errs = append(errs, validateDeletionPolicyAnnotation(r)...)
+	errs = append(errs, validateAdoptAnnotation(r, "role")...)
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind(KindIamRole).GroupKind(), r.Name, errs)
+}
+
+// validateIamRoleSpec validates the spec shared by IamRole and
+// NamespacedIamRole
+func validateIamRoleSpec(path *field.Path, spec *IamRoleSpec) field.ErrorList {
+	var errs field.ErrorList
+	if boundary := spec.PermissionsBoundary; boundary != nil {
+		path := path.Child("permissionsBoundary")
+		if len(boundary.Arn) > 0 && boundary.PolicyRef != nil {
+			errs = append(errs, field.Forbidden(path, "only one of arn or policyRef may be specified"))
+		}
+		if len(boundary.Arn) == 0 && boundary.PolicyRef == nil {
+			errs = append(errs, field.Required(path, "one of arn or policyRef must be specified"))
+		}
+	}
+	errs = append(errs, validatePolicyArns(path.Child("policyArns"), spec.PolicyArns)...)
+	names := make(map[string]struct{}, len(spec.InlinePolicies))
+	for k, policy := range spec.InlinePolicies {
+		if _, ok := names[policy.Name]; ok {
+			errs = append(errs, field.Duplicate(path.Child("inlinePolicies").Index(k).Child("name"), policy.Name))
+		}
+		names[policy.Name] = struct{}{}
+	}
+	if spec.Trust != nil {
+		errs = append(errs, validateTrust(path.Child("trust"), spec.Trust)...)
+	}
+	return errs
+}
+
+var (
+	accountIDPattern  = regexp.MustCompile(`^[0-9]{12}$`)
+	externalIDPattern = regexp.MustCompile(`^[\w+=,.@:/-]+$`)
+)
+
+// validateTrust validates the principals and conditions a role trusts
+func validateTrust(path *field.Path, trust *IamRoleTrust) field.ErrorList {
+	var errs field.ErrorList
+	principals := make(map[string]struct{}, len(trust.AWS))
+	for k, value := range trust.AWS {
+		path := path.Child("aws").Index(k)
+		if _, ok := principals[value]; ok {
+			errs = append(errs, field.Duplicate(path, value))
+		}
+		principals[value] = struct{}{}
+		if !isAWSPrincipal(value) {
+			errs = append(errs, field.Invalid(path, value, "must be an account id or the arn of an account, role or user"))
+		}
+	}
+	services := make(map[string]struct{}, len(trust.Services))
+	for k, value := range trust.Services {
+		path := path.Child("services").Index(k)
+		if _, ok := services[value]; ok {
+			errs = append(errs, field.Duplicate(path, value))
+		}
+		services[value] = struct{}{}
+		if !strings.HasSuffix(value, ".amazonaws.com") || len(value) == len(".amazonaws.com") {
+			errs = append(errs, field.Invalid(path, value, "must be a service principal, e.g. lambda.amazonaws.com"))
+		}
+	}
+	if conditions := trust.Conditions; conditions != nil {
+		path := path.Child("conditions")
+		if len(trust.AWS) == 0 {
+			errs = append(errs, field.Forbidden(path, "conditions only apply to aws principals"))
+		}
+		if id := conditions.ExternalID; len(id) > 0 && (len(id) < 2 || len(id) > 1224 || !externalIDPattern.MatchString(id)) {
+			errs = append(errs, field.Invalid(path.Child("externalId"), conditions.ExternalID,
+				"must be 2 to 1224 letters, numbers or any of +=,.@:/-"))
+		}
+	}
+	return errs
+}
+
+// isAWSPrincipal returns true if value is an account id or the arn of an
+// account root, role or user. Wildcards aren't allowed, so a role can't be
+// trusted by every account
+func isAWSPrincipal(value string) bool {
+	if accountIDPattern.MatchString(value) {
+		return true
+	}
+	out, err := arn.Parse(value)
+	if err != nil || out.Service != "iam" || !accountIDPattern.MatchString(out.AccountID) || strings.Contains(value, "*") {
+		return false
+	}
+	if out.Resource == "root" {
+		return true
+	}
+	for _, prefix := range []string{"role/", "user/"} {
+		if strings.HasPrefix(out.Resource, prefix) && len(out.Resource) > len(prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// validatePolicyArns validates that policy arns are unique managed
+// policy arns
+func validatePolicyArns(path *field.Path, values []string) field.ErrorList {
+	var errs field.ErrorList
+	arns := make(map[string]struct{}, len(values))
+	for k, value := range values {
+		path := path.Index(k)
+		if _, ok := arns[value]; ok {
+			errs = append(errs, field.Duplicate(path, value))
+		}
+		arns[value] = struct{}{}
+		if !isPolicyArn(value) {
+			errs = append(errs, field.Invalid(path, value, "must be an iam policy arn"))
+		}
+	}
+	return errs
+}
+
+// isPolicyArn returns true if value is the arn of an iam managed policy,
+// e.g. arn:aws:iam::aws:policy/ReadOnlyAccess
+func isPolicyArn(value string) bool {
+	out, err := arn.Parse(value)
+	if err != nil {
+		return false
+	}
+	return out.Service == "iam" && strings.HasPrefix(out.Resource, "policy/") && len(out.Resource) > len("policy/")
+}
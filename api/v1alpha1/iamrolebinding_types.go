@@ -21,20 +21,45 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// IamRoleReference references an IamRole, or a NamespacedIamRole in the same
+// namespace as the binding
+type IamRoleReference struct {
+	// Kind of the referenced role. Defaults to IamRole
+	//+kubebuilder:validation:Enum=IamRole;NamespacedIamRole
+	//+kubebuilder:default=IamRole
+	//+optional
+	Kind string `json:"kind,omitempty"`
+	Name string `json:"name"`
+}
+
 type IamRoleBindingSpec struct {
-	IamRoleRef        corev1.LocalObjectReference `json:"iamRoleRef"`
-	ServiceAccountRef corev1.LocalObjectReference `json:"serviceAccountRef"`
+	IamRoleRef IamRoleReference `json:"iamRoleRef"`
+	// ServiceAccountRef binds a single service account. Exactly one of
+	// ServiceAccountRef and ServiceAccountSelector must be set
+	//+optional
+	ServiceAccountRef corev1.LocalObjectReference `json:"serviceAccountRef,omitempty"`
+	// ServiceAccountSelector binds every service account in the namespace
+	// matching the selector
+	//+optional
+	ServiceAccountSelector *metav1.LabelSelector `json:"serviceAccountSelector,omitempty"`
 }
 
 type IamRoleBindingStatus struct {
 	BoundServiceAccountRef corev1.LocalObjectReference `json:"serviceAccount,omitempty"`
 	BoundIamRoleArn        string                      `json:"iamRoleArn,omitempty"`
+	// BoundServiceAccounts are the service accounts annotated with the
+	// role arn
+	BoundServiceAccounts []corev1.LocalObjectReference `json:"serviceAccounts,omitempty"`
+
+	ConditionedStatus `json:",inline"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:printcolumn:name="Role",type=string,JSONPath=`.spec.iamRoleRef`
 //+kubebuilder:printcolumn:name="ServiceAccount",type=string,JSONPath=`.spec.serviceAccountRef`
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+//+kubebuilder:printcolumn:name="Synced",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
 
 // IamRoleBinding is the Schema for the iamrolebindings API
 type IamRoleBinding struct {
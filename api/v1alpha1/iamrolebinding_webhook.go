@@ -17,7 +17,10 @@ limitations under the License.
 package v1alpha1
 
 import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -39,6 +42,10 @@ var _ webhook.Defaulter = &IamRoleBinding{}
 // Default implements webhook.Defaulter so a webhook will be registered for the type
 func (r *IamRoleBinding) Default() {
 	iamrolebindinglog.Info("default", "name", r.Name)
+
+	if len(r.Spec.IamRoleRef.Kind) == 0 {
+		r.Spec.IamRoleRef.Kind = KindIamRole
+	}
 }
 
 //+kubebuilder:webhook:path=/validate-aws-jackhoman-com-v1alpha1-iamrolebinding,mutating=false,failurePolicy=fail,sideEffects=None,groups=aws.jackhoman.com,resources=iamrolebindings,verbs=create;update,versions=v1alpha1,name=viamrolebinding.kb.io,admissionReviewVersions=v1
@@ -48,13 +55,13 @@ var _ webhook.Validator = &IamRoleBinding{}
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
 func (r *IamRoleBinding) ValidateCreate() error {
 	iamrolebindinglog.Info("validate create", "name", r.Name)
-	return nil
+	return r.validate()
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *IamRoleBinding) ValidateUpdate(old runtime.Object) error {
 	iamrolebindinglog.Info("validate update", "name", r.Name)
-	return nil
+	return r.validate()
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -62,3 +69,24 @@ func (r *IamRoleBinding) ValidateDelete() error {
 	iamrolebindinglog.Info("validate delete", "name", r.Name)
 	return nil
 }
+
+func (r *IamRoleBinding) validate() error {
+	var errs field.ErrorList
+	path := field.NewPath("spec")
+	selector := r.Spec.ServiceAccountSelector
+	if len(r.Spec.ServiceAccountRef.Name) > 0 && selector != nil {
+		errs = append(errs, field.Forbidden(path, "only one of serviceAccountRef or serviceAccountSelector may be specified"))
+	}
+	if len(r.Spec.ServiceAccountRef.Name) == 0 && selector == nil {
+		errs = append(errs, field.Required(path, "one of serviceAccountRef or serviceAccountSelector must be specified"))
+	}
+	if selector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(selector); err != nil {
+			errs = append(errs, field.Invalid(path.Child("serviceAccountSelector"), selector, err.Error()))
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind(KindIamRoleBinding).GroupKind(), r.Name, errs)
+}
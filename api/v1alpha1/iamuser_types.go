@@ -0,0 +1,126 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// AccessKeyIdKey and SecretAccessKeyKey are the keys in the access key
+	// secret. They match the environment variables read by the AWS SDKs
+	AccessKeyIdKey     = "AWS_ACCESS_KEY_ID"
+	SecretAccessKeyKey = "AWS_SECRET_ACCESS_KEY"
+
+	// DefaultAccessKeyOverlap is how long a replaced access key stays
+	// active when the user doesn't set an overlap period
+	DefaultAccessKeyOverlap = 24 * time.Hour
+)
+
+// SecretReference references a secret in any namespace
+type SecretReference struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// AccessKeySpec configures the access key the controller creates for a user
+type AccessKeySpec struct {
+	// SecretRef is the secret the access key is written to. The secret is
+	// created and owned by the IamUser
+	SecretRef SecretReference `json:"secretRef"`
+	// RotationPeriod is how long an access key is used before it's replaced
+	// with a new one. Keys aren't rotated when it's unset
+	//+optional
+	RotationPeriod *metav1.Duration `json:"rotationPeriod,omitempty"`
+	// OverlapPeriod is how long the previous access key stays active after
+	// it's replaced, giving clients time to pick up the new key. It must be
+	// shorter than the rotation period. Defaults to 24h
+	//+optional
+	OverlapPeriod *metav1.Duration `json:"overlapPeriod,omitempty"`
+}
+
+// IamUserSpec defines the desired state of IamUser
+type IamUserSpec struct {
+	PolicyRefs []corev1.ObjectReference `json:"policyRefs,omitempty"`
+	// PolicyArns are existing managed policies to attach to the user, such
+	// as AWS managed policies or policies that aren't managed by an IamPolicy
+	PolicyArns []string `json:"policyArns,omitempty"`
+	// AccessKey creates an access key for the user. Access keys created by
+	// the controller are deleted when it's unset
+	//+optional
+	AccessKey *AccessKeySpec `json:"accessKey,omitempty"`
+	// Tags are added to the upstream user along with any tags the
+	// controller adds automatically
+	Tags map[string]string `json:"tags,omitempty"`
+	// DeletionPolicy decides if the upstream user is deleted with the
+	// resource. Falls back to the deletion policy annotation and then the
+	// controller default
+	//+optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// AccessKeyStatus describes an access key of the user
+type AccessKeyStatus struct {
+	AccessKeyId string      `json:"accessKeyId"`
+	CreateDate  metav1.Time `json:"createDate"`
+}
+
+// IamUserStatus defines the observed state of IamUser
+type IamUserStatus struct {
+	Arn string `json:"arn,omitempty"`
+	// AccessKeys are the user's access keys, newest first. The newest key
+	// is the one in the secret
+	AccessKeys []AccessKeyStatus `json:"accessKeys,omitempty"`
+
+	// ManagedTags are the keys of the spec tags the controller added
+	ManagedTags []string `json:"managedTags,omitempty"`
+
+	ConditionedStatus `json:",inline"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="ARN",type="string",JSONPath=".status.arn"
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+//+kubebuilder:printcolumn:name="Synced",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+
+// IamUser is the Schema for the iamusers API. Users are meant for clients
+// that can't assume a role, so prefer an IamRole where possible
+type IamUser struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   IamUserSpec   `json:"spec,omitempty"`
+	Status IamUserStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// IamUserList contains a list of IamUser
+type IamUserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IamUser `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&IamUser{}, &IamUserList{})
+}
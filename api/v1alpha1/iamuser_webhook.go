@@ -0,0 +1,83 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var iamuserlog = logf.Log.WithName("iamuser-resource")
+
+func (r *IamUser) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-aws-jackhoman-com-v1alpha1-iamuser,mutating=false,failurePolicy=fail,sideEffects=None,groups=aws.jackhoman.com,resources=iamusers,verbs=create;update,versions=v1alpha1,name=viamuser.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &IamUser{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *IamUser) ValidateCreate() error {
+	iamuserlog.Info("validate create", "name", r.Name)
+	return r.validate()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *IamUser) ValidateUpdate(old runtime.Object) error {
+	iamuserlog.Info("validate update", "name", r.Name)
+	return r.validate()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *IamUser) ValidateDelete() error {
+	iamuserlog.Info("validate delete", "name", r.Name)
+	return nil
+}
+
+func (r *IamUser) validate() error {
+	path := field.NewPath("spec")
+	errs := validatePolicyArns(path.Child("policyArns"), r.Spec.PolicyArns)
+	if key := r.Spec.AccessKey; key != nil {
+		path := path.Child("accessKey")
+		if key.RotationPeriod != nil && key.RotationPeriod.Duration <= 0 {
+			errs = append(errs, field.Invalid(path.Child("rotationPeriod"), key.RotationPeriod.Duration.String(), "must be positive"))
+		}
+		if key.OverlapPeriod != nil && key.OverlapPeriod.Duration < 0 {
+			errs = append(errs, field.Invalid(path.Child("overlapPeriod"), key.OverlapPeriod.Duration.String(), "must not be negative"))
+		}
+		overlap := DefaultAccessKeyOverlap
+		if key.OverlapPeriod != nil {
+			overlap = key.OverlapPeriod.Duration
+		}
+		if key.RotationPeriod != nil && overlap >= key.RotationPeriod.Duration {
+			errs = append(errs, field.Invalid(path.Child("overlapPeriod"), overlap.String(), "must be shorter than the rotation period"))
+		}
+	}
+	errs = append(errs, validateDeletionPolicyAnnotation(r)...)
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind(KindIamUser).GroupKind(), r.Name, errs)
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var namespacediampolicylog = logf.Log.WithName("namespacediampolicy-resource")
+
+func (r *NamespacedIamPolicy) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-aws-jackhoman-com-v1alpha1-namespacediampolicy,mutating=false,failurePolicy=fail,sideEffects=None,groups=aws.jackhoman.com,resources=namespacediampolicies,verbs=create;update,versions=v1alpha1,name=vnamespacediampolicy.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &NamespacedIamPolicy{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *NamespacedIamPolicy) ValidateCreate() error {
+	namespacediampolicylog.Info("validate create", "name", r.Name, "namespace", r.Namespace)
+	return r.validate()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *NamespacedIamPolicy) ValidateUpdate(old runtime.Object) error {
+	namespacediampolicylog.Info("validate update", "name", r.Name, "namespace", r.Namespace)
+	return r.validate()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *NamespacedIamPolicy) ValidateDelete() error {
+	namespacediampolicylog.Info("validate delete", "name", r.Name, "namespace", r.Namespace)
+	return nil
+}
+
+func (r *NamespacedIamPolicy) validate() error {
+	errs := validateIamPolicySpec(field.NewPath("spec"), &r.Spec, r.Namespace)
+	errs = append(errs, validateDeletionPolicyAnnotation(r)...)
+	errs = append(errs, validateAdoptAnnotation(r, "policy")...)
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind(KindNamespacedIamPolicy).GroupKind(), r.Name, errs)
+}
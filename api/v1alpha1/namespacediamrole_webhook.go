@@ -0,0 +1,104 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// MaxRoleNameLength is the longest name iam allows for a role
+const MaxRoleNameLength = 64
+
+// log is for logging in this package.
+var namespacediamrolelog = logf.Log.WithName("namespacediamrole-resource")
+
+func (r *NamespacedIamRole) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-aws-jackhoman-com-v1alpha1-namespacediamrole,mutating=true,failurePolicy=fail,sideEffects=None,groups=aws.jackhoman.com,resources=namespacediamroles,verbs=create;update,versions=v1alpha1,name=mnamespacediamrole.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Defaulter = &NamespacedIamRole{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type
+func (r *NamespacedIamRole) Default() {
+	namespacediamrolelog.Info("default", "name", r.Name, "namespace", r.Namespace)
+
+	if r.Spec.MaxDurationSeconds == 0 {
+		r.Spec.MaxDurationSeconds = DefaultMaxDurationSeconds
+	}
+}
+
+//+kubebuilder:webhook:path=/validate-aws-jackhoman-com-v1alpha1-namespacediamrole,mutating=false,failurePolicy=fail,sideEffects=None,groups=aws.jackhoman.com,resources=namespacediamroles,verbs=create;update,versions=v1alpha1,name=vnamespacediamrole.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &NamespacedIamRole{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *NamespacedIamRole) ValidateCreate() error {
+	namespacediamrolelog.Info("validate create", "name", r.Name, "namespace", r.Namespace)
+	return r.validate()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *NamespacedIamRole) ValidateUpdate(old runtime.Object) error {
+	namespacediamrolelog.Info("validate update", "name", r.Name, "namespace", r.Namespace)
+	return r.validate()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *NamespacedIamRole) ValidateDelete() error {
+	namespacediamrolelog.Info("validate delete", "name", r.Name, "namespace", r.Namespace)
+	return nil
+}
+
+func (r *NamespacedIamRole) validate() error {
+	errs := validateIamRoleSpec(field.NewPath("spec"), &r.Spec)
+	// Namespaced roles always use the default permissions boundary and can
+	// only attach policies from their own namespace so they can't grant more
+	// than the cluster admin allows
+	if r.Spec.PermissionsBoundary != nil {
+		errs = append(errs, field.Forbidden(field.NewPath("spec", "permissionsBoundary"),
+			"namespaced roles use the default permissions boundary"))
+	}
+	for k, value := range r.Spec.PolicyArns {
+		if !InNamespacePath(value, "policy", r.Namespace) {
+			errs = append(errs, field.Invalid(field.NewPath("spec", "policyArns").Index(k), value,
+				fmt.Sprintf("must be the arn of a policy in the path %s", NamespacePath(r.Namespace))))
+		}
+	}
+	errs = append(errs, validateDeletionPolicyAnnotation(r)...)
+	errs = append(errs, validateAdoptAnnotation(r, "role")...)
+	// Adopted roles keep their existing name
+	if name := UpstreamName(r); !metav1.HasAnnotation(r.ObjectMeta, AnnotationAdoptArn) && len(name) > MaxRoleNameLength {
+		errs = append(errs, field.Invalid(field.NewPath("metadata", "name"), r.Name,
+			fmt.Sprintf("iam role name %s is longer than %d characters", name, MaxRoleNameLength)))
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind(KindNamespacedIamRole).GroupKind(), r.Name, errs)
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	KindIamRole             = "IamRole"
+	KindNamespacedIamRole   = "NamespacedIamRole"
+	KindIamPolicy           = "IamPolicy"
+	KindNamespacedIamPolicy = "NamespacedIamPolicy"
+	KindIamRoleBinding      = "IamRoleBinding"
+	KindIamPolicyTemplate   = "IamPolicyTemplate"
+	KindIamUser             = "IamUser"
+	KindIamGroup            = "IamGroup"
+	KindIamInstanceProfile  = "IamInstanceProfile"
+	KindIamOIDCProvider     = "IamOIDCProvider"
+)
+
+// AnnotationAdoptArn is the arn of an existing iam role or policy to take
+// over instead of creating a new one
+const AnnotationAdoptArn = "aws.jackhoman.com/adopt-arn"
+
+//+kubebuilder:object:generate=false
+
+// IamRoleObject is implemented by IamRole and NamespacedIamRole
+type IamRoleObject interface {
+	client.Object
+	GetSpec() *IamRoleSpec
+	GetStatus() *IamRoleStatus
+}
+
+//+kubebuilder:object:generate=false
+
+// IamPolicyObject is implemented by IamPolicy and NamespacedIamPolicy
+type IamPolicyObject interface {
+	client.Object
+	GetSpec() *IamPolicySpec
+	GetStatus() *IamPolicyStatus
+}
+
+func (in *IamRole) GetSpec() *IamRoleSpec               { return &in.Spec }
+func (in *IamRole) GetStatus() *IamRoleStatus           { return &in.Status }
+func (in *NamespacedIamRole) GetSpec() *IamRoleSpec     { return &in.Spec }
+func (in *NamespacedIamRole) GetStatus() *IamRoleStatus { return &in.Status }
+
+func (in *IamPolicy) GetSpec() *IamPolicySpec               { return &in.Spec }
+func (in *IamPolicy) GetStatus() *IamPolicyStatus           { return &in.Status }
+func (in *NamespacedIamPolicy) GetSpec() *IamPolicySpec     { return &in.Spec }
+func (in *NamespacedIamPolicy) GetStatus() *IamPolicyStatus { return &in.Status }
+
+// UpstreamName returns the name of the iam resource managed by obj.
+// Namespaced resources are prefixed with the namespace so they can't
+// collide with resources in other namespaces or cluster scoped resources.
+// Resource names can't contain a '_' so the prefix is unambiguous
+func UpstreamName(obj client.Object) string {
+	if len(obj.GetNamespace()) == 0 {
+		return obj.GetName()
+	}
+	return obj.GetNamespace() + "_" + obj.GetName()
+}
+
+// ResourcePath is the path prefix the controller creates iam resources
+// under. It's set from --resource-default-path
+var ResourcePath string
+
+// NamespacePath returns the iam path of the resources created for the
+// namespace
+func NamespacePath(namespace string) string {
+	return "/" + ResourcePath + "/" + namespace + "/"
+}
+
+// InNamespacePath returns true if value is the arn of an iam resource of
+// the given type, such as role or policy, in the namespace path
+func InNamespacePath(value string, resource string, namespace string) bool {
+	parsed, err := arn.Parse(value)
+	if err != nil || parsed.Service != "iam" {
+		return false
+	}
+	prefix := resource + NamespacePath(namespace)
+	return strings.HasPrefix(parsed.Resource, prefix) && !strings.Contains(parsed.Resource[len(prefix):], "/")
+}
+
+// validateAdoptAnnotation validates that the adopt annotation, if it's set,
+// is the arn of an iam resource of the given type such as role or policy.
+// Namespaced resources can only adopt resources in the namespace path so
+// tenants can't take over resources they don't own
+func validateAdoptAnnotation(obj metav1.Object, resource string) field.ErrorList {
+	value, ok := obj.GetAnnotations()[AnnotationAdoptArn]
+	if !ok {
+		return nil
+	}
+	path := field.NewPath("metadata", "annotations").Key(AnnotationAdoptArn)
+	if len(obj.GetNamespace()) > 0 {
+		if InNamespacePath(value, resource, obj.GetNamespace()) {
+			return nil
+		}
+		return field.ErrorList{field.Invalid(path, value,
+			"must be the arn of an iam "+resource+" in the path "+NamespacePath(obj.GetNamespace()))}
+	}
+	parsed, err := arn.Parse(value)
+	if err == nil && parsed.Service == "iam" && strings.HasPrefix(parsed.Resource, resource+"/") {
+		return nil
+	}
+	return field.ErrorList{field.Invalid(path, value, "must be an iam "+resource+" arn")}
+}
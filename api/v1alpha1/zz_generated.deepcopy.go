@@ -22,17 +22,63 @@ limitations under the License.
 package v1alpha1
 
 import (
-	"k8s.io/api/core/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccessKeySpec) DeepCopyInto(out *AccessKeySpec) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+	if in.RotationPeriod != nil {
+		in, out := &in.RotationPeriod, &out.RotationPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.OverlapPeriod != nil {
+		in, out := &in.OverlapPeriod, &out.OverlapPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccessKeySpec.
+func (in *AccessKeySpec) DeepCopy() *AccessKeySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AccessKeySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccessKeyStatus) DeepCopyInto(out *AccessKeyStatus) {
+	*out = *in
+	in.CreateDate.DeepCopyInto(&out.CreateDate)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccessKeyStatus.
+func (in *AccessKeyStatus) DeepCopy() *AccessKeyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AccessKeyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Condition) DeepCopyInto(out *Condition) {
 	*out = *in
 	if in.Values != nil {
 		in, out := &in.Values, &out.Values
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+		*out = make([]apiextensionsv1.JSON, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -46,6 +92,28 @@ func (in *Condition) DeepCopy() *Condition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConditionedStatus) DeepCopyInto(out *ConditionedStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConditionedStatus.
+func (in *ConditionedStatus) DeepCopy() *ConditionedStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ConditionedStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Conditions) DeepCopyInto(out *Conditions) {
 	*out = *in
@@ -405,80 +473,98 @@ func (in *Conditions) DeepCopy() *Conditions {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IamPolicy) DeepCopyInto(out *IamPolicy) {
+func (in *DocumentKeySelector) DeepCopyInto(out *DocumentKeySelector) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamPolicy.
-func (in *IamPolicy) DeepCopy() *IamPolicy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DocumentKeySelector.
+func (in *DocumentKeySelector) DeepCopy() *DocumentKeySelector {
 	if in == nil {
 		return nil
 	}
-	out := new(IamPolicy)
+	out := new(DocumentKeySelector)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *IamPolicy) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DocumentSource) DeepCopyInto(out *DocumentSource) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(DocumentKeySelector)
+		**out = **in
 	}
-	return nil
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(DocumentKeySelector)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DocumentSource.
+func (in *DocumentSource) DeepCopy() *DocumentSource {
+	if in == nil {
+		return nil
+	}
+	out := new(DocumentSource)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IamPolicyDocument) DeepCopyInto(out *IamPolicyDocument) {
+func (in *IamGroup) DeepCopyInto(out *IamGroup) {
 	*out = *in
-	if in.Statements != nil {
-		in, out := &in.Statements, &out.Statements
-		*out = make([]Statement, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamPolicyDocument.
-func (in *IamPolicyDocument) DeepCopy() *IamPolicyDocument {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamGroup.
+func (in *IamGroup) DeepCopy() *IamGroup {
 	if in == nil {
 		return nil
 	}
-	out := new(IamPolicyDocument)
+	out := new(IamGroup)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IamGroup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IamPolicyList) DeepCopyInto(out *IamPolicyList) {
+func (in *IamGroupList) DeepCopyInto(out *IamGroupList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]IamPolicy, len(*in))
+		*out = make([]IamGroup, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamPolicyList.
-func (in *IamPolicyList) DeepCopy() *IamPolicyList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamGroupList.
+func (in *IamGroupList) DeepCopy() *IamGroupList {
 	if in == nil {
 		return nil
 	}
-	out := new(IamPolicyList)
+	out := new(IamGroupList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *IamPolicyList) DeepCopyObject() runtime.Object {
+func (in *IamGroupList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -486,43 +572,58 @@ func (in *IamPolicyList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IamPolicySpec) DeepCopyInto(out *IamPolicySpec) {
+func (in *IamGroupSpec) DeepCopyInto(out *IamGroupSpec) {
 	*out = *in
-	in.Document.DeepCopyInto(&out.Document)
+	if in.PolicyRefs != nil {
+		in, out := &in.PolicyRefs, &out.PolicyRefs
+		*out = make([]corev1.ObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.PolicyArns != nil {
+		in, out := &in.PolicyArns, &out.PolicyArns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamPolicySpec.
-func (in *IamPolicySpec) DeepCopy() *IamPolicySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamGroupSpec.
+func (in *IamGroupSpec) DeepCopy() *IamGroupSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(IamPolicySpec)
+	out := new(IamGroupSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IamPolicyStatus) DeepCopyInto(out *IamPolicyStatus) {
+func (in *IamGroupStatus) DeepCopyInto(out *IamGroupStatus) {
 	*out = *in
-	if in.AttachedRoles != nil {
-		in, out := &in.AttachedRoles, &out.AttachedRoles
-		*out = make([]v1.ObjectReference, len(*in))
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]corev1.LocalObjectReference, len(*in))
 		copy(*out, *in)
 	}
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamPolicyStatus.
-func (in *IamPolicyStatus) DeepCopy() *IamPolicyStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamGroupStatus.
+func (in *IamGroupStatus) DeepCopy() *IamGroupStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(IamPolicyStatus)
+	out := new(IamGroupStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IamRole) DeepCopyInto(out *IamRole) {
+func (in *IamInstanceProfile) DeepCopyInto(out *IamInstanceProfile) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -530,18 +631,18 @@ func (in *IamRole) DeepCopyInto(out *IamRole) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamRole.
-func (in *IamRole) DeepCopy() *IamRole {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamInstanceProfile.
+func (in *IamInstanceProfile) DeepCopy() *IamInstanceProfile {
 	if in == nil {
 		return nil
 	}
-	out := new(IamRole)
+	out := new(IamInstanceProfile)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *IamRole) DeepCopyObject() runtime.Object {
+func (in *IamInstanceProfile) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -549,26 +650,31 @@ func (in *IamRole) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IamRoleBinding) DeepCopyInto(out *IamRoleBinding) {
+func (in *IamInstanceProfileList) DeepCopyInto(out *IamInstanceProfileList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]IamInstanceProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamRoleBinding.
-func (in *IamRoleBinding) DeepCopy() *IamRoleBinding {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamInstanceProfileList.
+func (in *IamInstanceProfileList) DeepCopy() *IamInstanceProfileList {
 	if in == nil {
 		return nil
 	}
-	out := new(IamRoleBinding)
+	out := new(IamInstanceProfileList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *IamRoleBinding) DeepCopyObject() runtime.Object {
+func (in *IamInstanceProfileList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -576,96 +682,102 @@ func (in *IamRoleBinding) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IamRoleBindingList) DeepCopyInto(out *IamRoleBindingList) {
+func (in *IamInstanceProfileSpec) DeepCopyInto(out *IamInstanceProfileSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]IamRoleBinding, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	out.RoleRef = in.RoleRef
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamRoleBindingList.
-func (in *IamRoleBindingList) DeepCopy() *IamRoleBindingList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamInstanceProfileSpec.
+func (in *IamInstanceProfileSpec) DeepCopy() *IamInstanceProfileSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(IamRoleBindingList)
+	out := new(IamInstanceProfileSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *IamRoleBindingList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IamRoleBindingSpec) DeepCopyInto(out *IamRoleBindingSpec) {
+func (in *IamInstanceProfileStatus) DeepCopyInto(out *IamInstanceProfileStatus) {
 	*out = *in
-	out.IamRoleRef = in.IamRoleRef
-	out.ServiceAccountRef = in.ServiceAccountRef
+	if in.ManagedTags != nil {
+		in, out := &in.ManagedTags, &out.ManagedTags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamRoleBindingSpec.
-func (in *IamRoleBindingSpec) DeepCopy() *IamRoleBindingSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamInstanceProfileStatus.
+func (in *IamInstanceProfileStatus) DeepCopy() *IamInstanceProfileStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(IamRoleBindingSpec)
+	out := new(IamInstanceProfileStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IamRoleBindingStatus) DeepCopyInto(out *IamRoleBindingStatus) {
+func (in *IamOIDCProvider) DeepCopyInto(out *IamOIDCProvider) {
 	*out = *in
-	out.BoundServiceAccountRef = in.BoundServiceAccountRef
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamRoleBindingStatus.
-func (in *IamRoleBindingStatus) DeepCopy() *IamRoleBindingStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamOIDCProvider.
+func (in *IamOIDCProvider) DeepCopy() *IamOIDCProvider {
 	if in == nil {
 		return nil
 	}
-	out := new(IamRoleBindingStatus)
+	out := new(IamOIDCProvider)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IamOIDCProvider) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IamRoleList) DeepCopyInto(out *IamRoleList) {
+func (in *IamOIDCProviderList) DeepCopyInto(out *IamOIDCProviderList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]IamRole, len(*in))
+		*out = make([]IamOIDCProvider, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamRoleList.
-func (in *IamRoleList) DeepCopy() *IamRoleList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamOIDCProviderList.
+func (in *IamOIDCProviderList) DeepCopy() *IamOIDCProviderList {
 	if in == nil {
 		return nil
 	}
-	out := new(IamRoleList)
+	out := new(IamOIDCProviderList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *IamRoleList) DeepCopyObject() runtime.Object {
+func (in *IamOIDCProviderList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -673,71 +785,1034 @@ func (in *IamRoleList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IamRoleSpec) DeepCopyInto(out *IamRoleSpec) {
+func (in *IamOIDCProviderSpec) DeepCopyInto(out *IamOIDCProviderSpec) {
 	*out = *in
-	if in.PolicyRefs != nil {
-		in, out := &in.PolicyRefs, &out.PolicyRefs
-		*out = make([]v1.ObjectReference, len(*in))
+	if in.ClientIDs != nil {
+		in, out := &in.ClientIDs, &out.ClientIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Thumbprints != nil {
+		in, out := &in.Thumbprints, &out.Thumbprints
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamRoleSpec.
-func (in *IamRoleSpec) DeepCopy() *IamRoleSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamOIDCProviderSpec.
+func (in *IamOIDCProviderSpec) DeepCopy() *IamOIDCProviderSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(IamRoleSpec)
+	out := new(IamOIDCProviderSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IamRoleStatus) DeepCopyInto(out *IamRoleStatus) {
+func (in *IamOIDCProviderStatus) DeepCopyInto(out *IamOIDCProviderStatus) {
 	*out = *in
-	if in.BoundServiceAccounts != nil {
-		in, out := &in.BoundServiceAccounts, &out.BoundServiceAccounts
-		*out = make([]v1.ObjectReference, len(*in))
+	if in.ManagedTags != nil {
+		in, out := &in.ManagedTags, &out.ManagedTags
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamRoleStatus.
-func (in *IamRoleStatus) DeepCopy() *IamRoleStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamOIDCProviderStatus.
+func (in *IamOIDCProviderStatus) DeepCopy() *IamOIDCProviderStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(IamRoleStatus)
+	out := new(IamOIDCProviderStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Statement) DeepCopyInto(out *Statement) {
+func (in *IamPolicy) DeepCopyInto(out *IamPolicy) {
 	*out = *in
-	if in.Actions != nil {
-		in, out := &in.Actions, &out.Actions
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamPolicy.
+func (in *IamPolicy) DeepCopy() *IamPolicy {
+	if in == nil {
+		return nil
 	}
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	out := new(IamPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IamPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = new(Conditions)
-		(*in).DeepCopyInto(*out)
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IamPolicyDocument) DeepCopyInto(out *IamPolicyDocument) {
+	*out = *in
+	if in.Statements != nil {
+		in, out := &in.Statements, &out.Statements
+		*out = make([]Statement, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Statement.
-func (in *Statement) DeepCopy() *Statement {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamPolicyDocument.
+func (in *IamPolicyDocument) DeepCopy() *IamPolicyDocument {
 	if in == nil {
 		return nil
 	}
-	out := new(Statement)
+	out := new(IamPolicyDocument)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IamPolicyList) DeepCopyInto(out *IamPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]IamPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamPolicyList.
+func (in *IamPolicyList) DeepCopy() *IamPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(IamPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IamPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IamPolicySpec) DeepCopyInto(out *IamPolicySpec) {
+	*out = *in
+	if in.Document != nil {
+		in, out := &in.Document, &out.Document
+		*out = new(IamPolicyDocument)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DocumentFrom != nil {
+		in, out := &in.DocumentFrom, &out.DocumentFrom
+		*out = new(DocumentSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(PolicyTemplateReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.VersionLimit != nil {
+		in, out := &in.VersionLimit, &out.VersionLimit
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamPolicySpec.
+func (in *IamPolicySpec) DeepCopy() *IamPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IamPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IamPolicyStatus) DeepCopyInto(out *IamPolicyStatus) {
+	*out = *in
+	if in.AttachedRoles != nil {
+		in, out := &in.AttachedRoles, &out.AttachedRoles
+		*out = make([]corev1.ObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Shards != nil {
+		in, out := &in.Shards, &out.Shards
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RetiredShards != nil {
+		in, out := &in.RetiredShards, &out.RetiredShards
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Versions != nil {
+		in, out := &in.Versions, &out.Versions
+		*out = make([]PolicyVersion, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ManagedTags != nil {
+		in, out := &in.ManagedTags, &out.ManagedTags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamPolicyStatus.
+func (in *IamPolicyStatus) DeepCopy() *IamPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IamPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IamPolicyTemplate) DeepCopyInto(out *IamPolicyTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamPolicyTemplate.
+func (in *IamPolicyTemplate) DeepCopy() *IamPolicyTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(IamPolicyTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IamPolicyTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IamPolicyTemplateList) DeepCopyInto(out *IamPolicyTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]IamPolicyTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamPolicyTemplateList.
+func (in *IamPolicyTemplateList) DeepCopy() *IamPolicyTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(IamPolicyTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IamPolicyTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IamPolicyTemplateSpec) DeepCopyInto(out *IamPolicyTemplateSpec) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]TemplateParameter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Document.DeepCopyInto(&out.Document)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamPolicyTemplateSpec.
+func (in *IamPolicyTemplateSpec) DeepCopy() *IamPolicyTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IamPolicyTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IamRole) DeepCopyInto(out *IamRole) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamRole.
+func (in *IamRole) DeepCopy() *IamRole {
+	if in == nil {
+		return nil
+	}
+	out := new(IamRole)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IamRole) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IamRoleBinding) DeepCopyInto(out *IamRoleBinding) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamRoleBinding.
+func (in *IamRoleBinding) DeepCopy() *IamRoleBinding {
+	if in == nil {
+		return nil
+	}
+	out := new(IamRoleBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IamRoleBinding) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IamRoleBindingList) DeepCopyInto(out *IamRoleBindingList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]IamRoleBinding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamRoleBindingList.
+func (in *IamRoleBindingList) DeepCopy() *IamRoleBindingList {
+	if in == nil {
+		return nil
+	}
+	out := new(IamRoleBindingList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IamRoleBindingList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IamRoleBindingSpec) DeepCopyInto(out *IamRoleBindingSpec) {
+	*out = *in
+	out.IamRoleRef = in.IamRoleRef
+	out.ServiceAccountRef = in.ServiceAccountRef
+	if in.ServiceAccountSelector != nil {
+		in, out := &in.ServiceAccountSelector, &out.ServiceAccountSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamRoleBindingSpec.
+func (in *IamRoleBindingSpec) DeepCopy() *IamRoleBindingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IamRoleBindingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IamRoleBindingStatus) DeepCopyInto(out *IamRoleBindingStatus) {
+	*out = *in
+	out.BoundServiceAccountRef = in.BoundServiceAccountRef
+	if in.BoundServiceAccounts != nil {
+		in, out := &in.BoundServiceAccounts, &out.BoundServiceAccounts
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamRoleBindingStatus.
+func (in *IamRoleBindingStatus) DeepCopy() *IamRoleBindingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IamRoleBindingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IamRoleList) DeepCopyInto(out *IamRoleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]IamRole, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamRoleList.
+func (in *IamRoleList) DeepCopy() *IamRoleList {
+	if in == nil {
+		return nil
+	}
+	out := new(IamRoleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IamRoleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IamRoleReference) DeepCopyInto(out *IamRoleReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamRoleReference.
+func (in *IamRoleReference) DeepCopy() *IamRoleReference {
+	if in == nil {
+		return nil
+	}
+	out := new(IamRoleReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IamRoleSpec) DeepCopyInto(out *IamRoleSpec) {
+	*out = *in
+	if in.PolicyRefs != nil {
+		in, out := &in.PolicyRefs, &out.PolicyRefs
+		*out = make([]corev1.ObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.PolicyArns != nil {
+		in, out := &in.PolicyArns, &out.PolicyArns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.InlinePolicies != nil {
+		in, out := &in.InlinePolicies, &out.InlinePolicies
+		*out = make([]InlinePolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PermissionsBoundary != nil {
+		in, out := &in.PermissionsBoundary, &out.PermissionsBoundary
+		*out = new(PermissionsBoundary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Trust != nil {
+		in, out := &in.Trust, &out.Trust
+		*out = new(IamRoleTrust)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamRoleSpec.
+func (in *IamRoleSpec) DeepCopy() *IamRoleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IamRoleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IamRoleStatus) DeepCopyInto(out *IamRoleStatus) {
+	*out = *in
+	if in.BoundServiceAccounts != nil {
+		in, out := &in.BoundServiceAccounts, &out.BoundServiceAccounts
+		*out = make([]corev1.ObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.ManagedTags != nil {
+		in, out := &in.ManagedTags, &out.ManagedTags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamRoleStatus.
+func (in *IamRoleStatus) DeepCopy() *IamRoleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IamRoleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IamRoleTrust) DeepCopyInto(out *IamRoleTrust) {
+	*out = *in
+	if in.AWS != nil {
+		in, out := &in.AWS, &out.AWS
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = new(TrustConditions)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamRoleTrust.
+func (in *IamRoleTrust) DeepCopy() *IamRoleTrust {
+	if in == nil {
+		return nil
+	}
+	out := new(IamRoleTrust)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IamUser) DeepCopyInto(out *IamUser) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamUser.
+func (in *IamUser) DeepCopy() *IamUser {
+	if in == nil {
+		return nil
+	}
+	out := new(IamUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IamUser) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IamUserList) DeepCopyInto(out *IamUserList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]IamUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamUserList.
+func (in *IamUserList) DeepCopy() *IamUserList {
+	if in == nil {
+		return nil
+	}
+	out := new(IamUserList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IamUserList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IamUserSpec) DeepCopyInto(out *IamUserSpec) {
+	*out = *in
+	if in.PolicyRefs != nil {
+		in, out := &in.PolicyRefs, &out.PolicyRefs
+		*out = make([]corev1.ObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.PolicyArns != nil {
+		in, out := &in.PolicyArns, &out.PolicyArns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AccessKey != nil {
+		in, out := &in.AccessKey, &out.AccessKey
+		*out = new(AccessKeySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamUserSpec.
+func (in *IamUserSpec) DeepCopy() *IamUserSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IamUserSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IamUserStatus) DeepCopyInto(out *IamUserStatus) {
+	*out = *in
+	if in.AccessKeys != nil {
+		in, out := &in.AccessKeys, &out.AccessKeys
+		*out = make([]AccessKeyStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ManagedTags != nil {
+		in, out := &in.ManagedTags, &out.ManagedTags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IamUserStatus.
+func (in *IamUserStatus) DeepCopy() *IamUserStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IamUserStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InlinePolicy) DeepCopyInto(out *InlinePolicy) {
+	*out = *in
+	in.Document.DeepCopyInto(&out.Document)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InlinePolicy.
+func (in *InlinePolicy) DeepCopy() *InlinePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(InlinePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacedIamPolicy) DeepCopyInto(out *NamespacedIamPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacedIamPolicy.
+func (in *NamespacedIamPolicy) DeepCopy() *NamespacedIamPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacedIamPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespacedIamPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacedIamPolicyList) DeepCopyInto(out *NamespacedIamPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NamespacedIamPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacedIamPolicyList.
+func (in *NamespacedIamPolicyList) DeepCopy() *NamespacedIamPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacedIamPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespacedIamPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacedIamRole) DeepCopyInto(out *NamespacedIamRole) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacedIamRole.
+func (in *NamespacedIamRole) DeepCopy() *NamespacedIamRole {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacedIamRole)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespacedIamRole) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacedIamRoleList) DeepCopyInto(out *NamespacedIamRoleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NamespacedIamRole, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacedIamRoleList.
+func (in *NamespacedIamRoleList) DeepCopy() *NamespacedIamRoleList {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacedIamRoleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespacedIamRoleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PermissionsBoundary) DeepCopyInto(out *PermissionsBoundary) {
+	*out = *in
+	if in.PolicyRef != nil {
+		in, out := &in.PolicyRef, &out.PolicyRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PermissionsBoundary.
+func (in *PermissionsBoundary) DeepCopy() *PermissionsBoundary {
+	if in == nil {
+		return nil
+	}
+	out := new(PermissionsBoundary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyTemplateReference) DeepCopyInto(out *PolicyTemplateReference) {
+	*out = *in
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyTemplateReference.
+func (in *PolicyTemplateReference) DeepCopy() *PolicyTemplateReference {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyTemplateReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyVersion) DeepCopyInto(out *PolicyVersion) {
+	*out = *in
+	in.CreateDate.DeepCopyInto(&out.CreateDate)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyVersion.
+func (in *PolicyVersion) DeepCopy() *PolicyVersion {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyVersion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Principal) DeepCopyInto(out *Principal) {
+	*out = *in
+	if in.AWS != nil {
+		in, out := &in.AWS, &out.AWS
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Federated != nil {
+		in, out := &in.Federated, &out.Federated
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CanonicalUser != nil {
+		in, out := &in.CanonicalUser, &out.CanonicalUser
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Principal.
+func (in *Principal) DeepCopy() *Principal {
+	if in == nil {
+		return nil
+	}
+	out := new(Principal)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretReference) DeepCopyInto(out *SecretReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretReference.
+func (in *SecretReference) DeepCopy() *SecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Statement) DeepCopyInto(out *Statement) {
+	*out = *in
+	if in.Principal != nil {
+		in, out := &in.Principal, &out.Principal
+		*out = new(Principal)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NotPrincipal != nil {
+		in, out := &in.NotPrincipal, &out.NotPrincipal
+		*out = new(Principal)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NotActions != nil {
+		in, out := &in.NotActions, &out.NotActions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NotResources != nil {
+		in, out := &in.NotResources, &out.NotResources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = new(Conditions)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Statement.
+func (in *Statement) DeepCopy() *Statement {
+	if in == nil {
+		return nil
+	}
+	out := new(Statement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateParameter) DeepCopyInto(out *TemplateParameter) {
+	*out = *in
+	if in.Default != nil {
+		in, out := &in.Default, &out.Default
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateParameter.
+func (in *TemplateParameter) DeepCopy() *TemplateParameter {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateParameter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrustConditions) DeepCopyInto(out *TrustConditions) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrustConditions.
+func (in *TrustConditions) DeepCopy() *TrustConditions {
+	if in == nil {
+		return nil
+	}
+	out := new(TrustConditions)
 	in.DeepCopyInto(out)
 	return out
 }
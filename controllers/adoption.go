@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/johnhoman/aws-iam-controller/api/v1alpha1"
+)
+
+// adoptArn returns the arn of the upstream resource the object adopts.
+// Namespaced objects only adopt resources in their namespace path
+func adoptArn(obj client.Object) (string, bool) {
+	value := obj.GetAnnotations()[v1alpha1.AnnotationAdoptArn]
+	if len(value) == 0 {
+		return "", false
+	}
+	if namespace := obj.GetNamespace(); len(namespace) > 0 &&
+		!v1alpha1.InNamespacePath(value, "role", namespace) && !v1alpha1.InNamespacePath(value, "policy", namespace) {
+		return "", false
+	}
+	return value, true
+}
+
+// upstreamRoleName returns the name of the upstream role. An adopted role
+// keeps its existing name
+func upstreamRoleName(obj client.Object) string {
+	if value, ok := adoptArn(obj); ok {
+		if parsed, err := arn.Parse(value); err == nil && strings.HasPrefix(parsed.Resource, "role/") {
+			return parsed.Resource[strings.LastIndex(parsed.Resource, "/")+1:]
+		}
+	}
+	return v1alpha1.UpstreamName(obj)
+}
+
+// canAdopt returns true if the object adopts the upstream resource and the
+// upstream resource isn't owned by another resource
+func canAdopt(obj client.Object, upstreamArn string, tags map[string]string) bool {
+	value, ok := adoptArn(obj)
+	if !ok || value != upstreamArn {
+		return false
+	}
+	_, owned := tags[TagKeyOwnerUID]
+	return !owned
+}
@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/johnhoman/aws-iam-controller/api/v1alpha1"
+	pkgaws "github.com/johnhoman/aws-iam-controller/pkg/aws"
+)
+
+// policyArns returns the arns of the referenced policies, including their
+// shards, together with the arns from the spec. Policies are referenced by
+// IamPolicy name, or by NamespacedIamPolicy name when the namespace is set.
+// The names of policies that don't exist or don't have an arn yet are
+// returned as missing
+func policyArns(
+	ctx context.Context,
+	c client.Client,
+	namespace string,
+	refs []corev1.ObjectReference,
+	arns []string,
+) (sets.String, []string, error) {
+	want := sets.NewString(arns...)
+	missing := make([]string, 0)
+	for _, ref := range refs {
+		var policy v1alpha1.IamPolicyObject = &v1alpha1.IamPolicy{}
+		if len(namespace) > 0 {
+			policy = &v1alpha1.NamespacedIamPolicy{}
+		}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, policy); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, nil, err
+			}
+			missing = append(missing, ref.Name)
+			continue
+		}
+		if len(policy.GetStatus().Arn) == 0 {
+			missing = append(missing, ref.Name)
+			continue
+		}
+		want.Insert(policy.GetStatus().Arn)
+		want.Insert(policy.GetStatus().Shards...)
+	}
+	return want, missing, nil
+}
+
+// policyNotFound returns the error for the policies that couldn't be
+// attached. Policies from the spec arns aren't watched, so the error is a
+// PolicyArnNotFoundError when any of them are missing
+func policyNotFound(missing []string, arns []string) error {
+	message := fmt.Sprintf("unable to attach policies %s", strings.Join(missing, ", "))
+	if sets.NewString(arns...).HasAny(missing...) {
+		return NewPolicyArnNotFound(message)
+	}
+	return NewPolicyNotFound(message)
+}
+
+// syncAttachments attaches the wanted policies that aren't attached and
+// detaches the attached policies that aren't wanted. The arns of wanted
+// policies that don't exist upstream are returned
+func syncAttachments(
+	recorder record.EventRecorder,
+	obj client.Object,
+	attached sets.String,
+	want sets.String,
+	attach func(arn string) error,
+	detach func(arn string) error,
+) ([]string, error) {
+	missing := make([]string, 0)
+	for _, arn := range want.Difference(attached).List() {
+		if err := attach(arn); err != nil {
+			if !pkgaws.IsNotFound(err) {
+				return nil, err
+			}
+			recorder.Eventf(obj, corev1.EventTypeNormal, "PolicyNotFound", "policy %s does not exist", arn)
+			missing = append(missing, arn)
+			continue
+		}
+		recorder.Eventf(obj, corev1.EventTypeNormal, "AttachedPolicy", "attached policy %s", arn)
+	}
+	for _, arn := range attached.Difference(want).List() {
+		if err := detach(arn); err != nil && !pkgaws.IsNotFound(err) {
+			return nil, err
+		}
+		recorder.Eventf(obj, corev1.EventTypeNormal, "DetachPolicy", "detached policy %s", arn)
+	}
+	return missing, nil
+}
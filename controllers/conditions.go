@@ -0,0 +1,157 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/johnhoman/aws-iam-controller/api/v1alpha1"
+	pkgaws "github.com/johnhoman/aws-iam-controller/pkg/aws"
+)
+
+// PolicyArnRequeueInterval is how long to wait before retrying a reconcile
+// when a policy from spec.policyArns doesn't exist
+const PolicyArnRequeueInterval = time.Minute
+
+// updateConditions sets the conditions and observed generation on the
+// status and patches the object status when anything changed
+func updateConditions(
+	ctx context.Context,
+	c client.Client,
+	obj client.Object,
+	status *v1alpha1.ConditionedStatus,
+	conditions ...metav1.Condition,
+) error {
+	changed := status.ObservedGeneration != obj.GetGeneration()
+	for _, condition := range conditions {
+		current := meta.FindStatusCondition(status.Conditions, condition.Type)
+		if current == nil ||
+			current.Status != condition.Status ||
+			current.Reason != condition.Reason ||
+			current.Message != condition.Message ||
+			current.ObservedGeneration != obj.GetGeneration() {
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	status.ObservedGeneration = obj.GetGeneration()
+	for _, condition := range conditions {
+		condition.ObservedGeneration = obj.GetGeneration()
+		meta.SetStatusCondition(&status.Conditions, condition)
+	}
+	return c.Status().Patch(ctx, obj, patch)
+}
+
+// readyCondition returns the Ready condition. The reason for an unavailable
+// resource comes from the reconcile error, if there is one
+func readyCondition(available bool, err error) metav1.Condition {
+	if available {
+		return metav1.Condition{
+			Type:   v1alpha1.ConditionTypeReady,
+			Status: metav1.ConditionTrue,
+			Reason: v1alpha1.ReasonAvailable,
+		}
+	}
+	condition := metav1.Condition{
+		Type:   v1alpha1.ConditionTypeReady,
+		Status: metav1.ConditionFalse,
+		Reason: v1alpha1.ReasonUnavailable,
+	}
+	if err != nil {
+		condition.Reason = reasonForError(err)
+		condition.Message = err.Error()
+	}
+	return condition
+}
+
+// syncedCondition returns the Synced condition for the result of a reconcile
+func syncedCondition(err error) metav1.Condition {
+	if err == nil {
+		return metav1.Condition{
+			Type:   v1alpha1.ConditionTypeSynced,
+			Status: metav1.ConditionTrue,
+			Reason: v1alpha1.ReasonReconciled,
+		}
+	}
+	return metav1.Condition{
+		Type:    v1alpha1.ConditionTypeSynced,
+		Status:  metav1.ConditionFalse,
+		Reason:  reasonForError(err),
+		Message: err.Error(),
+	}
+}
+
+func reasonForError(err error) string {
+	var conflict ConflictError
+	var policyNotFound PolicyNotFoundError
+	var policyArnNotFound PolicyArnNotFoundError
+	var roleNotFound RoleNotFoundError
+	var invalidRoleStatus InvalidRoleStatusError
+	var documentNotFound DocumentNotFoundError
+	var documentTooLarge DocumentTooLargeError
+	var versionNotFound VersionNotFoundError
+	var userNotFound UserNotFoundError
+	var boundaryRequired PermissionsBoundaryRequiredError
+	switch {
+	case errors.As(err, &conflict):
+		return v1alpha1.ReasonConflict
+	case errors.As(err, &policyNotFound), errors.As(err, &policyArnNotFound):
+		return v1alpha1.ReasonPolicyNotFound
+	case errors.As(err, &documentNotFound):
+		return v1alpha1.ReasonDocumentNotFound
+	case errors.As(err, &documentTooLarge):
+		return v1alpha1.ReasonDocumentTooLarge
+	case errors.As(err, &versionNotFound):
+		return v1alpha1.ReasonVersionNotFound
+	case errors.As(err, &userNotFound):
+		return v1alpha1.ReasonUserNotFound
+	case errors.As(err, &roleNotFound), errors.As(err, &invalidRoleStatus):
+		return v1alpha1.ReasonRoleNotFound
+	case errors.As(err, &boundaryRequired):
+		return v1alpha1.ReasonPermissionsBoundaryRequired
+	case pkgaws.IsAccessDenied(err):
+		return v1alpha1.ReasonAccessDenied
+	default:
+		return v1alpha1.ReasonReconcileError
+	}
+}
+
+// requeueError returns the error if the reconcile should be retried. Conflicts,
+// missing policies, documents, versions or users and documents that are too
+// large won't resolve until one of the watched resources changes, and a
+// missing default permissions boundary won't resolve until the controller
+// is restarted, so they aren't retried. Missing policies from spec.policyArns are retried after
+// requeueAfter instead
+func requeueError(err error) error {
+	var conflict ConflictError
+	var policyNotFound PolicyNotFoundError
+	var policyArnNotFound PolicyArnNotFoundError
+	var documentNotFound DocumentNotFoundError
+	var documentTooLarge DocumentTooLargeError
+	var versionNotFound VersionNotFoundError
+	var userNotFound UserNotFoundError
+	var boundaryRequired PermissionsBoundaryRequiredError
+	if errors.As(err, &conflict) || errors.As(err, &policyNotFound) || errors.As(err, &policyArnNotFound) ||
+		errors.As(err, &documentNotFound) || errors.As(err, &documentTooLarge) ||
+		errors.As(err, &versionNotFound) || errors.As(err, &userNotFound) || errors.As(err, &boundaryRequired) {
+		return nil
+	}
+	return err
+}
+
+// requeueAfter returns how long to wait before retrying a reconcile that
+// failed with an error that requeueError doesn't return
+func requeueAfter(err error) time.Duration {
+	var policyArnNotFound PolicyArnNotFoundError
+	if errors.As(err, &policyArnNotFound) {
+		return PolicyArnRequeueInterval
+	}
+	return 0
+}
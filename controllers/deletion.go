@@ -0,0 +1,31 @@
+package controllers
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/johnhoman/aws-iam-controller/api/v1alpha1"
+)
+
+// deletionPolicy returns the deletion policy of the resource. The spec takes
+// precedence over the annotation, and the fallback is used when neither is
+// set to a known policy
+func deletionPolicy(obj client.Object, policy v1alpha1.DeletionPolicy, fallback v1alpha1.DeletionPolicy) v1alpha1.DeletionPolicy {
+	if isDeletionPolicy(policy) {
+		return policy
+	}
+	if value, ok := obj.GetAnnotations()[v1alpha1.AnnotationDeletionPolicy]; ok && isDeletionPolicy(v1alpha1.DeletionPolicy(value)) {
+		return v1alpha1.DeletionPolicy(value)
+	}
+	if isDeletionPolicy(fallback) {
+		return fallback
+	}
+	return v1alpha1.DeletionPolicyDelete
+}
+
+func isDeletionPolicy(policy v1alpha1.DeletionPolicy) bool {
+	return policy == v1alpha1.DeletionPolicyDelete || policy == v1alpha1.DeletionPolicyRetain
+}
+
+// ownershipTagKeys are the tags removed from upstream resources that are
+// retained when the resource is deleted
+var ownershipTagKeys = []string{TagKeyOwnerCluster, TagKeyOwnerUID}
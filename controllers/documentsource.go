@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/johnhoman/aws-iam-controller/api/v1alpha1"
+)
+
+const (
+	documentConfigMapRefIndex = "spec.documentFrom.configMapKeyRef"
+	documentSecretRefIndex    = "spec.documentFrom.secretKeyRef"
+)
+
+// documentSourceKey returns the key of the object the selector references.
+// Namespaced policies can only reference objects in their own namespace
+func documentSourceKey(policy client.Object, selector *v1alpha1.DocumentKeySelector) types.NamespacedName {
+	namespace := selector.Namespace
+	if len(policy.GetNamespace()) > 0 {
+		namespace = policy.GetNamespace()
+	}
+	return types.NamespacedName{Namespace: namespace, Name: selector.Name}
+}
+
+// documentConfigMapRef indexes policies by the ConfigMap their document is
+// read from
+func documentConfigMapRef(obj client.Object) []string {
+	policy, ok := obj.(v1alpha1.IamPolicyObject)
+	if !ok || policy.GetSpec().DocumentFrom == nil || policy.GetSpec().DocumentFrom.ConfigMapKeyRef == nil {
+		return []string{}
+	}
+	return []string{documentSourceKey(obj, policy.GetSpec().DocumentFrom.ConfigMapKeyRef).String()}
+}
+
+// documentSecretRef indexes policies by the Secret their document is read
+// from
+func documentSecretRef(obj client.Object) []string {
+	policy, ok := obj.(v1alpha1.IamPolicyObject)
+	if !ok || policy.GetSpec().DocumentFrom == nil || policy.GetSpec().DocumentFrom.SecretKeyRef == nil {
+		return []string{}
+	}
+	return []string{documentSourceKey(obj, policy.GetSpec().DocumentFrom.SecretKeyRef).String()}
+}
+
+// indexedPolicyRequests returns a request for every policy whose index
+// matches value. policies is the list type of the policies to enqueue
+func indexedPolicyRequests(c client.Client, policies client.ObjectList, index string, value string) []ctrl.Request {
+	if err := c.List(context.Background(), policies, client.MatchingFields{index: value}); err != nil {
+		return []ctrl.Request{}
+	}
+	items, _ := meta.ExtractList(policies)
+	requests := make([]ctrl.Request, 0, len(items))
+	for _, item := range items {
+		policy := item.(client.Object)
+		requests = append(requests, ctrl.Request{
+			NamespacedName: types.NamespacedName{Namespace: policy.GetNamespace(), Name: policy.GetName()},
+		})
+	}
+	return requests
+}
+
+// documentSourceRequests returns a request for every policy that reads its
+// document from obj
+func documentSourceRequests(c client.Client, obj client.Object, index string, policies client.ObjectList) []ctrl.Request {
+	key := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+	return indexedPolicyRequests(c, policies, index, key.String())
+}
+
+// readDocumentSource returns the document in the ConfigMap or Secret key
+// referenced by the policy
+func readDocumentSource(ctx context.Context, c client.Client, policy v1alpha1.IamPolicyObject) (string, error) {
+	source := policy.GetSpec().DocumentFrom
+	if selector := source.ConfigMapKeyRef; selector != nil {
+		key := documentSourceKey(policy, selector)
+		configMap := &corev1.ConfigMap{}
+		if err := c.Get(ctx, key, configMap); err != nil {
+			if apierrors.IsNotFound(err) {
+				return "", NewDocumentNotFound(fmt.Sprintf("configmap %s does not exist", key))
+			}
+			return "", err
+		}
+		if value, ok := configMap.Data[selector.Key]; ok {
+			return value, nil
+		}
+		if value, ok := configMap.BinaryData[selector.Key]; ok {
+			return string(value), nil
+		}
+		return "", NewDocumentNotFound(fmt.Sprintf("configmap %s does not have key %s", key, selector.Key))
+	}
+	if selector := source.SecretKeyRef; selector != nil {
+		key := documentSourceKey(policy, selector)
+		secret := &corev1.Secret{}
+		if err := c.Get(ctx, key, secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				return "", NewDocumentNotFound(fmt.Sprintf("secret %s does not exist", key))
+			}
+			return "", err
+		}
+		if value, ok := secret.Data[selector.Key]; ok {
+			return string(value), nil
+		}
+		return "", NewDocumentNotFound(fmt.Sprintf("secret %s does not have key %s", key, selector.Key))
+	}
+	return "", fmt.Errorf("one of configMapKeyRef or secretKeyRef must be specified")
+}
@@ -10,6 +10,16 @@ func NewInvalidRoleStatus(message string) error {
 	return InvalidRoleStatusError(message)
 }
 
+type InvalidPolicyStatusError string
+
+func (err InvalidPolicyStatusError) Error() string {
+	return string(err)
+}
+
+func NewInvalidPolicyStatus(message string) error {
+	return InvalidPolicyStatusError(message)
+}
+
 type ConflictError string
 
 func (err ConflictError) Error() string {
@@ -19,3 +29,86 @@ func (err ConflictError) Error() string {
 func NewConflict(message string) error {
 	return ConflictError(message)
 }
+
+type PolicyNotFoundError string
+
+func (err PolicyNotFoundError) Error() string {
+	return string(err)
+}
+
+func NewPolicyNotFound(message string) error {
+	return PolicyNotFoundError(message)
+}
+
+// PolicyArnNotFoundError is a missing policy from spec.policyArns. Those
+// policies aren't watched, so the reconcile is retried after
+// PolicyArnRequeueInterval
+type PolicyArnNotFoundError string
+
+func (err PolicyArnNotFoundError) Error() string {
+	return string(err)
+}
+
+func NewPolicyArnNotFound(message string) error {
+	return PolicyArnNotFoundError(message)
+}
+
+type RoleNotFoundError string
+
+func (err RoleNotFoundError) Error() string {
+	return string(err)
+}
+
+func NewRoleNotFound(message string) error {
+	return RoleNotFoundError(message)
+}
+
+type DocumentNotFoundError string
+
+func (err DocumentNotFoundError) Error() string {
+	return string(err)
+}
+
+func NewDocumentNotFound(message string) error {
+	return DocumentNotFoundError(message)
+}
+
+type DocumentTooLargeError string
+
+func (err DocumentTooLargeError) Error() string {
+	return string(err)
+}
+
+func NewDocumentTooLarge(message string) error {
+	return DocumentTooLargeError(message)
+}
+
+type VersionNotFoundError string
+
+func (err VersionNotFoundError) Error() string {
+	return string(err)
+}
+
+func NewVersionNotFound(message string) error {
+	return VersionNotFoundError(message)
+}
+
+type UserNotFoundError string
+
+func (err UserNotFoundError) Error() string {
+	return string(err)
+}
+
+func NewUserNotFound(message string) error {
+	return UserNotFoundError(message)
+}
+
+type PermissionsBoundaryRequiredError string
+
+func (err PermissionsBoundaryRequiredError) Error() string {
+	return string(err)
+}
+
+func NewPermissionsBoundaryRequired(message string) error {
+	return PermissionsBoundaryRequiredError(message)
+}
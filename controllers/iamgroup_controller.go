@@ -0,0 +1,392 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	cu "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/johnhoman/aws-iam-controller/api/v1alpha1"
+	pkgaws "github.com/johnhoman/aws-iam-controller/pkg/aws"
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/iamgroup"
+)
+
+const (
+	IamGroupFinalizer = "aws.jackhoman.com/delete-iam-group"
+
+	groupPolicyRefIndex = "spec.policyRefs"
+	groupMemberIndex    = "spec.members"
+)
+
+// IamGroupReconciler reconciles a IamGroup object
+type IamGroupReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	record.EventRecorder
+
+	GroupService iamgroup.Interface
+	// DefaultDeletionPolicy is used for groups that don't set a deletion
+	// policy. Groups are deleted when it's empty
+	DefaultDeletionPolicy v1alpha1.DeletionPolicy
+}
+
+//+kubebuilder:rbac:groups=aws.jackhoman.com,resources=iamgroups,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=aws.jackhoman.com,resources=iamgroups/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=aws.jackhoman.com,resources=iamgroups/finalizers,verbs=update
+//+kubebuilder:rbac:groups=aws.jackhoman.com,resources=iampolicies,verbs=get;list;watch
+//+kubebuilder:rbac:groups=aws.jackhoman.com,resources=iamusers,verbs=get;list;watch
+
+// Reconcile creates the upstream iam group, attaches the referenced
+// policies and adds the member users to it
+func (r *IamGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	instance := &v1alpha1.IamGroup{}
+	if err := r.Client.Get(ctx, req.NamespacedName, instance); err != nil {
+		logger.Error(err, "unable to get instance")
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !instance.GetDeletionTimestamp().IsZero() {
+		logger.Info("instance pending deletion")
+		if err := r.Finalize(ctx, instance); err != nil {
+			logger.Error(err, "unable to finalize instance")
+			return ctrl.Result{}, err
+		}
+		if cu.ContainsFinalizer(instance, IamGroupFinalizer) {
+			patch := client.MergeFrom(instance.DeepCopy())
+			cu.RemoveFinalizer(instance, IamGroupFinalizer)
+			if err := r.Client.Patch(ctx, instance, patch, FieldOwner); err != nil {
+				logger.Error(err, "unable to remove finalizer")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+	if !cu.ContainsFinalizer(instance, IamGroupFinalizer) {
+		patch := &unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"finalizers": []string{IamGroupFinalizer},
+			},
+		}}
+		patch.SetName(instance.GetName())
+		patch.SetGroupVersionKind(v1alpha1.GroupVersion.WithKind(v1alpha1.KindIamGroup))
+		if err := r.Client.Patch(ctx, patch, client.Apply, FieldOwner, client.ForceOwnership); err != nil {
+			logger.Error(err, "unable to add finalizer")
+			return ctrl.Result{}, err
+		}
+	}
+
+	logger = logger.WithValues("GroupName", v1alpha1.UpstreamName(instance))
+	logger.Info("reconciling iam group")
+	reconcileErr := r.reconcile(ctx, instance)
+	if err := updateConditions(ctx, r.Client, instance, &instance.Status.ConditionedStatus,
+		readyCondition(len(instance.Status.Arn) > 0 && reasonForError(reconcileErr) != v1alpha1.ReasonConflict, reconcileErr),
+		syncedCondition(reconcileErr),
+	); err != nil {
+		logger.Error(err, "unable to update status conditions")
+		return ctrl.Result{}, err
+	}
+	if reconcileErr != nil {
+		return ctrl.Result{RequeueAfter: requeueAfter(reconcileErr)}, requeueError(reconcileErr)
+	}
+	logger.Info("Reconcile complete")
+	return ctrl.Result{}, nil
+}
+
+// reconcile converges the upstream group with the spec. Groups can't be
+// tagged, so the group is created under a path with the resource uid to
+// mark it as owned by the resource
+func (r *IamGroupReconciler) reconcile(ctx context.Context, instance *v1alpha1.IamGroup) error {
+	logger := log.FromContext(ctx)
+	name := v1alpha1.UpstreamName(instance)
+
+	upstream, err := r.GroupService.Get(ctx, &iamgroup.GetOptions{Name: name})
+	if err != nil {
+		if !pkgaws.IsNotFound(err) {
+			return err
+		}
+		upstream, err = r.GroupService.Create(ctx, &iamgroup.CreateOptions{Name: name, Path: string(instance.GetUID())})
+		if err != nil {
+			logger.Error(err, "unable to create iam group")
+			return err
+		}
+		logger.Info("created upstream iam group", "arn", upstream.Arn)
+		r.Eventf(instance, corev1.EventTypeNormal, "Created", "created iam group %s", upstream.Arn)
+	} else if !ownsGroup(instance, upstream) {
+		logger.Info("upstream iam group is not owned by this resource")
+		message := fmt.Sprintf("iam group %s exists and is not owned by this resource", upstream.Arn)
+		r.Event(instance, corev1.EventTypeWarning, v1alpha1.ReasonConflict, message)
+		return NewConflict(message)
+	}
+	if instance.Status.Arn != upstream.Arn {
+		patch := client.MergeFrom(instance.DeepCopy())
+		instance.Status.Arn = upstream.Arn
+		if err := r.Client.Status().Patch(ctx, instance, patch); err != nil {
+			logger.Error(err, "unable to update status")
+			return err
+		}
+	}
+	if err := r.reconcilePolicies(ctx, instance); err != nil {
+		var notFound PolicyNotFoundError
+		if !errors.As(err, &notFound) {
+			logger.Error(err, "unable to reconcile policies")
+			return err
+		}
+		// Missing policies don't block the members, so the error is
+		// returned once the members are reconciled
+		if memberErr := r.reconcileMembers(ctx, instance, upstream); memberErr != nil {
+			return memberErr
+		}
+		return err
+	}
+	return r.reconcileMembers(ctx, instance, upstream)
+}
+
+// ownsGroup returns true if the upstream group was created for the
+// resource, which is the case when the resource uid is the last segment of
+// the group path or the group arn is already in the status
+func ownsGroup(instance *v1alpha1.IamGroup, upstream *iamgroup.IamGroup) bool {
+	if strings.HasSuffix(upstream.Path, "/"+string(instance.GetUID())+"/") {
+		return true
+	}
+	return len(instance.Status.Arn) > 0 && instance.Status.Arn == upstream.Arn
+}
+
+// reconcilePolicies attaches the referenced policies, including their
+// shards, and detaches every other policy from the group
+func (r *IamGroupReconciler) reconcilePolicies(ctx context.Context, instance *v1alpha1.IamGroup) error {
+	name := v1alpha1.UpstreamName(instance)
+	attached, err := r.GroupService.ListAttachedPolicies(ctx, &iamgroup.ListOptions{Name: name})
+	if err != nil {
+		return err
+	}
+	want, missing, err := policyArns(ctx, r.Client, "", instance.Spec.PolicyRefs, instance.Spec.PolicyArns)
+	if err != nil {
+		return err
+	}
+	notFound, err := syncAttachments(r.EventRecorder, instance, sets.NewString(attached...), want,
+		func(arn string) error {
+			return r.GroupService.AttachPolicy(ctx, &iamgroup.AttachOptions{Name: name, PolicyArn: arn})
+		},
+		func(arn string) error {
+			return r.GroupService.DetachPolicy(ctx, &iamgroup.DetachOptions{Name: name, PolicyArn: arn})
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if missing = append(missing, notFound...); len(missing) > 0 {
+		return policyNotFound(missing, instance.Spec.PolicyArns)
+	}
+	return nil
+}
+
+// reconcileMembers adds the member users to the upstream group and removes
+// every other user. Users that are being deleted are removed so they can be
+// deleted upstream
+func (r *IamGroupReconciler) reconcileMembers(ctx context.Context, instance *v1alpha1.IamGroup, upstream *iamgroup.IamGroup) error {
+	name := v1alpha1.UpstreamName(instance)
+	// upstream user names of the members mapped to their resource names
+	want := make(map[string]string, len(instance.Spec.Members))
+	missing := make([]string, 0)
+	for _, member := range instance.Spec.Members {
+		user := &v1alpha1.IamUser{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Name: member.Name}, user); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return err
+			}
+			missing = append(missing, member.Name)
+			continue
+		}
+		if !user.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		if len(user.Status.Arn) == 0 {
+			missing = append(missing, member.Name)
+			continue
+		}
+		want[v1alpha1.UpstreamName(user)] = user.GetName()
+	}
+	current := sets.NewString(upstream.Members...)
+	members := sets.NewString()
+	for userName, memberName := range want {
+		if !current.Has(userName) {
+			if err := r.GroupService.AddUser(ctx, &iamgroup.MemberOptions{Name: name, UserName: userName}); err != nil {
+				if !pkgaws.IsNotFound(err) {
+					return err
+				}
+				missing = append(missing, memberName)
+				continue
+			}
+			r.Eventf(instance, corev1.EventTypeNormal, "AddedMember", "added user %s to group", userName)
+		}
+		members.Insert(memberName)
+	}
+	for _, userName := range current.List() {
+		if _, ok := want[userName]; ok {
+			continue
+		}
+		if err := r.GroupService.RemoveUser(ctx, &iamgroup.MemberOptions{Name: name, UserName: userName}); err != nil && !pkgaws.IsNotFound(err) {
+			return err
+		}
+		r.Eventf(instance, corev1.EventTypeNormal, "RemovedMember", "removed user %s from group", userName)
+	}
+
+	var refs []corev1.LocalObjectReference
+	for _, memberName := range members.List() {
+		refs = append(refs, corev1.LocalObjectReference{Name: memberName})
+	}
+	if !equality.Semantic.DeepEqual(instance.Status.Members, refs) {
+		patch := client.MergeFrom(instance.DeepCopy())
+		instance.Status.Members = refs
+		if err := r.Client.Status().Patch(ctx, instance, patch); err != nil {
+			return err
+		}
+	}
+	if len(missing) > 0 {
+		return NewUserNotFound(fmt.Sprintf("unable to add users %s", strings.Join(missing, ", ")))
+	}
+	return nil
+}
+
+// Finalize deletes the upstream group after removing its members and
+// policies. Retained groups are left as they are
+func (r *IamGroupReconciler) Finalize(ctx context.Context, instance *v1alpha1.IamGroup) error {
+	logger := log.FromContext(ctx).WithValues("method", "Finalize")
+	name := v1alpha1.UpstreamName(instance)
+
+	upstream, err := r.GroupService.Get(ctx, &iamgroup.GetOptions{Name: name})
+	if err != nil {
+		if pkgaws.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !ownsGroup(instance, upstream) {
+		logger.Info("upstream iam group is not owned by this resource, skipping deletion", "arn", upstream.Arn)
+		r.Eventf(instance, corev1.EventTypeWarning, v1alpha1.ReasonConflict, "not deleting iam group %s that is not owned by this resource", upstream.Arn)
+		return nil
+	}
+	if deletionPolicy(instance, instance.Spec.DeletionPolicy, r.DefaultDeletionPolicy) == v1alpha1.DeletionPolicyRetain {
+		logger.Info("Retained upstream group", "arn", upstream.Arn)
+		r.Eventf(instance, corev1.EventTypeNormal, "Retained", "retained iam group %s", upstream.Arn)
+		return nil
+	}
+	// Members and policies have to be removed before the group can be
+	// deleted
+	for _, userName := range upstream.Members {
+		if err := r.GroupService.RemoveUser(ctx, &iamgroup.MemberOptions{Name: name, UserName: userName}); err != nil && !pkgaws.IsNotFound(err) {
+			return err
+		}
+	}
+	attached, err := r.GroupService.ListAttachedPolicies(ctx, &iamgroup.ListOptions{Name: name})
+	if err != nil {
+		return err
+	}
+	for _, arn := range attached {
+		if err := r.GroupService.DetachPolicy(ctx, &iamgroup.DetachOptions{Name: name, PolicyArn: arn}); err != nil && !pkgaws.IsNotFound(err) {
+			return err
+		}
+	}
+	if err := r.GroupService.Delete(ctx, &iamgroup.DeleteOptions{Name: name}); err != nil && !pkgaws.IsNotFound(err) {
+		return err
+	}
+	logger.Info("Removed upstream group", "arn", upstream.Arn)
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *IamGroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.IamGroup{}, groupPolicyRefIndex, groupPolicyRefs); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.IamGroup{}, groupMemberIndex, groupMembers); err != nil {
+		return err
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.IamGroup{}).
+		Watches(
+			&source.Kind{Type: &v1alpha1.IamPolicy{}},
+			handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []ctrl.Request {
+				return groupRequests(mgr.GetClient(), groupPolicyRefIndex, obj.GetName())
+			}),
+		).
+		Watches(
+			&source.Kind{Type: &v1alpha1.IamUser{}},
+			handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []ctrl.Request {
+				return groupRequests(mgr.GetClient(), groupMemberIndex, obj.GetName())
+			}),
+		).
+		Complete(r)
+}
+
+// groupPolicyRefs indexes groups by the policies they reference
+func groupPolicyRefs(obj client.Object) []string {
+	group, ok := obj.(*v1alpha1.IamGroup)
+	if !ok {
+		return []string{}
+	}
+	matches := make([]string, 0, len(group.Spec.PolicyRefs))
+	for _, ref := range group.Spec.PolicyRefs {
+		matches = append(matches, ref.Name)
+	}
+	return matches
+}
+
+// groupMembers indexes groups by their member users
+func groupMembers(obj client.Object) []string {
+	group, ok := obj.(*v1alpha1.IamGroup)
+	if !ok {
+		return []string{}
+	}
+	matches := make([]string, 0, len(group.Spec.Members))
+	for _, member := range group.Spec.Members {
+		matches = append(matches, member.Name)
+	}
+	return matches
+}
+
+// groupRequests returns a request for every group matching the index value
+func groupRequests(c client.Client, index string, value string) []ctrl.Request {
+	groups := &v1alpha1.IamGroupList{}
+	if err := c.List(context.Background(), groups, client.MatchingFields{index: value}); err != nil {
+		return []ctrl.Request{}
+	}
+	requests := make([]ctrl.Request, 0, len(groups.Items))
+	for _, group := range groups.Items {
+		requests = append(requests, ctrl.Request{NamespacedName: types.NamespacedName{Name: group.GetName()}})
+	}
+	return requests
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"github.com/google/uuid"
+	"github.com/johnhoman/controller-tools/manager"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	cu "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/johnhoman/aws-iam-controller/api/v1alpha1"
+	"github.com/johnhoman/aws-iam-controller/controllers"
+	pkgaws "github.com/johnhoman/aws-iam-controller/pkg/aws"
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/fake"
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/iamgroup"
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/iamuser"
+)
+
+var _ = Describe("IamGroupController", func() {
+	var mgr manager.IntegrationTest
+	var groupService iamgroup.Interface
+	var userService iamuser.Interface
+	var key types.NamespacedName
+	var instance *v1alpha1.IamGroup
+	BeforeEach(func() {
+		iamService := fake.NewIamService()
+		groupService = iamgroup.New(iamService, "controller-test")
+		userService = iamuser.New(iamService, "controller-test")
+		mgr = manager.IntegrationTestBuilder().
+			WithScheme(scheme.Scheme).
+			Complete(cfg)
+
+		Expect((&controllers.IamGroupReconciler{
+			Client:        mgr.GetClient(),
+			Scheme:        mgr.GetScheme(),
+			EventRecorder: mgr.GetEventRecorderFor("controller.test"),
+			GroupService:  groupService,
+		}).SetupWithManager(mgr)).Should(Succeed())
+		mgr.StartManager()
+
+		key = types.NamespacedName{Name: "iam-group-" + uuid.New().String()[:8]}
+		instance = &v1alpha1.IamGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name},
+			Spec: v1alpha1.IamGroupSpec{
+				Members: []corev1.LocalObjectReference{{Name: key.Name + "-member"}},
+			},
+		}
+	})
+	AfterEach(func() { mgr.StopManager() })
+	When("the group is created", func() {
+		BeforeEach(func() {
+			mgr.Eventually().Create(instance).Should(Succeed())
+		})
+		It("should create the upstream group", func() {
+			mgr.Eventually().GetWhen(key, instance, func(obj client.Object) bool {
+				return len(obj.(*v1alpha1.IamGroup).Status.Arn) > 0
+			}).Should(Succeed())
+			Expect(cu.ContainsFinalizer(instance, controllers.IamGroupFinalizer)).Should(BeTrue())
+			upstream, err := groupService.Get(mgr.GetContext(), &iamgroup.GetOptions{Name: key.Name})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(upstream.Arn).Should(Equal(instance.Status.Arn))
+		})
+		It("should still own the upstream group after the status is lost", func() {
+			mgr.Eventually().GetWhen(key, instance, func(obj client.Object) bool {
+				return len(obj.(*v1alpha1.IamGroup).Status.Arn) > 0
+			}).Should(Succeed())
+			arn := instance.Status.Arn
+			patch := client.MergeFrom(instance.DeepCopy())
+			instance.Status.Arn = ""
+			Expect(mgr.Uncached().Status().Patch(mgr.GetContext(), instance, patch)).Should(Succeed())
+			mgr.Eventually().GetWhen(key, instance, func(obj client.Object) bool {
+				return obj.(*v1alpha1.IamGroup).Status.Arn == arn
+			}).Should(Succeed())
+			Expect(meta.FindStatusCondition(instance.Status.Conditions, v1alpha1.ConditionTypeSynced).Reason).ShouldNot(Equal(v1alpha1.ReasonConflict))
+		})
+		It("should report the missing member", func() {
+			mgr.Eventually().GetWhen(key, instance, func(obj client.Object) bool {
+				condition := meta.FindStatusCondition(obj.(*v1alpha1.IamGroup).Status.Conditions, v1alpha1.ConditionTypeSynced)
+				return condition != nil && condition.Reason == v1alpha1.ReasonUserNotFound
+			}).Should(Succeed())
+			Expect(instance.Status.Members).Should(BeEmpty())
+		})
+		It("should delete the upstream group", func() {
+			mgr.Eventually().GetWhen(key, instance, func(obj client.Object) bool {
+				return len(obj.(*v1alpha1.IamGroup).Status.Arn) > 0
+			}).Should(Succeed())
+			Expect(mgr.Uncached().Delete(mgr.GetContext(), instance)).Should(Succeed())
+			Eventually(func() bool {
+				_, err := groupService.Get(mgr.GetContext(), &iamgroup.GetOptions{Name: key.Name})
+				return pkgaws.IsNotFound(err)
+			}).Should(BeTrue())
+		})
+	})
+	When("the member user exists", func() {
+		var userName string
+		BeforeEach(func() {
+			user := &v1alpha1.IamUser{ObjectMeta: metav1.ObjectMeta{Name: key.Name + "-member"}}
+			userName = v1alpha1.UpstreamName(user)
+			upstream, err := userService.Create(mgr.GetContext(), &iamuser.CreateOptions{Name: userName})
+			Expect(err).ShouldNot(HaveOccurred())
+			mgr.Eventually().Create(user).Should(Succeed())
+			patch := client.MergeFrom(user.DeepCopy())
+			user.Status.Arn = upstream.Arn
+			Expect(mgr.Uncached().Status().Patch(mgr.GetContext(), user, patch)).Should(Succeed())
+
+			mgr.Eventually().Create(instance).Should(Succeed())
+		})
+		It("should add the user to the group", func() {
+			mgr.Eventually().GetWhen(key, instance, func(obj client.Object) bool {
+				return len(obj.(*v1alpha1.IamGroup).Status.Members) > 0
+			}).Should(Succeed())
+			Expect(instance.Status.Members).Should(Equal(instance.Spec.Members))
+			upstream, err := groupService.Get(mgr.GetContext(), &iamgroup.GetOptions{Name: key.Name})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(upstream.Members).Should(ConsistOf(userName))
+		})
+		It("should remove the user when it's no longer a member", func() {
+			mgr.Eventually().GetWhen(key, instance, func(obj client.Object) bool {
+				return len(obj.(*v1alpha1.IamGroup).Status.Members) > 0
+			}).Should(Succeed())
+			patch := client.MergeFrom(instance.DeepCopy())
+			instance.Spec.Members = nil
+			Expect(mgr.Uncached().Patch(mgr.GetContext(), instance, patch)).Should(Succeed())
+			Eventually(func() []string {
+				upstream, _ := groupService.Get(mgr.GetContext(), &iamgroup.GetOptions{Name: key.Name})
+				if upstream == nil {
+					return nil
+				}
+				return upstream.Members
+			}).Should(BeEmpty())
+		})
+	})
+})
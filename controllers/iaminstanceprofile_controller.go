@@ -0,0 +1,314 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	cu "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/johnhoman/aws-iam-controller/api/v1alpha1"
+	pkgaws "github.com/johnhoman/aws-iam-controller/pkg/aws"
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/iaminstanceprofile"
+)
+
+const (
+	IamInstanceProfileFinalizer = "aws.jackhoman.com/delete-iam-instance-profile"
+	// ServiceEC2 is trusted by roles in an instance profile so instances can
+	// assume them
+	ServiceEC2 = "ec2.amazonaws.com"
+)
+
+// IamInstanceProfileReconciler reconciles a IamInstanceProfile object
+type IamInstanceProfileReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	record.EventRecorder
+
+	InstanceProfileService iaminstanceprofile.Interface
+	Tags                   Tagger
+	// DefaultDeletionPolicy is used for instance profiles that don't set a
+	// deletion policy. Instance profiles are deleted when it's empty
+	DefaultDeletionPolicy v1alpha1.DeletionPolicy
+}
+
+//+kubebuilder:rbac:groups=aws.jackhoman.com,resources=iaminstanceprofiles,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=aws.jackhoman.com,resources=iaminstanceprofiles/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=aws.jackhoman.com,resources=iaminstanceprofiles/finalizers,verbs=update
+//+kubebuilder:rbac:groups=aws.jackhoman.com,resources=iamroles,verbs=get;list;watch
+
+// Reconcile creates the upstream instance profile and adds the referenced
+// role to it
+func (r *IamInstanceProfileReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	instance := &v1alpha1.IamInstanceProfile{}
+	if err := r.Client.Get(ctx, req.NamespacedName, instance); err != nil {
+		logger.Error(err, "unable to get instance")
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !instance.GetDeletionTimestamp().IsZero() {
+		logger.Info("instance pending deletion")
+		if err := r.Finalize(ctx, instance); err != nil {
+			logger.Error(err, "unable to finalize instance")
+			return ctrl.Result{}, err
+		}
+		if cu.ContainsFinalizer(instance, IamInstanceProfileFinalizer) {
+			patch := client.MergeFrom(instance.DeepCopy())
+			cu.RemoveFinalizer(instance, IamInstanceProfileFinalizer)
+			if err := r.Client.Patch(ctx, instance, patch, FieldOwner); err != nil {
+				logger.Error(err, "unable to remove finalizer")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+	if !cu.ContainsFinalizer(instance, IamInstanceProfileFinalizer) {
+		patch := &unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"finalizers": []string{IamInstanceProfileFinalizer},
+			},
+		}}
+		patch.SetName(instance.GetName())
+		patch.SetGroupVersionKind(v1alpha1.GroupVersion.WithKind(v1alpha1.KindIamInstanceProfile))
+		if err := r.Client.Patch(ctx, patch, client.Apply, FieldOwner, client.ForceOwnership); err != nil {
+			logger.Error(err, "unable to add finalizer")
+			return ctrl.Result{}, err
+		}
+	}
+
+	logger = logger.WithValues("InstanceProfileName", v1alpha1.UpstreamName(instance))
+	logger.Info("reconciling iam instance profile")
+	reconcileErr := r.reconcile(ctx, instance)
+	if err := updateConditions(ctx, r.Client, instance, &instance.Status.ConditionedStatus,
+		readyCondition(len(instance.Status.Arn) > 0 && reasonForError(reconcileErr) != v1alpha1.ReasonConflict, reconcileErr),
+		syncedCondition(reconcileErr),
+	); err != nil {
+		logger.Error(err, "unable to update status conditions")
+		return ctrl.Result{}, err
+	}
+	if reconcileErr != nil {
+		return ctrl.Result{}, requeueError(reconcileErr)
+	}
+	logger.Info("Reconcile complete")
+	return ctrl.Result{}, nil
+}
+
+func (r *IamInstanceProfileReconciler) reconcile(ctx context.Context, instance *v1alpha1.IamInstanceProfile) error {
+	logger := log.FromContext(ctx)
+	name := v1alpha1.UpstreamName(instance)
+	tags := r.Tags.Tags(v1alpha1.KindIamInstanceProfile, instance, instance.Spec.Tags)
+
+	upstream, err := r.InstanceProfileService.Get(ctx, &iaminstanceprofile.GetOptions{Name: name})
+	if err != nil {
+		if !pkgaws.IsNotFound(err) {
+			return err
+		}
+		upstream, err = r.InstanceProfileService.Create(ctx, &iaminstanceprofile.CreateOptions{Name: name, Tags: tags})
+		if err != nil {
+			logger.Error(err, "unable to create iam instance profile")
+			return err
+		}
+		logger.Info("created upstream iam instance profile", "arn", upstream.Arn)
+		r.Eventf(instance, corev1.EventTypeNormal, "Created", "created iam instance profile %s", upstream.Arn)
+	} else if !r.Tags.Owns(instance, upstream.Tags) {
+		logger.Info("upstream iam instance profile is not owned by this resource")
+		message := fmt.Sprintf("iam instance profile %s exists and is not owned by this resource", upstream.Arn)
+		r.Event(instance, corev1.EventTypeWarning, v1alpha1.ReasonConflict, message)
+		return NewConflict(message)
+	}
+	if err := r.reconcileTags(ctx, instance, upstream, tags); err != nil {
+		logger.Error(err, "unable to update tags")
+		return err
+	}
+	if instance.Status.Arn != upstream.Arn {
+		patch := client.MergeFrom(instance.DeepCopy())
+		instance.Status.Arn = upstream.Arn
+		if err := r.Client.Status().Patch(ctx, instance, patch); err != nil {
+			logger.Error(err, "unable to update status")
+			return err
+		}
+	}
+	return r.reconcileRole(ctx, instance, upstream)
+}
+
+// reconcileTags adds tags missing from the upstream instance profile and
+// removes tags that are no longer wanted
+func (r *IamInstanceProfileReconciler) reconcileTags(ctx context.Context, instance *v1alpha1.IamInstanceProfile, upstream *iaminstanceprofile.IamInstanceProfile, tags map[string]string) error {
+	add, remove := diffTags(upstream.Tags, tags, instance.Status.ManagedTags)
+	if len(add) > 0 {
+		if err := r.InstanceProfileService.Tag(ctx, &iaminstanceprofile.TagOptions{Name: upstream.Name, Tags: add}); err != nil {
+			return err
+		}
+	}
+	if len(remove) > 0 {
+		if err := r.InstanceProfileService.Untag(ctx, &iaminstanceprofile.UntagOptions{Name: upstream.Name, Keys: remove}); err != nil {
+			return err
+		}
+	}
+	if len(add) > 0 || len(remove) > 0 {
+		r.Eventf(instance, corev1.EventTypeNormal, "UpdatedTags", "added %d and removed %d tags", len(add), len(remove))
+	}
+	return updateManagedTags(ctx, r.Client, instance, &instance.Status.ManagedTags, instance.Spec.Tags)
+}
+
+// reconcileRole replaces the role in the upstream instance profile with the
+// referenced role. An instance profile only has one role, so any other role
+// is removed first
+func (r *IamInstanceProfileReconciler) reconcileRole(ctx context.Context, instance *v1alpha1.IamInstanceProfile, upstream *iaminstanceprofile.IamInstanceProfile) error {
+	logger := log.FromContext(ctx)
+
+	role := &v1alpha1.IamRole{}
+	roleName := ""
+	roleArn := ""
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: instance.Spec.RoleRef.Name}, role); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+	} else if role.GetDeletionTimestamp().IsZero() && len(role.Status.RoleArn) > 0 {
+		roleName = upstreamRoleName(role)
+		roleArn = role.Status.RoleArn
+	}
+
+	found := false
+	for _, name := range upstream.Roles {
+		if name == roleName {
+			found = true
+			continue
+		}
+		if err := r.InstanceProfileService.RemoveRole(ctx, &iaminstanceprofile.RoleOptions{
+			Name:     upstream.Name,
+			RoleName: name,
+		}); err != nil && !pkgaws.IsNotFound(err) {
+			return err
+		}
+		logger.Info("removed role from instance profile", "roleName", name)
+		r.Eventf(instance, corev1.EventTypeNormal, "RemovedRole", "removed role %s from instance profile", name)
+	}
+	if len(roleName) > 0 && !found {
+		if err := r.InstanceProfileService.AddRole(ctx, &iaminstanceprofile.RoleOptions{
+			Name:     upstream.Name,
+			RoleName: roleName,
+		}); err != nil {
+			if !pkgaws.IsNotFound(err) {
+				return err
+			}
+			roleArn = ""
+		} else {
+			logger.Info("added role to instance profile", "roleName", roleName)
+			r.Eventf(instance, corev1.EventTypeNormal, "AddedRole", "added role %s to instance profile", roleName)
+		}
+	}
+	if instance.Status.RoleArn != roleArn {
+		patch := client.MergeFrom(instance.DeepCopy())
+		instance.Status.RoleArn = roleArn
+		if err := r.Client.Status().Patch(ctx, instance, patch); err != nil {
+			return err
+		}
+	}
+	if len(roleArn) == 0 {
+		return NewRoleNotFound(fmt.Sprintf("unable to add role %s", instance.Spec.RoleRef.Name))
+	}
+	return nil
+}
+
+// Finalize removes the role from the upstream instance profile and deletes
+// it. Retained instance profiles keep their role
+func (r *IamInstanceProfileReconciler) Finalize(ctx context.Context, instance *v1alpha1.IamInstanceProfile) error {
+	logger := log.FromContext(ctx).WithValues("method", "Finalize")
+	name := v1alpha1.UpstreamName(instance)
+
+	upstream, err := r.InstanceProfileService.Get(ctx, &iaminstanceprofile.GetOptions{Name: name})
+	if err != nil {
+		if pkgaws.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !r.Tags.Owns(instance, upstream.Tags) {
+		logger.Info("upstream iam instance profile is not owned by this resource, skipping deletion", "arn", upstream.Arn)
+		r.Eventf(instance, corev1.EventTypeWarning, v1alpha1.ReasonConflict, "not deleting iam instance profile %s that is not owned by this resource", upstream.Arn)
+		return nil
+	}
+	if deletionPolicy(instance, instance.Spec.DeletionPolicy, r.DefaultDeletionPolicy) == v1alpha1.DeletionPolicyRetain {
+		if err := r.InstanceProfileService.Untag(ctx, &iaminstanceprofile.UntagOptions{
+			Name: name,
+			Keys: ownershipTagKeys,
+		}); err != nil && !pkgaws.IsNotFound(err) {
+			return err
+		}
+		logger.Info("Retained upstream instance profile", "arn", upstream.Arn)
+		r.Eventf(instance, corev1.EventTypeNormal, "Retained", "retained iam instance profile %s", upstream.Arn)
+		return nil
+	}
+	// The role has to be removed before the instance profile can be deleted
+	for _, roleName := range upstream.Roles {
+		if err := r.InstanceProfileService.RemoveRole(ctx, &iaminstanceprofile.RoleOptions{
+			Name:     name,
+			RoleName: roleName,
+		}); err != nil && !pkgaws.IsNotFound(err) {
+			return err
+		}
+	}
+	if err := r.InstanceProfileService.Delete(ctx, &iaminstanceprofile.DeleteOptions{Name: name}); err != nil && !pkgaws.IsNotFound(err) {
+		return err
+	}
+	logger.Info("Removed upstream instance profile", "arn", upstream.Arn)
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *IamInstanceProfileReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.IamInstanceProfile{}).
+		Watches(
+			&source.Kind{Type: &v1alpha1.IamRole{}},
+			handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []ctrl.Request {
+				return roleInstanceProfileRequests(mgr.GetClient(), obj.GetName())
+			}),
+		).
+		Complete(r)
+}
+
+// roleInstanceProfileRequests returns a request for every instance profile
+// that references the role
+func roleInstanceProfileRequests(c client.Client, roleName string) []ctrl.Request {
+	profiles := &v1alpha1.IamInstanceProfileList{}
+	if err := c.List(context.Background(), profiles); err != nil {
+		return []ctrl.Request{}
+	}
+	requests := make([]ctrl.Request, 0)
+	for _, profile := range profiles.Items {
+		if profile.Spec.RoleRef.Name == roleName {
+			requests = append(requests, ctrl.Request{NamespacedName: types.NamespacedName{Name: profile.GetName()}})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/google/uuid"
+	"github.com/johnhoman/controller-tools/manager"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	cu "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/johnhoman/aws-iam-controller/api/v1alpha1"
+	"github.com/johnhoman/aws-iam-controller/controllers"
+	pkgaws "github.com/johnhoman/aws-iam-controller/pkg/aws"
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/fake"
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/iaminstanceprofile"
+)
+
+var _ = Describe("IamInstanceProfileController", func() {
+	var mgr manager.IntegrationTest
+	var iamService *fake.IamService
+	var profileService iaminstanceprofile.Interface
+	var key types.NamespacedName
+	var instance *v1alpha1.IamInstanceProfile
+	BeforeEach(func() {
+		iamService = fake.NewIamService()
+		profileService = iaminstanceprofile.New(iamService, "controller-test")
+		mgr = manager.IntegrationTestBuilder().
+			WithScheme(scheme.Scheme).
+			Complete(cfg)
+
+		Expect((&controllers.IamInstanceProfileReconciler{
+			Client:                 mgr.GetClient(),
+			Scheme:                 mgr.GetScheme(),
+			EventRecorder:          mgr.GetEventRecorderFor("controller.test"),
+			InstanceProfileService: profileService,
+			Tags:                   controllers.Tagger{ClusterID: "controller-test"},
+		}).SetupWithManager(mgr)).Should(Succeed())
+		mgr.StartManager()
+
+		key = types.NamespacedName{Name: "iam-instance-profile-" + uuid.New().String()[:8]}
+		instance = &v1alpha1.IamInstanceProfile{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name},
+			Spec: v1alpha1.IamInstanceProfileSpec{
+				RoleRef: corev1.LocalObjectReference{Name: key.Name + "-role"},
+			},
+		}
+	})
+	AfterEach(func() { mgr.StopManager() })
+	When("the role doesn't exist", func() {
+		BeforeEach(func() {
+			mgr.Eventually().Create(instance).Should(Succeed())
+		})
+		It("should create the upstream instance profile", func() {
+			mgr.Eventually().GetWhen(key, instance, func(obj client.Object) bool {
+				return len(obj.(*v1alpha1.IamInstanceProfile).Status.Arn) > 0
+			}).Should(Succeed())
+			Expect(cu.ContainsFinalizer(instance, controllers.IamInstanceProfileFinalizer)).Should(BeTrue())
+			upstream, err := profileService.Get(mgr.GetContext(), &iaminstanceprofile.GetOptions{Name: key.Name})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(upstream.Arn).Should(Equal(instance.Status.Arn))
+		})
+		It("should report the missing role", func() {
+			mgr.Eventually().GetWhen(key, instance, func(obj client.Object) bool {
+				condition := meta.FindStatusCondition(obj.(*v1alpha1.IamInstanceProfile).Status.Conditions, v1alpha1.ConditionTypeSynced)
+				return condition != nil && condition.Reason == v1alpha1.ReasonRoleNotFound
+			}).Should(Succeed())
+			Expect(instance.Status.RoleArn).Should(BeEmpty())
+		})
+	})
+	When("the role exists", func() {
+		var roleArn string
+		BeforeEach(func() {
+			role := &v1alpha1.IamRole{ObjectMeta: metav1.ObjectMeta{Name: key.Name + "-role"}}
+			out, err := iamService.CreateRole(mgr.GetContext(), &iam.CreateRoleInput{
+				RoleName:                 aws.String(v1alpha1.UpstreamName(role)),
+				AssumeRolePolicyDocument: aws.String(`{"Version":"2012-10-17","Statement":[]}`),
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			roleArn = aws.ToString(out.Role.Arn)
+			mgr.Eventually().Create(role).Should(Succeed())
+			patch := client.MergeFrom(role.DeepCopy())
+			role.Status.RoleArn = roleArn
+			Expect(mgr.Uncached().Status().Patch(mgr.GetContext(), role, patch)).Should(Succeed())
+
+			mgr.Eventually().Create(instance).Should(Succeed())
+		})
+		It("should add the role to the instance profile", func() {
+			mgr.Eventually().GetWhen(key, instance, func(obj client.Object) bool {
+				return len(obj.(*v1alpha1.IamInstanceProfile).Status.RoleArn) > 0
+			}).Should(Succeed())
+			Expect(instance.Status.RoleArn).Should(Equal(roleArn))
+			upstream, err := profileService.Get(mgr.GetContext(), &iaminstanceprofile.GetOptions{Name: key.Name})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(upstream.Roles).Should(ConsistOf(key.Name + "-role"))
+		})
+		It("should delete the upstream instance profile", func() {
+			mgr.Eventually().GetWhen(key, instance, func(obj client.Object) bool {
+				return len(obj.(*v1alpha1.IamInstanceProfile).Status.RoleArn) > 0
+			}).Should(Succeed())
+			Expect(mgr.Uncached().Delete(mgr.GetContext(), instance)).Should(Succeed())
+			Eventually(func() bool {
+				_, err := profileService.Get(mgr.GetContext(), &iaminstanceprofile.GetOptions{Name: key.Name})
+				return pkgaws.IsNotFound(err)
+			}).Should(BeTrue())
+		})
+	})
+})
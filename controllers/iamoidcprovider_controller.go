@@ -0,0 +1,258 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	cu "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/johnhoman/aws-iam-controller/api/v1alpha1"
+	pkgaws "github.com/johnhoman/aws-iam-controller/pkg/aws"
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/iamoidcprovider"
+)
+
+const (
+	IamOIDCProviderFinalizer = "aws.jackhoman.com/delete-iam-oidc-provider"
+)
+
+// IamOIDCProviderReconciler reconciles a IamOIDCProvider object
+type IamOIDCProviderReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	record.EventRecorder
+
+	ProviderService iamoidcprovider.Interface
+	Tags            Tagger
+	// DefaultDeletionPolicy is used for providers that don't set a deletion
+	// policy. Providers are deleted when it's empty
+	DefaultDeletionPolicy v1alpha1.DeletionPolicy
+}
+
+//+kubebuilder:rbac:groups=aws.jackhoman.com,resources=iamoidcproviders,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=aws.jackhoman.com,resources=iamoidcproviders/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=aws.jackhoman.com,resources=iamoidcproviders/finalizers,verbs=update
+
+// Reconcile creates the upstream oidc provider and keeps its client ids,
+// thumbprints and tags in sync with the spec
+func (r *IamOIDCProviderReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	instance := &v1alpha1.IamOIDCProvider{}
+	if err := r.Client.Get(ctx, req.NamespacedName, instance); err != nil {
+		logger.Error(err, "unable to get instance")
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !instance.GetDeletionTimestamp().IsZero() {
+		logger.Info("instance pending deletion")
+		if err := r.Finalize(ctx, instance); err != nil {
+			logger.Error(err, "unable to finalize instance")
+			return ctrl.Result{}, err
+		}
+		if cu.ContainsFinalizer(instance, IamOIDCProviderFinalizer) {
+			patch := client.MergeFrom(instance.DeepCopy())
+			cu.RemoveFinalizer(instance, IamOIDCProviderFinalizer)
+			if err := r.Client.Patch(ctx, instance, patch, FieldOwner); err != nil {
+				logger.Error(err, "unable to remove finalizer")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+	if !cu.ContainsFinalizer(instance, IamOIDCProviderFinalizer) {
+		patch := &unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"finalizers": []string{IamOIDCProviderFinalizer},
+			},
+		}}
+		patch.SetName(instance.GetName())
+		patch.SetGroupVersionKind(v1alpha1.GroupVersion.WithKind(v1alpha1.KindIamOIDCProvider))
+		if err := r.Client.Patch(ctx, patch, client.Apply, FieldOwner, client.ForceOwnership); err != nil {
+			logger.Error(err, "unable to add finalizer")
+			return ctrl.Result{}, err
+		}
+	}
+
+	logger = logger.WithValues("URL", instance.Spec.URL)
+	logger.Info("reconciling iam oidc provider")
+	reconcileErr := r.reconcile(ctx, instance)
+	if err := updateConditions(ctx, r.Client, instance, &instance.Status.ConditionedStatus,
+		readyCondition(len(instance.Status.Arn) > 0 && reasonForError(reconcileErr) != v1alpha1.ReasonConflict, reconcileErr),
+		syncedCondition(reconcileErr),
+	); err != nil {
+		logger.Error(err, "unable to update status conditions")
+		return ctrl.Result{}, err
+	}
+	if reconcileErr != nil {
+		return ctrl.Result{}, requeueError(reconcileErr)
+	}
+	logger.Info("Reconcile complete")
+	return ctrl.Result{}, nil
+}
+
+func (r *IamOIDCProviderReconciler) reconcile(ctx context.Context, instance *v1alpha1.IamOIDCProvider) error {
+	logger := log.FromContext(ctx)
+	tags := r.Tags.Tags(v1alpha1.KindIamOIDCProvider, instance, instance.Spec.Tags)
+
+	upstream, err := r.getProvider(ctx, instance)
+	if err != nil {
+		if !pkgaws.IsNotFound(err) {
+			return err
+		}
+		upstream, err = r.ProviderService.Create(ctx, &iamoidcprovider.CreateOptions{
+			URL:         instance.Spec.URL,
+			ClientIDs:   instance.GetClientIDs(),
+			Thumbprints: instance.Spec.Thumbprints,
+			Tags:        tags,
+		})
+		if err != nil {
+			logger.Error(err, "unable to create iam oidc provider")
+			return err
+		}
+		logger.Info("created upstream iam oidc provider", "arn", upstream.Arn)
+		r.Eventf(instance, corev1.EventTypeNormal, "Created", "created iam oidc provider %s", upstream.Arn)
+	} else if !r.Tags.Owns(instance, upstream.Tags) {
+		logger.Info("upstream iam oidc provider is not owned by this resource")
+		message := fmt.Sprintf("iam oidc provider %s exists and is not owned by this resource", upstream.Arn)
+		r.Event(instance, corev1.EventTypeWarning, v1alpha1.ReasonConflict, message)
+		return NewConflict(message)
+	}
+	if err := r.reconcileTags(ctx, instance, upstream, tags); err != nil {
+		logger.Error(err, "unable to update tags")
+		return err
+	}
+	if instance.Status.Arn != upstream.Arn {
+		patch := client.MergeFrom(instance.DeepCopy())
+		instance.Status.Arn = upstream.Arn
+		if err := r.Client.Status().Patch(ctx, instance, patch); err != nil {
+			logger.Error(err, "unable to update status")
+			return err
+		}
+	}
+
+	current := sets.NewString(upstream.ClientIDs...)
+	want := sets.NewString(instance.GetClientIDs()...)
+	for _, clientID := range want.Difference(current).List() {
+		if err := r.ProviderService.AddClientID(ctx, &iamoidcprovider.ClientIDOptions{Arn: upstream.Arn, ClientID: clientID}); err != nil {
+			return err
+		}
+		r.Eventf(instance, corev1.EventTypeNormal, "AddedClientID", "added client id %s", clientID)
+	}
+	for _, clientID := range current.Difference(want).List() {
+		if err := r.ProviderService.RemoveClientID(ctx, &iamoidcprovider.ClientIDOptions{Arn: upstream.Arn, ClientID: clientID}); err != nil {
+			return err
+		}
+		r.Eventf(instance, corev1.EventTypeNormal, "RemovedClientID", "removed client id %s", clientID)
+	}
+	// IAM fetches the thumbprints itself when the spec doesn't have any, so
+	// they're only updated when they're set
+	if len(instance.Spec.Thumbprints) > 0 && !sets.NewString(upstream.Thumbprints...).Equal(sets.NewString(instance.Spec.Thumbprints...)) {
+		if err := r.ProviderService.UpdateThumbprints(ctx, &iamoidcprovider.UpdateThumbprintsOptions{
+			Arn:         upstream.Arn,
+			Thumbprints: instance.Spec.Thumbprints,
+		}); err != nil {
+			return err
+		}
+		r.Event(instance, corev1.EventTypeNormal, "UpdatedThumbprints", "updated thumbprints")
+	}
+	return nil
+}
+
+// getProvider returns the upstream provider for the status arn, or the
+// provider for the url when the status doesn't have an arn yet
+func (r *IamOIDCProviderReconciler) getProvider(ctx context.Context, instance *v1alpha1.IamOIDCProvider) (*iamoidcprovider.IamOIDCProvider, error) {
+	if len(instance.Status.Arn) > 0 {
+		upstream, err := r.ProviderService.Get(ctx, &iamoidcprovider.GetOptions{Arn: instance.Status.Arn})
+		if err == nil || !pkgaws.IsNotFound(err) {
+			return upstream, err
+		}
+	}
+	return r.ProviderService.Find(ctx, &iamoidcprovider.FindOptions{URL: instance.Spec.URL})
+}
+
+// reconcileTags adds tags missing from the upstream provider and removes
+// tags that are no longer wanted
+func (r *IamOIDCProviderReconciler) reconcileTags(ctx context.Context, instance *v1alpha1.IamOIDCProvider, upstream *iamoidcprovider.IamOIDCProvider, tags map[string]string) error {
+	add, remove := diffTags(upstream.Tags, tags, instance.Status.ManagedTags)
+	if len(add) > 0 {
+		if err := r.ProviderService.Tag(ctx, &iamoidcprovider.TagOptions{Arn: upstream.Arn, Tags: add}); err != nil {
+			return err
+		}
+	}
+	if len(remove) > 0 {
+		if err := r.ProviderService.Untag(ctx, &iamoidcprovider.UntagOptions{Arn: upstream.Arn, Keys: remove}); err != nil {
+			return err
+		}
+	}
+	if len(add) > 0 || len(remove) > 0 {
+		r.Eventf(instance, corev1.EventTypeNormal, "UpdatedTags", "added %d and removed %d tags", len(add), len(remove))
+	}
+	return updateManagedTags(ctx, r.Client, instance, &instance.Status.ManagedTags, instance.Spec.Tags)
+}
+
+// Finalize deletes the upstream provider. Service accounts can't assume
+// roles through a deleted provider, so the Retain deletion policy is
+// usually what's wanted
+func (r *IamOIDCProviderReconciler) Finalize(ctx context.Context, instance *v1alpha1.IamOIDCProvider) error {
+	logger := log.FromContext(ctx).WithValues("method", "Finalize")
+
+	upstream, err := r.getProvider(ctx, instance)
+	if err != nil {
+		if pkgaws.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !r.Tags.Owns(instance, upstream.Tags) {
+		logger.Info("upstream iam oidc provider is not owned by this resource, skipping deletion", "arn", upstream.Arn)
+		r.Eventf(instance, corev1.EventTypeWarning, v1alpha1.ReasonConflict, "not deleting iam oidc provider %s that is not owned by this resource", upstream.Arn)
+		return nil
+	}
+	if deletionPolicy(instance, instance.Spec.DeletionPolicy, r.DefaultDeletionPolicy) == v1alpha1.DeletionPolicyRetain {
+		if err := r.ProviderService.Untag(ctx, &iamoidcprovider.UntagOptions{
+			Arn:  upstream.Arn,
+			Keys: ownershipTagKeys,
+		}); err != nil && !pkgaws.IsNotFound(err) {
+			return err
+		}
+		logger.Info("Retained upstream oidc provider", "arn", upstream.Arn)
+		r.Eventf(instance, corev1.EventTypeNormal, "Retained", "retained iam oidc provider %s", upstream.Arn)
+		return nil
+	}
+	if err := r.ProviderService.Delete(ctx, &iamoidcprovider.DeleteOptions{Arn: upstream.Arn}); err != nil && !pkgaws.IsNotFound(err) {
+		return err
+	}
+	logger.Info("Removed upstream oidc provider", "arn", upstream.Arn)
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *IamOIDCProviderReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.IamOIDCProvider{}).
+		Complete(r)
+}
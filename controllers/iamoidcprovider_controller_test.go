@@ -0,0 +1,101 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"github.com/google/uuid"
+	"github.com/johnhoman/controller-tools/manager"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	cu "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/johnhoman/aws-iam-controller/api/v1alpha1"
+	"github.com/johnhoman/aws-iam-controller/controllers"
+	pkgaws "github.com/johnhoman/aws-iam-controller/pkg/aws"
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/fake"
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/iamoidcprovider"
+	"github.com/johnhoman/aws-iam-controller/pkg/bindmanager"
+)
+
+var _ = Describe("IamOIDCProviderController", func() {
+	var mgr manager.IntegrationTest
+	var providerService iamoidcprovider.Interface
+	var key types.NamespacedName
+	var instance *v1alpha1.IamOIDCProvider
+	BeforeEach(func() {
+		providerService = iamoidcprovider.New(fake.NewIamService())
+		mgr = manager.IntegrationTestBuilder().
+			WithScheme(scheme.Scheme).
+			Complete(cfg)
+
+		Expect((&controllers.IamOIDCProviderReconciler{
+			Client:          mgr.GetClient(),
+			Scheme:          mgr.GetScheme(),
+			EventRecorder:   mgr.GetEventRecorderFor("controller.test"),
+			ProviderService: providerService,
+			Tags:            controllers.Tagger{ClusterID: "controller-test"},
+		}).SetupWithManager(mgr)).Should(Succeed())
+		mgr.StartManager()
+
+		key = types.NamespacedName{Name: "iam-oidc-provider-" + uuid.New().String()[:8]}
+		instance = &v1alpha1.IamOIDCProvider{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name},
+			Spec: v1alpha1.IamOIDCProviderSpec{
+				URL: "https://oidc.example.com/id/" + key.Name,
+			},
+		}
+		mgr.Eventually().Create(instance).Should(Succeed())
+		mgr.Eventually().GetWhen(key, instance, func(obj client.Object) bool {
+			return len(obj.(*v1alpha1.IamOIDCProvider).Status.Arn) > 0
+		}).Should(Succeed())
+	})
+	AfterEach(func() { mgr.StopManager() })
+	It("should create the upstream provider", func() {
+		Expect(cu.ContainsFinalizer(instance, controllers.IamOIDCProviderFinalizer)).Should(BeTrue())
+		upstream, err := providerService.Find(mgr.GetContext(), &iamoidcprovider.FindOptions{URL: instance.Spec.URL})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(upstream.Arn).Should(Equal(instance.Status.Arn))
+		Expect(upstream.ClientIDs).Should(ConsistOf(v1alpha1.DefaultOIDCClientID))
+	})
+	It("should update the client ids and thumbprints", func() {
+		thumbprint := "9e99a48a9960b14926bb7f3b02e22da2b0ab7280"
+		patch := client.MergeFrom(instance.DeepCopy())
+		instance.Spec.ClientIDs = []string{"sts.amazonaws.com", "kubernetes"}
+		instance.Spec.Thumbprints = []string{thumbprint}
+		Expect(mgr.Uncached().Patch(mgr.GetContext(), instance, patch)).Should(Succeed())
+		Eventually(func() []string {
+			upstream, err := providerService.Get(mgr.GetContext(), &iamoidcprovider.GetOptions{Arn: instance.Status.Arn})
+			Expect(err).ShouldNot(HaveOccurred())
+			return append(upstream.ClientIDs, upstream.Thumbprints...)
+		}).Should(ConsistOf("sts.amazonaws.com", "kubernetes", thumbprint))
+	})
+	It("should resolve the provider arn for the bind manager", func() {
+		provider := &bindmanager.ResourceProvider{Client: mgr.Uncached(), Name: key.Name}
+		Expect(provider.ProviderArn(mgr.GetContext())).Should(Equal(instance.Status.Arn))
+	})
+	It("should delete the upstream provider", func() {
+		Expect(mgr.Uncached().Delete(mgr.GetContext(), instance)).Should(Succeed())
+		Eventually(func() bool {
+			_, err := providerService.Get(mgr.GetContext(), &iamoidcprovider.GetOptions{Arn: instance.Status.Arn})
+			return pkgaws.IsNotFound(err)
+		}).Should(BeTrue())
+	})
+})
@@ -17,11 +17,22 @@ limitations under the License.
 package controllers
 
 import (
+	"bytes"
 	"context"
 	"crypto/md5"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"reflect"
+	"strconv"
+	"time"
 
 	"k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -46,6 +57,17 @@ type IamPolicyReconciler struct {
 	record.EventRecorder
 
 	AWS iampolicy.Interface
+	// Tags builds the tags for the upstream policy
+	Tags Tagger
+	// DefaultDeletionPolicy is used for policies that don't set a deletion
+	// policy. Policies are deleted when it's empty
+	DefaultDeletionPolicy v1alpha1.DeletionPolicy
+	// TemplateVariables are the built in variables of policy templates
+	TemplateVariables TemplateVariables
+	// DefaultVersionLimit is the number of versions kept for policies that
+	// don't set a version limit. Only the default version is kept when it's
+	// zero
+	DefaultVersionLimit int
 }
 
 const (
@@ -57,13 +79,21 @@ const (
 //+kubebuilder:rbac:groups=aws.jackhoman.com,resources=iampolicies,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=aws.jackhoman.com,resources=iampolicies/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=aws.jackhoman.com,resources=iampolicies/finalizers,verbs=update
+//+kubebuilder:rbac:groups=aws.jackhoman.com,resources=iampolicytemplates,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 func (r *IamPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	return r.reconcileRequest(ctx, req, &v1alpha1.IamPolicy{})
+}
+
+// reconcileRequest reconciles either policy kind. instance is populated from
+// the request
+func (r *IamPolicyReconciler) reconcileRequest(ctx context.Context, req ctrl.Request, instance v1alpha1.IamPolicyObject) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
 
-	instance := &v1alpha1.IamPolicy{}
 	if err := r.Client.Get(ctx, req.NamespacedName, instance); err != nil {
 		logger.Info("unable to get instance")
 		return ctrl.Result{}, client.IgnoreNotFound(err)
@@ -78,32 +108,72 @@ func (r *IamPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 			return ctrl.Result{}, err
 		}
 	}
+	reconcileErr := r.reconcile(ctx, instance)
+	if err := updateConditions(ctx, r.Client, instance, &instance.GetStatus().ConditionedStatus,
+		readyCondition(len(instance.GetStatus().Arn) > 0 && reasonForError(reconcileErr) != v1alpha1.ReasonConflict, reconcileErr),
+		syncedCondition(reconcileErr),
+	); err != nil {
+		logger.Error(err, "unable to update status conditions")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, requeueError(reconcileErr)
+}
+
+// reconcile converges the upstream policy with the spec
+func (r *IamPolicyReconciler) reconcile(ctx context.Context, instance v1alpha1.IamPolicyObject) error {
+	logger := log.FromContext(ctx)
 	// Create the iam policy
-	options := &iampolicy.GetOptions{Name: instance.GetName()}
-	if len(instance.Status.Arn) > 0 {
+	options := &iampolicy.GetOptions{Name: v1alpha1.UpstreamName(instance)}
+	if value, ok := adoptArn(instance); ok {
+		// Adopted policies can be under any path, so they can only be
+		// found by arn
+		*options = iampolicy.GetOptions{Arn: value}
+	}
+	if len(instance.GetStatus().Arn) > 0 {
 		// Use the arn if it's available. Most of the time it should be.
 		// Using the name to get the arn will be a more expensive operation
-		*options = iampolicy.GetOptions{Arn: instance.Status.Arn}
+		*options = iampolicy.GetOptions{Arn: instance.GetStatus().Arn}
 	}
-	document, err := serializeDocument(instance)
+	document, err := r.policyDocument(ctx, instance)
 	if err != nil {
-		return ctrl.Result{}, err
+		var documentNotFound DocumentNotFoundError
+		if errors.As(err, &documentNotFound) {
+			r.Event(instance, v1.EventTypeWarning, v1alpha1.ReasonDocumentNotFound, err.Error())
+		}
+		return err
+	}
+	documents, err := iampolicy.Shard(document)
+	if err != nil {
+		if errors.Is(err, iampolicy.ErrStatementTooLarge) {
+			message := fmt.Sprintf("unable to split policy document into managed policies: %s", err)
+			r.Event(instance, v1.EventTypeWarning, v1alpha1.ReasonDocumentTooLarge, message)
+			return NewDocumentTooLarge(message)
+		}
+		return err
 	}
 	sum := md5Sum(document)
+	tags := r.Tags.Tags(instance.GetObjectKind().GroupVersionKind().Kind, instance, instance.GetSpec().Tags)
 	iamPolicy, err := r.AWS.Get(ctx, options)
 	if err != nil {
 		if !aws.IsNotFound(err) {
-			return ctrl.Result{}, err
+			return err
+		}
+		if value, ok := adoptArn(instance); ok {
+			message := fmt.Sprintf("iam policy %s to adopt does not exist", value)
+			r.Event(instance, v1.EventTypeWarning, v1alpha1.ReasonPolicyNotFound, message)
+			return NewPolicyNotFound(message)
 		}
 		// Create it
 		iamPolicy, err = r.AWS.Create(ctx, &iampolicy.CreateOptions{
-			Name:        instance.GetName(),
-			Document:    document,
-			Description: instance.Spec.Description,
+			Name:        v1alpha1.UpstreamName(instance),
+			Document:    documents[0],
+			Description: instance.GetSpec().Description,
+			Path:        instance.GetNamespace(),
+			Tags:        tags,
 		})
 		if err != nil {
 			logger.Error(err, "unable to create iam policy")
-			return ctrl.Result{}, err
+			return err
 		}
 		patch := &unstructured.Unstructured{Object: map[string]interface{}{
 			"status": map[string]interface{}{
@@ -111,20 +181,45 @@ func (r *IamPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 				"md5": sum,
 			},
 		}}
-		patch.SetGroupVersionKind(instance.GroupVersionKind())
+		patch.SetGroupVersionKind(instance.GetObjectKind().GroupVersionKind())
 		patch.SetName(instance.GetName())
+		patch.SetNamespace(instance.GetNamespace())
 		if err := r.Client.Status().Patch(ctx, patch, client.Apply, IamPolicyFieldOwner, client.ForceOwnership); err != nil {
 			logger.Error(err, "unable to patch status on create")
 		}
+		instance.GetStatus().Arn = iamPolicy.Arn
 		r.Eventf(instance, v1.EventTypeNormal, "Created", "Created iam policy %s", iamPolicy.Arn)
+	} else if canAdopt(instance, iamPolicy.Arn, iamPolicy.Tags) {
+		logger.Info("adopting upstream iam policy", "arn", iamPolicy.Arn)
+		r.Eventf(instance, v1.EventTypeNormal, "Adopted", "Adopted iam policy %s", iamPolicy.Arn)
+	} else if !r.Tags.Owns(instance, iamPolicy.Tags) {
+		logger.Info("upstream iam policy is not owned by this resource", "arn", iamPolicy.Arn)
+		message := fmt.Sprintf("iam policy %s exists and is not owned by this resource", iamPolicy.Arn)
+		r.Event(instance, v1.EventTypeWarning, v1alpha1.ReasonConflict, message)
+		return NewConflict(message)
 	}
-	if sum != instance.Status.Md5Sum {
+	// Shards are converged first so statements moved out of the policy are
+	// already in a shard when the policy is updated. They aren't versioned,
+	// so they keep the last applied document while a version is pinned
+	pinned := instance.GetSpec().PinnedVersion
+	if len(pinned) == 0 {
+		if err := r.reconcileShards(ctx, instance, iamPolicy.Name, documents[1:], tags); err != nil {
+			logger.Error(err, "unable to reconcile iam policy shards")
+			return err
+		}
+	}
+	if len(pinned) > 0 {
+		if err := r.pinVersion(ctx, instance, iamPolicy, pinned); err != nil {
+			return err
+		}
+	} else if sum != instance.GetStatus().Md5Sum {
 		iamPolicy, err = r.AWS.Update(ctx, &iampolicy.UpdateOptions{
-			Arn:      iamPolicy.Arn,
-			Document: document,
+			Arn:          iamPolicy.Arn,
+			Document:     documents[0],
+			VersionLimit: r.versionLimit(instance),
 		})
 		if err != nil {
-			return ctrl.Result{}, err
+			return err
 		}
 		patch := &unstructured.Unstructured{Object: map[string]interface{}{
 			"status": map[string]interface{}{
@@ -132,26 +227,51 @@ func (r *IamPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 				"md5": sum,
 			},
 		}}
-		patch.SetGroupVersionKind(instance.GroupVersionKind())
+		patch.SetGroupVersionKind(instance.GetObjectKind().GroupVersionKind())
 		patch.SetName(instance.GetName())
+		patch.SetNamespace(instance.GetNamespace())
 		if err := r.Status().Patch(ctx, patch, client.Apply, IamPolicyFieldOwner, client.ForceOwnership); err != nil {
 			logger.Error(err, "unable to update status after policy document update")
-			return ctrl.Result{}, err
+			return err
 		}
+		instance.GetStatus().Arn = iamPolicy.Arn
+		instance.GetStatus().Md5Sum = sum
 		r.Eventf(instance, v1.EventTypeNormal, "Updated", "Updated iam policy %s", iamPolicy.Arn)
 	}
+	if err := r.updateTags(ctx, instance, iamPolicy, tags); err != nil {
+		return err
+	}
+	// The shards are tagged before the policy, so the managed tags are
+	// recorded once every upstream policy has them
+	if err := updateManagedTags(ctx, r.Client, instance, &instance.GetStatus().ManagedTags, instance.GetSpec().Tags); err != nil {
+		logger.Error(err, "unable to update managed tags")
+		return err
+	}
+	if err := r.updateVersions(ctx, instance, iamPolicy.Arn); err != nil {
+		logger.Error(err, "unable to update policy versions")
+		return err
+	}
 
-	matchingRolesList := &v1alpha1.IamRoleList{}
-	if err := r.Client.List(ctx, matchingRolesList, client.MatchingFields{"spec.policyRefs": instance.GetName()}); err != nil {
+	var matchingRolesList client.ObjectList = &v1alpha1.IamRoleList{}
+	if len(instance.GetNamespace()) > 0 {
+		matchingRolesList = &v1alpha1.NamespacedIamRoleList{}
+	}
+	if err := r.Client.List(ctx, matchingRolesList,
+		client.InNamespace(instance.GetNamespace()),
+		client.MatchingFields{"spec.policyRefs": instance.GetName()},
+	); err != nil {
 		logger.Error(err, "unable to list iam roles")
 	}
-	items := matchingRolesList.Items
+	items, err := meta.ExtractList(matchingRolesList)
+	if err != nil {
+		return err
+	}
 	referenced := sets.NewString()
 	existing := sets.NewString()
 	for _, role := range items {
-		referenced.Insert(role.GetName())
+		referenced.Insert(role.(client.Object).GetName())
 	}
-	for _, role := range instance.Status.AttachedRoles {
+	for _, role := range instance.GetStatus().AttachedRoles {
 		existing.Insert(role.Name)
 	}
 	if referenced.Difference(existing).Len() > 0 || existing.Difference(referenced).Len() > 0 {
@@ -160,35 +280,271 @@ func (r *IamPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		for _, name := range referenced.List() {
 			refs = append(refs, v1.ObjectReference{Name: name})
 		}
-		patch := client.MergeFrom(instance.DeepCopy())
-		instance.Status.AttachedRoles = refs
+		patch := client.MergeFrom(instance.DeepCopyObject().(client.Object))
+		instance.GetStatus().AttachedRoles = refs
 		// TODO: switch to SSA
 		if err := r.Client.Status().Patch(ctx, instance, patch); err != nil {
 			logger.Error(err, "unable to updated attached roles")
-			return ctrl.Result{}, err
+			return err
 		}
 		logger.Info("finished sync")
 	}
+	return nil
+}
 
-	return ctrl.Result{}, nil
+// versionLimit returns the number of versions to keep for the policy
+func (r *IamPolicyReconciler) versionLimit(instance v1alpha1.IamPolicyObject) int {
+	if limit := instance.GetSpec().VersionLimit; limit != nil {
+		return int(*limit)
+	}
+	return r.DefaultVersionLimit
+}
+
+// pinVersion sets a previous version as the default version of the policy.
+// The md5 is dropped from the status so the document is applied again once
+// the version is unpinned
+func (r *IamPolicyReconciler) pinVersion(ctx context.Context, instance v1alpha1.IamPolicyObject, iamPolicy *iampolicy.IamPolicy, versionId string) error {
+	logger := log.FromContext(ctx)
+	if iamPolicy.VersionId != versionId {
+		err := r.AWS.SetDefaultVersion(ctx, &iampolicy.SetDefaultVersionOptions{Arn: iamPolicy.Arn, VersionId: versionId})
+		if aws.IsNotFound(err) {
+			message := fmt.Sprintf("version %s of iam policy %s does not exist", versionId, iamPolicy.Arn)
+			r.Event(instance, v1.EventTypeWarning, v1alpha1.ReasonVersionNotFound, message)
+			return NewVersionNotFound(message)
+		}
+		if err != nil {
+			logger.Error(err, "unable to set default version", "arn", iamPolicy.Arn, "version", versionId)
+			return err
+		}
+		r.Eventf(instance, v1.EventTypeNormal, "PinnedVersion", "Set version %s as the default version of iam policy %s", versionId, iamPolicy.Arn)
+	}
+	if len(instance.GetStatus().Md5Sum) == 0 {
+		return nil
+	}
+	patch := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"arn": iamPolicy.Arn,
+		},
+	}}
+	patch.SetGroupVersionKind(instance.GetObjectKind().GroupVersionKind())
+	patch.SetName(instance.GetName())
+	patch.SetNamespace(instance.GetNamespace())
+	if err := r.Status().Patch(ctx, patch, client.Apply, IamPolicyFieldOwner, client.ForceOwnership); err != nil {
+		logger.Error(err, "unable to update status after pinning version")
+		return err
+	}
+	instance.GetStatus().Md5Sum = ""
+	return nil
+}
+
+// updateVersions records the versions of the upstream policy in the status
+func (r *IamPolicyReconciler) updateVersions(ctx context.Context, instance v1alpha1.IamPolicyObject, arn string) error {
+	versions, err := r.AWS.ListVersions(ctx, &iampolicy.ListVersionsOptions{Arn: arn})
+	if err != nil {
+		return err
+	}
+	status := make([]v1alpha1.PolicyVersion, 0, len(versions))
+	for _, version := range versions {
+		status = append(status, v1alpha1.PolicyVersion{
+			VersionId: version.VersionId,
+			// The status is only precise to the second
+			CreateDate: metav1.NewTime(version.CreateDate.Truncate(time.Second)),
+			Default:    version.IsDefault,
+		})
+	}
+	if equality.Semantic.DeepEqual(status, instance.GetStatus().Versions) {
+		return nil
+	}
+	patch := client.MergeFrom(instance.DeepCopyObject().(client.Object))
+	instance.GetStatus().Versions = status
+	return r.Client.Status().Patch(ctx, instance, patch)
+}
+
+// updateTags converges the tags on an upstream policy owned by the instance
+func (r *IamPolicyReconciler) updateTags(ctx context.Context, instance v1alpha1.IamPolicyObject, iamPolicy *iampolicy.IamPolicy, tags map[string]string) error {
+	logger := log.FromContext(ctx)
+	add, remove := diffTags(iamPolicy.Tags, tags, instance.GetStatus().ManagedTags)
+	if len(add) == 0 && len(remove) == 0 {
+		return nil
+	}
+	if len(add) > 0 {
+		if err := r.AWS.Tag(ctx, &iampolicy.TagOptions{Arn: iamPolicy.Arn, Tags: add}); err != nil {
+			logger.Error(err, "unable to tag iam policy", "arn", iamPolicy.Arn)
+			return err
+		}
+	}
+	if len(remove) > 0 {
+		if err := r.AWS.Untag(ctx, &iampolicy.UntagOptions{Arn: iamPolicy.Arn, Keys: remove}); err != nil {
+			logger.Error(err, "unable to untag iam policy", "arn", iamPolicy.Arn)
+			return err
+		}
+	}
+	r.Eventf(instance, v1.EventTypeNormal, "UpdatedTags", "added %d and removed %d tags on %s", len(add), len(remove), iamPolicy.Arn)
+	return nil
+}
+
+// reconcileShards converges the managed policies holding the statements
+// that don't fit in the policy named name. The shard arns are recorded in
+// the status so roles attach them with the policy, and shards left over
+// from a larger document are retired and then deleted
+func (r *IamPolicyReconciler) reconcileShards(ctx context.Context, instance v1alpha1.IamPolicyObject, name string, documents []string, tags map[string]string) error {
+	logger := log.FromContext(ctx)
+	arns := make([]string, 0, len(documents))
+	for k, document := range documents {
+		shardName := iampolicy.ShardName(name, k+1)
+		options := &iampolicy.GetOptions{Name: shardName}
+		if k < len(instance.GetStatus().Shards) {
+			// Shards are named by index, so the status has the arn
+			options = &iampolicy.GetOptions{Arn: instance.GetStatus().Shards[k]}
+		}
+		shard, err := r.AWS.Get(ctx, options)
+		if err != nil && !aws.IsNotFound(err) {
+			return err
+		}
+		if aws.IsNotFound(err) {
+			shard, err = r.AWS.Create(ctx, &iampolicy.CreateOptions{
+				Name:        shardName,
+				Document:    document,
+				Description: instance.GetSpec().Description,
+				Path:        instance.GetNamespace(),
+				Tags:        tags,
+			})
+			if err != nil {
+				logger.Error(err, "unable to create iam policy shard", "name", shardName)
+				return err
+			}
+			r.Eventf(instance, v1.EventTypeNormal, "CreatedShard", "Created iam policy shard %s", shard.Arn)
+		} else if !r.Tags.Owns(instance, shard.Tags) {
+			message := fmt.Sprintf("iam policy %s exists and is not owned by this resource", shard.Arn)
+			r.Event(instance, v1.EventTypeWarning, v1alpha1.ReasonConflict, message)
+			return NewConflict(message)
+		} else {
+			updated, err := r.AWS.Update(ctx, &iampolicy.UpdateOptions{
+				Arn:          shard.Arn,
+				Document:     document,
+				VersionLimit: r.versionLimit(instance),
+			})
+			if err != nil {
+				logger.Error(err, "unable to update iam policy shard", "arn", shard.Arn)
+				return err
+			}
+			if updated.VersionId != shard.VersionId {
+				r.Eventf(instance, v1.EventTypeNormal, "UpdatedShard", "Updated iam policy shard %s", shard.Arn)
+			}
+			if err := r.updateTags(ctx, instance, shard, tags); err != nil {
+				return err
+			}
+		}
+		arns = append(arns, shard.Arn)
+	}
+	// The shards left over from a larger document are retired instead of
+	// deleted right away so the roles detach them first
+	retired := sets.NewString(instance.GetStatus().RetiredShards...)
+	retired.Insert(instance.GetStatus().Shards...)
+	retired.Delete(arns...)
+	if err := r.updateShards(ctx, instance, arns, retired.List()); err != nil {
+		logger.Error(err, "unable to update shards")
+		return err
+	}
+	// Deletion is retried until the roles have detached the retired shards
+	for _, arn := range instance.GetStatus().RetiredShards {
+		shard, err := r.AWS.Get(ctx, &iampolicy.GetOptions{Arn: arn})
+		if err != nil && !aws.IsNotFound(err) {
+			return err
+		}
+		if err == nil && r.Tags.Owns(instance, shard.Tags) {
+			if err := r.AWS.Delete(ctx, &iampolicy.DeleteOptions{Arn: arn}); err != nil {
+				logger.Error(err, "unable to delete iam policy shard", "arn", arn)
+				return err
+			}
+			r.Eventf(instance, v1.EventTypeNormal, "DeletedShard", "Deleted iam policy shard %s", arn)
+		}
+		retired.Delete(arn)
+	}
+	return r.updateShards(ctx, instance, arns, retired.List())
+}
+
+// updateShards patches the shards and retired shards in the status when
+// they changed
+func (r *IamPolicyReconciler) updateShards(ctx context.Context, instance v1alpha1.IamPolicyObject, shards []string, retired []string) error {
+	status := instance.GetStatus()
+	if len(shards) == 0 {
+		shards = nil
+	}
+	if len(retired) == 0 {
+		retired = nil
+	}
+	if reflect.DeepEqual(shards, status.Shards) && reflect.DeepEqual(retired, status.RetiredShards) {
+		return nil
+	}
+	patch := client.MergeFrom(instance.DeepCopyObject().(client.Object))
+	status.Shards = shards
+	status.RetiredShards = retired
+	return r.Client.Status().Patch(ctx, instance, patch)
+}
+
+// finalizeShards deletes or retains the shards of the policy the same way
+// as the policy itself
+func (r *IamPolicyReconciler) finalizeShards(ctx context.Context, instance v1alpha1.IamPolicyObject) error {
+	logger := log.FromContext(ctx)
+	retain := deletionPolicy(instance, instance.GetSpec().DeletionPolicy, r.DefaultDeletionPolicy) == v1alpha1.DeletionPolicyRetain
+	shards := sets.NewString(instance.GetStatus().Shards...).Insert(instance.GetStatus().RetiredShards...)
+	for _, arn := range shards.List() {
+		shard, err := r.AWS.Get(ctx, &iampolicy.GetOptions{Arn: arn})
+		if aws.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !r.Tags.Owns(instance, shard.Tags) {
+			continue
+		}
+		if retain {
+			if err := r.AWS.Untag(ctx, &iampolicy.UntagOptions{Arn: arn, Keys: ownershipTagKeys}); err != nil {
+				logger.Error(err, "unable to remove ownership tags", "arn", arn)
+				return err
+			}
+			r.Eventf(instance, v1.EventTypeNormal, "Retained", "Retained iam policy shard %s", arn)
+			continue
+		}
+		if err := r.AWS.Delete(ctx, &iampolicy.DeleteOptions{Arn: arn}); err != nil {
+			logger.Error(err, "unable to delete iam policy shard", "arn", arn)
+			return err
+		}
+		r.Eventf(instance, v1.EventTypeNormal, "DeletedShard", "Deleted iam policy shard %s", arn)
+	}
+	return nil
 }
 
 func (r *IamPolicyReconciler) Finalize(ctx context.Context, obj client.Object) (ctrl.Result, error) {
-	instance := obj.(*v1alpha1.IamPolicy)
+	instance := obj.(v1alpha1.IamPolicyObject)
 	logger := log.FromContext(ctx).WithName("iam-policy-reconciler.finalize")
 	if controllerutil.ContainsFinalizer(instance, IamPolicyFinalizer) {
 		// Remove the Iam Policy
 		// - Check the status for an ARN
-		options := &iampolicy.GetOptions{Arn: instance.Status.Arn}
-		if len(instance.Status.Arn) == 0 {
-			options = &iampolicy.GetOptions{Name: instance.GetName()}
+		options := &iampolicy.GetOptions{Arn: instance.GetStatus().Arn}
+		if len(instance.GetStatus().Arn) == 0 {
+			options = &iampolicy.GetOptions{Name: v1alpha1.UpstreamName(instance)}
 		}
 		iamPolicy, err := r.AWS.Get(ctx, options)
 		if err != nil && !aws.IsNotFound(err) {
 			logger.Error(err, "unable to get iam policy for deletion")
 			return ctrl.Result{}, err
 		} else {
-			if !aws.IsNotFound(err) {
+			if !aws.IsNotFound(err) && !r.Tags.Owns(instance, iamPolicy.Tags) {
+				// The policy wasn't created for this resource so leave it in place
+				logger.Info("upstream iam policy is not owned by this resource, skipping deletion", "arn", iamPolicy.Arn)
+				r.Eventf(instance, v1.EventTypeWarning, v1alpha1.ReasonConflict, "not deleting iam policy %s that is not owned by this resource", iamPolicy.Arn)
+			} else if !aws.IsNotFound(err) && deletionPolicy(instance, instance.GetSpec().DeletionPolicy, r.DefaultDeletionPolicy) == v1alpha1.DeletionPolicyRetain {
+				// Leave the policy in place and remove the ownership marks
+				if err := r.AWS.Untag(ctx, &iampolicy.UntagOptions{Arn: iamPolicy.Arn, Keys: ownershipTagKeys}); err != nil {
+					logger.Error(err, "unable to remove ownership tags", "arn", iamPolicy.Arn)
+					return ctrl.Result{}, err
+				}
+				logger.Info("retained resource", "arn", iamPolicy.Arn)
+				r.Eventf(instance, v1.EventTypeNormal, "Retained", "Retained iam policy %s", iamPolicy.Arn)
+			} else if !aws.IsNotFound(err) {
 				if err := r.AWS.Delete(ctx, &iampolicy.DeleteOptions{Arn: iamPolicy.Arn}); err != nil {
 					logger.Error(err, "unable to delete iam policy", "arn", iamPolicy.Arn)
 					return ctrl.Result{}, err
@@ -197,8 +553,12 @@ func (r *IamPolicyReconciler) Finalize(ctx context.Context, obj client.Object) (
 				r.Eventf(instance, v1.EventTypeNormal, "Deleted", "Deleted iam policy %s", iamPolicy.Arn)
 			}
 		}
+		if err := r.finalizeShards(ctx, instance); err != nil {
+			logger.Error(err, "unable to finalize iam policy shards")
+			return ctrl.Result{}, err
+		}
 
-		patch := client.MergeFrom(instance.DeepCopy())
+		patch := client.MergeFrom(instance.DeepCopyObject().(client.Object))
 		controllerutil.RemoveFinalizer(instance, IamPolicyFinalizer)
 		if err := r.Client.Patch(ctx, instance, patch); err != nil {
 			logger.Error(err, "unable to remove finalizer")
@@ -209,7 +569,7 @@ func (r *IamPolicyReconciler) Finalize(ctx context.Context, obj client.Object) (
 	return ctrl.Result{}, nil
 }
 
-func (r *IamPolicyReconciler) addFinalizer(ctx context.Context, instance *v1alpha1.IamPolicy) error {
+func (r *IamPolicyReconciler) addFinalizer(ctx context.Context, instance v1alpha1.IamPolicyObject) error {
 	logger := log.FromContext(ctx)
 	patch := &unstructured.Unstructured{Object: map[string]interface{}{
 		"metadata": map[string]interface{}{
@@ -217,7 +577,8 @@ func (r *IamPolicyReconciler) addFinalizer(ctx context.Context, instance *v1alph
 		},
 	}}
 	patch.SetName(instance.GetName())
-	patch.SetGroupVersionKind(instance.GroupVersionKind())
+	patch.SetNamespace(instance.GetNamespace())
+	patch.SetGroupVersionKind(instance.GetObjectKind().GroupVersionKind())
 	if err := r.Client.Patch(ctx, patch, client.Apply, client.FieldOwner("aws-iam-policy-controller")); err != nil {
 		logger.Error(err, "unable to patch finalizer", "finalizer", IamPolicyFinalizer)
 		logger.Info("finalizer not added")
@@ -229,122 +590,321 @@ func (r *IamPolicyReconciler) addFinalizer(ctx context.Context, instance *v1alph
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *IamPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.IamRole{}, "spec.policyRefs", func(obj client.Object) []string {
-		role, ok := obj.(*v1alpha1.IamRole)
-		if !ok {
-			return []string{}
-		}
-		matches := make([]string, 0, len(role.Spec.PolicyRefs))
-		for _, ref := range role.Spec.PolicyRefs {
-			matches = append(matches, ref.Name)
-		}
-		return matches
-	}); err != nil {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.IamRole{}, "spec.policyRefs", policyRefs); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.IamPolicy{}, documentConfigMapRefIndex, documentConfigMapRef); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.IamPolicy{}, documentSecretRefIndex, documentSecretRef); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.IamPolicy{}, policyTemplateIndex, policyTemplateRef); err != nil {
 		return err
 	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.IamPolicy{}).
 		Watches(
 			&source.Kind{Type: &v1alpha1.IamRole{}},
+			handler.EnqueueRequestsFromMapFunc(rolePolicyRequests),
+		).
+		Watches(
+			&source.Kind{Type: &v1.ConfigMap{}},
 			handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []ctrl.Request {
-				role := obj.(*v1alpha1.IamRole)
-				rv := make([]ctrl.Request, 0)
-				for _, item := range role.Spec.PolicyRefs {
-					rv = append(rv, ctrl.Request{NamespacedName: types.NamespacedName{Name: item.Name}})
-				}
-				return rv
+				return documentSourceRequests(mgr.GetClient(), obj, documentConfigMapRefIndex, &v1alpha1.IamPolicyList{})
+			}),
+		).
+		Watches(
+			&source.Kind{Type: &v1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []ctrl.Request {
+				return documentSourceRequests(mgr.GetClient(), obj, documentSecretRefIndex, &v1alpha1.IamPolicyList{})
+			}),
+		).
+		Watches(
+			&source.Kind{Type: &v1alpha1.IamPolicyTemplate{}},
+			handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []ctrl.Request {
+				return indexedPolicyRequests(mgr.GetClient(), &v1alpha1.IamPolicyList{}, policyTemplateIndex, obj.GetName())
 			}),
 		).
 		Complete(r)
 }
 
-func toMap(conditions []v1alpha1.Condition) map[string][]string {
-	m := map[string][]string{}
+// policyRefs indexes roles by the policies they reference
+func policyRefs(obj client.Object) []string {
+	role, ok := obj.(v1alpha1.IamRoleObject)
+	if !ok {
+		return []string{}
+	}
+	matches := make([]string, 0, len(role.GetSpec().PolicyRefs))
+	for _, ref := range role.GetSpec().PolicyRefs {
+		matches = append(matches, ref.Name)
+	}
+	return matches
+}
+
+// rolePolicyRequests returns a request for every policy referenced by the
+// role. Policies are in the same namespace as the role
+func rolePolicyRequests(obj client.Object) []ctrl.Request {
+	rv := make([]ctrl.Request, 0)
+	for _, name := range policyRefs(obj) {
+		rv = append(rv, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: name}})
+	}
+	return rv
+}
+
+// conditionMap returns the conditions with the given qualifier keyed by
+// condition key, or nil if there are none
+func conditionMap(conditions []v1alpha1.Condition, qualifier v1alpha1.ConditionQualifier) (map[string][]string, error) {
+	var m map[string][]string
 	for _, condition := range conditions {
-		m[condition.Key] = condition.Values
+		if condition.Qualifier != qualifier {
+			continue
+		}
+		values := make([]string, 0, len(condition.Values))
+		for _, value := range condition.Values {
+			s, err := conditionValue(value)
+			if err != nil {
+				return nil, fmt.Errorf("condition %s: %w", condition.Key, err)
+			}
+			values = append(values, s)
+		}
+		if m == nil {
+			m = map[string][]string{}
+		}
+		m[condition.Key] = values
 	}
-	return m
+	return m, nil
 }
 
-func serializeDocument(instance *v1alpha1.IamPolicy) (string, error) {
+// conditionValue returns the string form of a scalar condition value. IAM
+// compares condition values as strings, so false and "false" are the same
+func conditionValue(value apiextensionsv1.JSON) (string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(value.Raw))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return "", err
+	}
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case json.Number:
+		return v.String(), nil
+	}
+	return "", fmt.Errorf("values must be strings, numbers or booleans, got %s", string(value.Raw))
+}
+
+// toConditions converts the operators with the given qualifier. An empty
+// qualifier selects the unqualified operators
+func toConditions(conditions *v1alpha1.Conditions, qualifier v1alpha1.ConditionQualifier) (*iampolicy.Conditions, error) {
+	var err error
+	toMap := func(conditions []v1alpha1.Condition) map[string][]string {
+		m, e := conditionMap(conditions, qualifier)
+		if e != nil && err == nil {
+			err = e
+		}
+		return m
+	}
+	rv := &iampolicy.Conditions{
+		ArnLike:                           toMap(conditions.ArnLike),
+		ArnLikeIfExists:                   toMap(conditions.ArnLikeIfExists),
+		ArnNotLike:                        toMap(conditions.ArnNotLike),
+		ArnNotLikeIfExists:                toMap(conditions.ArnNotLikeIfExists),
+		BinaryEquals:                      toMap(conditions.BinaryEquals),
+		BinaryEqualsIfExists:              toMap(conditions.BinaryEqualsIfExists),
+		Bool:                              toMap(conditions.Bool),
+		BoolIfExists:                      toMap(conditions.BoolIfExists),
+		DateEquals:                        toMap(conditions.DateEquals),
+		DateEqualsIfExists:                toMap(conditions.DateEqualsIfExists),
+		DateNotEquals:                     toMap(conditions.DateNotEquals),
+		DateNotEqualsIfExists:             toMap(conditions.DateNotEqualsIfExists),
+		DateLessThan:                      toMap(conditions.DateLessThan),
+		DateLessThanIfExists:              toMap(conditions.DateLessThanIfExists),
+		DateLessThanEquals:                toMap(conditions.DateLessThanEquals),
+		DateLessThanEqualsIfExists:        toMap(conditions.DateLessThanEqualsIfExists),
+		DateGreaterThan:                   toMap(conditions.DateGreaterThan),
+		DateGreaterThanIfExists:           toMap(conditions.DateGreaterThanIfExists),
+		DateGreaterThanEquals:             toMap(conditions.DateGreaterThanEquals),
+		DateGreaterThanEqualsIfExists:     toMap(conditions.DateGreaterThanEqualsIfExists),
+		IpAddress:                         toMap(conditions.IpAddress),
+		IpAddressIfExists:                 toMap(conditions.IpAddressIfExists),
+		NotIpAddress:                      toMap(conditions.NotIpAddress),
+		NotIpAddressIfExists:              toMap(conditions.NotIpAddressIfExists),
+		NumericEquals:                     toMap(conditions.NumericEquals),
+		NumericEqualsIfExists:             toMap(conditions.NumericEqualsIfExists),
+		NumericNotEquals:                  toMap(conditions.NumericNotEquals),
+		NumericNotEqualsIfExists:          toMap(conditions.NumericNotEqualsIfExists),
+		NumericLessThan:                   toMap(conditions.NumericLessThan),
+		NumericLessThanIfExists:           toMap(conditions.NumericLessThanIfExists),
+		NumericLessThanEquals:             toMap(conditions.NumericLessThanEquals),
+		NumericLessThanEqualsIfExists:     toMap(conditions.NumericLessThanEqualsIfExists),
+		NumericGreaterThan:                toMap(conditions.NumericGreaterThan),
+		NumericGreaterThanIfExists:        toMap(conditions.NumericGreaterThanIfExists),
+		NumericGreaterThanEquals:          toMap(conditions.NumericGreaterThanEquals),
+		NumericGreaterThanEqualsIfExists:  toMap(conditions.NumericGreaterThanEqualsIfExists),
+		Null:                              toMap(conditions.Null),
+		StringLike:                        toMap(conditions.StringLike),
+		StringLikeIfExists:                toMap(conditions.StringLikeIfExists),
+		StringNotLike:                     toMap(conditions.StringNotLike),
+		StringNotLikeIfExists:             toMap(conditions.StringNotLikeIfExists),
+		StringEquals:                      toMap(conditions.StringEquals),
+		StringEqualsIfExists:              toMap(conditions.StringEqualsIfExists),
+		StringNotEquals:                   toMap(conditions.StringNotEquals),
+		StringNotEqualsIfExists:           toMap(conditions.StringNotEqualsIfExists),
+		StringEqualsIgnoreCase:            toMap(conditions.StringEqualsIgnoreCase),
+		StringEqualsIgnoreCaseIfExists:    toMap(conditions.StringEqualsIgnoreCaseIfExists),
+		StringNotEqualsIgnoreCase:         toMap(conditions.StringNotEqualsIgnoreCase),
+		StringNotEqualsIgnoreCaseIfExists: toMap(conditions.StringNotEqualsIgnoreCaseIfExists),
+	}
+	return rv, err
+}
+
+// toQualifiedConditions returns nil if no operator uses the qualifier so the
+// qualifier is left out of the serialized document
+func toQualifiedConditions(conditions *v1alpha1.Conditions, qualifier v1alpha1.ConditionQualifier) (*iampolicy.Conditions, error) {
+	rv, err := toConditions(conditions, qualifier)
+	if err != nil || reflect.DeepEqual(rv, &iampolicy.Conditions{}) {
+		return nil, err
+	}
+	return rv, nil
+}
+
+// policyDocument returns the serialized document of the policy. Raw documents
+// are normalized so they're written the same way as structured documents
+func (r *IamPolicyReconciler) policyDocument(ctx context.Context, instance v1alpha1.IamPolicyObject) (string, error) {
+	spec := instance.GetSpec()
+	if spec.DocumentFrom != nil {
+		raw, err := readDocumentSource(ctx, r.Client, instance)
+		if err != nil {
+			return "", err
+		}
+		// Unlike rawDocument, the content isn't checked by the webhook
+		if err := iampolicy.ValidateDocument(raw); err != nil {
+			return "", fmt.Errorf("documentFrom: %w", err)
+		}
+		return iampolicy.NormalizeDocument(raw)
+	}
+	if spec.Template != nil {
+		template := &v1alpha1.IamPolicyTemplate{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Name: spec.Template.Name}, template); err != nil {
+			if apierrors.IsNotFound(err) {
+				return "", NewDocumentNotFound(fmt.Sprintf("iam policy template %s does not exist", spec.Template.Name))
+			}
+			return "", err
+		}
+		document, err := renderTemplate(template, instance, r.TemplateVariables)
+		if err != nil {
+			return "", fmt.Errorf("template %s: %w", spec.Template.Name, err)
+		}
+		return serializeDocument(document)
+	}
+	if len(spec.RawDocument) > 0 {
+		return iampolicy.NormalizeDocument(spec.RawDocument)
+	}
+	if spec.Document == nil {
+		return "", fmt.Errorf("one of document, rawDocument, documentFrom or template must be specified")
+	}
+	return serializeDocument(spec.Document)
+}
+
+func serializeDocument(document *v1alpha1.IamPolicyDocument) (string, error) {
 	doc := iampolicy.NewDocument()
-	// TODO: use version
+	if len(document.Version) > 0 {
+		doc.SetVersion(document.Version)
+	}
 	statements := make(
 		[]iampolicy.Statement,
 		0,
-		len(instance.Spec.Document.Statements),
+		len(document.Statements),
 	)
 
-	for _, statement := range instance.Spec.Document.Statements {
+	for k, statement := range document.Statements {
+		if len(statement.Actions) > 0 && len(statement.NotActions) > 0 {
+			return "", fmt.Errorf("statement %d: only one of action or notAction may be specified", k)
+		}
+		if len(statement.Resources) > 0 && len(statement.NotResources) > 0 {
+			return "", fmt.Errorf("statement %d: only one of resource or notResource may be specified", k)
+		}
+		if statement.Principal != nil && statement.NotPrincipal != nil {
+			return "", fmt.Errorf("statement %d: only one of principal or notPrincipal may be specified", k)
+		}
 		var conditions *iampolicy.Conditions
 		if statement.Conditions != nil {
-			conditions = &iampolicy.Conditions{
-				ArnLike:                           toMap(statement.Conditions.ArnLike),
-				ArnLikeIfExists:                   toMap(statement.Conditions.ArnLikeIfExists),
-				ArnNotLike:                        toMap(statement.Conditions.ArnNotLike),
-				ArnNotLikeIfExists:                toMap(statement.Conditions.ArnNotLikeIfExists),
-				BinaryEquals:                      toMap(statement.Conditions.BinaryEquals),
-				BinaryEqualsIfExists:              toMap(statement.Conditions.BinaryEqualsIfExists),
-				Bool:                              toMap(statement.Conditions.Bool),
-				BoolIfExists:                      toMap(statement.Conditions.BoolIfExists),
-				DateEquals:                        toMap(statement.Conditions.DateEquals),
-				DateEqualsIfExists:                toMap(statement.Conditions.DateEqualsIfExists),
-				DateNotEquals:                     toMap(statement.Conditions.DateNotEquals),
-				DateNotEqualsIfExists:             toMap(statement.Conditions.DateNotEqualsIfExists),
-				DateLessThan:                      toMap(statement.Conditions.DateLessThan),
-				DateLessThanIfExists:              toMap(statement.Conditions.DateLessThanIfExists),
-				DateLessThanEquals:                toMap(statement.Conditions.DateLessThanEquals),
-				DateLessThanEqualsIfExists:        toMap(statement.Conditions.DateLessThanEqualsIfExists),
-				DateGreaterThan:                   toMap(statement.Conditions.DateGreaterThan),
-				DateGreaterThanIfExists:           toMap(statement.Conditions.DateGreaterThanIfExists),
-				DateGreaterThanEquals:             toMap(statement.Conditions.DateGreaterThanEquals),
-				DateGreaterThanEqualsIfExists:     toMap(statement.Conditions.DateGreaterThanEqualsIfExists),
-				IpAddress:                         toMap(statement.Conditions.IpAddress),
-				IpAddressIfExists:                 toMap(statement.Conditions.IpAddressIfExists),
-				NotIpAddress:                      toMap(statement.Conditions.NotIpAddress),
-				NotIpAddressIfExists:              toMap(statement.Conditions.NotIpAddressIfExists),
-				NumericEquals:                     toMap(statement.Conditions.NumericEquals),
-				NumericEqualsIfExists:             toMap(statement.Conditions.NumericEqualsIfExists),
-				NumericNotEquals:                  toMap(statement.Conditions.NumericNotEquals),
-				NumericNotEqualsIfExists:          toMap(statement.Conditions.NumericNotEqualsIfExists),
-				NumericLessThan:                   toMap(statement.Conditions.NumericLessThan),
-				NumericLessThanIfExists:           toMap(statement.Conditions.NumericLessThanIfExists),
-				NumericLessThanEquals:             toMap(statement.Conditions.NumericLessThanEquals),
-				NumericLessThanEqualsIfExists:     toMap(statement.Conditions.NumericLessThanEqualsIfExists),
-				NumericGreaterThan:                toMap(statement.Conditions.NumericGreaterThan),
-				NumericGreaterThanIfExists:        toMap(statement.Conditions.NumericGreaterThanIfExists),
-				NumericGreaterThanEquals:          toMap(statement.Conditions.NumericGreaterThanEquals),
-				NumericGreaterThanEqualsIfExists:  toMap(statement.Conditions.NumericGreaterThanEqualsIfExists),
-				Null:                              toMap(statement.Conditions.Null),
-				StringLike:                        toMap(statement.Conditions.StringLike),
-				StringLikeIfExists:                toMap(statement.Conditions.StringLikeIfExists),
-				StringNotLike:                     toMap(statement.Conditions.StringNotLike),
-				StringNotLikeIfExists:             toMap(statement.Conditions.StringNotLikeIfExists),
-				StringEquals:                      toMap(statement.Conditions.StringEquals),
-				StringEqualsIfExists:              toMap(statement.Conditions.StringEqualsIfExists),
-				StringNotEquals:                   toMap(statement.Conditions.StringNotEquals),
-				StringNotEqualsIfExists:           toMap(statement.Conditions.StringNotEqualsIfExists),
-				StringEqualsIgnoreCase:            toMap(statement.Conditions.StringEqualsIgnoreCase),
-				StringEqualsIgnoreCaseIfExists:    toMap(statement.Conditions.StringEqualsIgnoreCaseIfExists),
-				StringNotEqualsIgnoreCase:         toMap(statement.Conditions.StringNotEqualsIgnoreCase),
-				StringNotEqualsIgnoreCaseIfExists: toMap(statement.Conditions.StringNotEqualsIgnoreCaseIfExists),
+			var err error
+			if conditions, err = toConditions(statement.Conditions, ""); err != nil {
+				return "", fmt.Errorf("statement %d: %w", k, err)
+			}
+			if conditions.ForAllValues, err = toQualifiedConditions(statement.Conditions, v1alpha1.ConditionQualifierForAllValues); err != nil {
+				return "", fmt.Errorf("statement %d: %w", k, err)
+			}
+			if conditions.ForAnyValue, err = toQualifiedConditions(statement.Conditions, v1alpha1.ConditionQualifierForAnyValue); err != nil {
+				return "", fmt.Errorf("statement %d: %w", k, err)
 			}
 		}
 
 		statements = append(statements, iampolicy.Statement{
-			Sid:        statement.Sid,
-			Effect:     statement.Effect,
-			Action:     statement.Actions,
-			Resource:   statement.Resources,
-			Conditions: conditions,
+			Sid:          statement.Sid,
+			Effect:       statement.Effect,
+			Principal:    toPrincipal(statement.Principal),
+			NotPrincipal: toPrincipal(statement.NotPrincipal),
+			Action:       toList(statement.Actions),
+			NotAction:    toList(statement.NotActions),
+			Resource:     toList(statement.Resources),
+			NotResource:  toList(statement.NotResources),
+			Conditions:   conditions,
 		})
 	}
 	doc.SetStatements(statements)
-	document, err := doc.Marshal()
+	out, err := doc.Marshal()
 	if err != nil {
 		return "", err
 	}
-	return document, nil
+	return out, nil
+}
+
+// toList returns nil for an empty list so the element is left out of the
+// serialized document
+func toList(values []string) interface{} {
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
+
+// toPrincipal converts the principal to the map form used in policy documents
+func toPrincipal(principal *v1alpha1.Principal) interface{} {
+	if principal == nil {
+		return nil
+	}
+	rv := iampolicy.Principal{}
+	for key, values := range map[string][]string{
+		"AWS":           principal.AWS,
+		"Service":       principal.Service,
+		"Federated":     principal.Federated,
+		"CanonicalUser": principal.CanonicalUser,
+	} {
+		if len(values) > 0 {
+			rv[key] = values
+		}
+	}
+	return rv
+}
+
+// documentsEqual compares two serialized policy documents, ignoring
+// differences in representation such as a single action written as a string
+func documentsEqual(a, b string) bool {
+	docA, err := iampolicy.NewDocumentFromString(a)
+	if err != nil {
+		return false
+	}
+	docB, err := iampolicy.NewDocumentFromString(b)
+	if err != nil {
+		return false
+	}
+	equal, err := docA.Equals(docB)
+	return err == nil && equal
 }
 
 func md5Sum(s string) string {
@@ -27,11 +27,14 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"strings"
 )
 
 const (
@@ -41,8 +44,10 @@ const (
 var _ = Describe("IamPolicyController", func() {
 	var it manager.IntegrationTest
 	var service iampolicy.Interface
+	var iamService *fake.IamService
 	BeforeEach(func() {
-		service = iampolicy.New(fake.NewIamService(), "controller.test")
+		iamService = fake.NewIamService()
+		service = iampolicy.New(iamService, "controller.test")
 		it = manager.IntegrationTestBuilder().
 			WithScheme(scheme.Scheme).
 			Complete(cfg)
@@ -52,6 +57,10 @@ var _ = Describe("IamPolicyController", func() {
 			Scheme:        it.GetScheme(),
 			EventRecorder: it.GetEventRecorderFor("controller.test"),
 			AWS:           service,
+			TemplateVariables: controllers.TemplateVariables{
+				AccountID: "111122223333",
+				Partition: "aws",
+			},
 		}).SetupWithManager(it)
 		Expect(err).ShouldNot(HaveOccurred())
 
@@ -66,7 +75,7 @@ var _ = Describe("IamPolicyController", func() {
 			instance = &awsv1alpha1.IamPolicy{}
 			instance.SetName(key.Name)
 			instance.SetFinalizers([]string{"keep-alive"})
-			instance.Spec.Document = awsv1alpha1.IamPolicyDocument{
+			instance.Spec.Document = &awsv1alpha1.IamPolicyDocument{
 				Statements: []awsv1alpha1.Statement{{
 					Effect:    awsv1alpha1.PolicyStatementEffectAllow,
 					Actions:   []string{"s3:ListBucket", "s3:CreateBucket", "s3:DeleteBucket"},
@@ -99,7 +108,7 @@ var _ = Describe("IamPolicyController", func() {
 			policy.Spec.Document.Statements[0].Conditions = &awsv1alpha1.Conditions{
 				ArnLike: []awsv1alpha1.Condition{{
 					Key:    "aws:SourceArn",
-					Values: []string{"arn:aws:iam::0123456789012:role/iam-user"},
+					Values: []apiextensionsv1.JSON{{Raw: []byte(`"arn:aws:iam::0123456789012:role/iam-user"`)}},
 				}},
 			}
 			Expect(it.Uncached().Patch(it.GetContext(), policy, patch)).Should(Succeed())
@@ -174,6 +183,497 @@ var _ = Describe("IamPolicyController", func() {
 					return err
 				}).Should(HaveOccurred())
 			})
+			When("the deletion policy annotation is Retain", func() {
+				BeforeEach(func() {
+					policy := &awsv1alpha1.IamPolicy{}
+					it.Expect().Get(key, policy).Should(Succeed())
+					patch := client.MergeFrom(policy.DeepCopy())
+					metav1.SetMetaDataAnnotation(&policy.ObjectMeta, awsv1alpha1.AnnotationDeletionPolicy, string(awsv1alpha1.DeletionPolicyRetain))
+					Expect(it.Uncached().Patch(it.GetContext(), policy, patch)).Should(Succeed())
+					it.Eventually().GetWhen(key, &awsv1alpha1.IamPolicy{}, func(obj client.Object) bool {
+						return metav1.HasAnnotation(obj.(*awsv1alpha1.IamPolicy).ObjectMeta, awsv1alpha1.AnnotationDeletionPolicy)
+					}).Should(Succeed())
+				})
+				It("keeps the resource and removes the ownership tags", func() {
+					it.Eventually().GetWhen(key, &awsv1alpha1.IamPolicy{}, func(o client.Object) bool {
+						return !controllerutil.ContainsFinalizer(o, IamPolicyFinalizer)
+					}).Should(Succeed())
+					out, err := service.Get(it.GetContext(), &iampolicy.GetOptions{Arn: upstream.Arn})
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(out.Tags).ShouldNot(HaveKey(controllers.TagKeyOwnerUID))
+					Expect(out.Tags).ShouldNot(HaveKey(controllers.TagKeyOwnerCluster))
+				})
+			})
+		})
+	})
+	When("an existing policy is adopted", func() {
+		var key types.NamespacedName
+		var upstream *iampolicy.IamPolicy
+		BeforeEach(func() {
+			key = types.NamespacedName{Name: fmt.Sprintf("adopt-%s", uuid.New().String()[:8])}
+			var err error
+			// Policies made outside the controller can be under any path
+			upstream, err = iampolicy.New(iamService, "hand-made").Create(it.GetContext(), &iampolicy.CreateOptions{
+				Name:     "Legacy-" + key.Name,
+				Document: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`,
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			instance := &awsv1alpha1.IamPolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        key.Name,
+					Annotations: map[string]string{awsv1alpha1.AnnotationAdoptArn: upstream.Arn},
+				},
+				Spec: awsv1alpha1.IamPolicySpec{
+					Document: &awsv1alpha1.IamPolicyDocument{
+						Statements: []awsv1alpha1.Statement{{
+							Effect:    awsv1alpha1.PolicyStatementEffectAllow,
+							Actions:   []string{"s3:ListBucket"},
+							Resources: []string{"*"},
+						}},
+					},
+				},
+			}
+			it.Eventually().Create(instance).Should(Succeed())
+		})
+		It("should take over the policy and update the document", func() {
+			it.Eventually().GetWhen(key, &awsv1alpha1.IamPolicy{}, func(obj client.Object) bool {
+				return obj.(*awsv1alpha1.IamPolicy).Status.Arn == upstream.Arn
+			}).Should(Succeed())
+			Eventually(func() string {
+				out, err := service.Get(it.GetContext(), &iampolicy.GetOptions{Arn: upstream.Arn})
+				if err != nil {
+					return ""
+				}
+				return out.Document
+			}).Should(ContainSubstring("s3:ListBucket"))
+			out, err := service.Get(it.GetContext(), &iampolicy.GetOptions{Arn: upstream.Arn})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(out.Tags).Should(HaveKey(controllers.TagKeyOwnerUID))
+		})
+	})
+	When("the policy denies everything except some actions", func() {
+		var key types.NamespacedName
+		BeforeEach(func() {
+			key = types.NamespacedName{Name: fmt.Sprintf("deny-%s", uuid.New().String()[:8])}
+			it.Eventually().Create(&awsv1alpha1.IamPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name},
+				Spec: awsv1alpha1.IamPolicySpec{
+					Document: &awsv1alpha1.IamPolicyDocument{
+						Version: "2008-10-17",
+						Statements: []awsv1alpha1.Statement{{
+							Effect:       awsv1alpha1.PolicyStatementEffectDeny,
+							NotActions:   []string{"s3:GetObject"},
+							NotResources: []string{"arn:aws:s3:::bucket/*"},
+						}},
+					},
+				},
+			}).Should(Succeed())
+		})
+		It("should create the policy with the version and negated elements", func() {
+			policy := &awsv1alpha1.IamPolicy{}
+			it.Eventually().GetWhen(key, policy, func(obj client.Object) bool {
+				return len(obj.(*awsv1alpha1.IamPolicy).Status.Arn) > 0
+			}).Should(Succeed())
+			out, err := service.Get(it.GetContext(), &iampolicy.GetOptions{Arn: policy.Status.Arn})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(out.Document).Should(Equal(
+				`{"Version":"2008-10-17","Statement":[{"Effect":"Deny","NotAction":["s3:GetObject"],"NotResource":["arn:aws:s3:::bucket/*"]}]}`,
+			))
+		})
+	})
+	When("the policy uses set qualified condition operators", func() {
+		var key types.NamespacedName
+		BeforeEach(func() {
+			key = types.NamespacedName{Name: fmt.Sprintf("abac-%s", uuid.New().String()[:8])}
+			it.Eventually().Create(&awsv1alpha1.IamPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name},
+				Spec: awsv1alpha1.IamPolicySpec{
+					Document: &awsv1alpha1.IamPolicyDocument{
+						Statements: []awsv1alpha1.Statement{{
+							Effect:    awsv1alpha1.PolicyStatementEffectAllow,
+							Actions:   []string{"ec2:CreateTags"},
+							Resources: []string{"*"},
+							Conditions: &awsv1alpha1.Conditions{
+								StringEquals: []awsv1alpha1.Condition{{
+									Key:       "aws:TagKeys",
+									Values:    []apiextensionsv1.JSON{{Raw: []byte(`"team"`)}, {Raw: []byte(`"env"`)}},
+									Qualifier: awsv1alpha1.ConditionQualifierForAllValues,
+								}},
+							},
+						}},
+					},
+				},
+			}).Should(Succeed())
+		})
+		It("should prefix the operator with the qualifier", func() {
+			policy := &awsv1alpha1.IamPolicy{}
+			it.Eventually().GetWhen(key, policy, func(obj client.Object) bool {
+				return len(obj.(*awsv1alpha1.IamPolicy).Status.Arn) > 0
+			}).Should(Succeed())
+			out, err := service.Get(it.GetContext(), &iampolicy.GetOptions{Arn: policy.Status.Arn})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(out.Document).Should(Equal(
+				`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["ec2:CreateTags"],"Resource":["*"],"Condition":{"ForAllValues:StringEquals":{"aws:TagKeys":["team","env"]}}}]}`,
+			))
+		})
+	})
+	When("the policy uses typed condition values", func() {
+		var key types.NamespacedName
+		BeforeEach(func() {
+			key = types.NamespacedName{Name: fmt.Sprintf("typed-%s", uuid.New().String()[:8])}
+			it.Eventually().Create(&awsv1alpha1.IamPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name},
+				Spec: awsv1alpha1.IamPolicySpec{
+					Document: &awsv1alpha1.IamPolicyDocument{
+						Statements: []awsv1alpha1.Statement{{
+							Effect:    awsv1alpha1.PolicyStatementEffectDeny,
+							Actions:   []string{"s3:*"},
+							Resources: []string{"*"},
+							Conditions: &awsv1alpha1.Conditions{
+								Bool: []awsv1alpha1.Condition{{
+									Key:    "aws:SecureTransport",
+									Values: []apiextensionsv1.JSON{{Raw: []byte(`false`)}},
+								}},
+								NumericGreaterThan: []awsv1alpha1.Condition{{
+									Key:    "s3:max-keys",
+									Values: []apiextensionsv1.JSON{{Raw: []byte(`10`)}},
+								}},
+							},
+						}},
+					},
+				},
+			}).Should(Succeed())
+		})
+		It("should write the values as strings", func() {
+			policy := &awsv1alpha1.IamPolicy{}
+			it.Eventually().GetWhen(key, policy, func(obj client.Object) bool {
+				return len(obj.(*awsv1alpha1.IamPolicy).Status.Arn) > 0
+			}).Should(Succeed())
+			out, err := service.Get(it.GetContext(), &iampolicy.GetOptions{Arn: policy.Status.Arn})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(out.Document).Should(Equal(
+				`{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Action":["s3:*"],"Resource":["*"],"Condition":{"Bool":{"aws:SecureTransport":["false"]},"NumericGreaterThan":{"s3:max-keys":["10"]}}}]}`,
+			))
+		})
+	})
+	When("the policy has a raw document", func() {
+		var key types.NamespacedName
+		BeforeEach(func() {
+			key = types.NamespacedName{Name: fmt.Sprintf("raw-%s", uuid.New().String()[:8])}
+			it.Eventually().Create(&awsv1alpha1.IamPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name},
+				Spec: awsv1alpha1.IamPolicySpec{
+					RawDocument: `{"Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"arn:aws:s3:::bucket/*"}]}`,
+				},
+			}).Should(Succeed())
+		})
+		It("should create the policy with the normalized document", func() {
+			policy := &awsv1alpha1.IamPolicy{}
+			it.Eventually().GetWhen(key, policy, func(obj client.Object) bool {
+				return len(obj.(*awsv1alpha1.IamPolicy).Status.Arn) > 0
+			}).Should(Succeed())
+			out, err := service.Get(it.GetContext(), &iampolicy.GetOptions{Arn: policy.Status.Arn})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(out.Document).Should(Equal(
+				`{"Statement":[{"Effect":"Allow","Action":["s3:GetObject"],"Resource":["arn:aws:s3:::bucket/*"]}]}`,
+			))
+		})
+		It("should update the policy when the raw document changes", func() {
+			policy := &awsv1alpha1.IamPolicy{}
+			it.Eventually().GetWhen(key, policy, func(obj client.Object) bool {
+				return len(obj.(*awsv1alpha1.IamPolicy).Status.Md5Sum) > 0
+			}).Should(Succeed())
+			sum := policy.Status.Md5Sum
+			patch := client.MergeFrom(policy.DeepCopy())
+			policy.Spec.RawDocument = `{"Statement":[{"Effect":"Allow","Action":"s3:*","Resource":"arn:aws:s3:::bucket/*"}]}`
+			Expect(it.Uncached().Patch(it.GetContext(), policy, patch)).Should(Succeed())
+			it.Eventually().GetWhen(key, policy, func(obj client.Object) bool {
+				return obj.(*awsv1alpha1.IamPolicy).Status.Md5Sum != sum
+			}).Should(Succeed())
+			out, err := service.Get(it.GetContext(), &iampolicy.GetOptions{Arn: policy.Status.Arn})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(out.Document).Should(ContainSubstring(`"Action":["s3:*"]`))
+		})
+	})
+	When("the policy keeps previous versions", func() {
+		var key types.NamespacedName
+		var limit int32 = 3
+		original := `{"Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"arn:aws:s3:::bucket/*"}]}`
+		BeforeEach(func() {
+			key = types.NamespacedName{Name: fmt.Sprintf("versions-%s", uuid.New().String()[:8])}
+			it.Eventually().Create(&awsv1alpha1.IamPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name},
+				Spec: awsv1alpha1.IamPolicySpec{
+					RawDocument:  original,
+					VersionLimit: &limit,
+				},
+			}).Should(Succeed())
+			policy := &awsv1alpha1.IamPolicy{}
+			it.Eventually().GetWhen(key, policy, func(obj client.Object) bool {
+				return len(obj.(*awsv1alpha1.IamPolicy).Status.Md5Sum) > 0
+			}).Should(Succeed())
+			patch := client.MergeFrom(policy.DeepCopy())
+			policy.Spec.RawDocument = `{"Statement":[{"Effect":"Allow","Action":"s3:*","Resource":"arn:aws:s3:::bucket/*"}]}`
+			Expect(it.Uncached().Patch(it.GetContext(), policy, patch)).Should(Succeed())
+			it.Eventually().GetWhen(key, policy, func(obj client.Object) bool {
+				return len(obj.(*awsv1alpha1.IamPolicy).Status.Versions) == 2
+			}).Should(Succeed())
+		})
+		It("should list the versions in the status", func() {
+			policy := &awsv1alpha1.IamPolicy{}
+			Expect(it.Uncached().Get(it.GetContext(), key, policy)).Should(Succeed())
+			Expect(policy.Status.Versions[0].VersionId).Should(Equal("v2"))
+			Expect(policy.Status.Versions[0].Default).Should(BeTrue())
+			Expect(policy.Status.Versions[1].VersionId).Should(Equal("v1"))
+			Expect(policy.Status.Versions[1].Default).Should(BeFalse())
+		})
+		It("should roll back to the pinned version", func() {
+			policy := &awsv1alpha1.IamPolicy{}
+			Expect(it.Uncached().Get(it.GetContext(), key, policy)).Should(Succeed())
+			patch := client.MergeFrom(policy.DeepCopy())
+			policy.Spec.PinnedVersion = "v1"
+			Expect(it.Uncached().Patch(it.GetContext(), policy, patch)).Should(Succeed())
+			Eventually(func() string {
+				out, err := service.Get(it.GetContext(), &iampolicy.GetOptions{Arn: policy.Status.Arn})
+				if err != nil {
+					return ""
+				}
+				return out.VersionId
+			}).Should(Equal("v1"))
+			it.Eventually().GetWhen(key, policy, func(obj client.Object) bool {
+				return len(obj.(*awsv1alpha1.IamPolicy).Status.Md5Sum) == 0
+			}).Should(Succeed())
+
+			patch = client.MergeFrom(policy.DeepCopy())
+			policy.Spec.PinnedVersion = ""
+			Expect(it.Uncached().Patch(it.GetContext(), policy, patch)).Should(Succeed())
+			Eventually(func() string {
+				out, err := service.Get(it.GetContext(), &iampolicy.GetOptions{Arn: policy.Status.Arn})
+				if err != nil {
+					return ""
+				}
+				return out.Document
+			}).Should(ContainSubstring(`"Action":["s3:*"]`))
+		})
+		It("should report a pinned version that doesn't exist", func() {
+			policy := &awsv1alpha1.IamPolicy{}
+			Expect(it.Uncached().Get(it.GetContext(), key, policy)).Should(Succeed())
+			patch := client.MergeFrom(policy.DeepCopy())
+			policy.Spec.PinnedVersion = "v9"
+			Expect(it.Uncached().Patch(it.GetContext(), policy, patch)).Should(Succeed())
+			it.Eventually().GetWhen(key, policy, func(obj client.Object) bool {
+				condition := meta.FindStatusCondition(obj.(*awsv1alpha1.IamPolicy).Status.Conditions, awsv1alpha1.ConditionTypeSynced)
+				return condition != nil && condition.Reason == awsv1alpha1.ReasonVersionNotFound
+			}).Should(Succeed())
+		})
+	})
+	When("the policy document is read from a configmap", func() {
+		var key types.NamespacedName
+		var configMap *corev1.ConfigMap
+		BeforeEach(func() {
+			configMap = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("policy-%s", uuid.New().String()[:8])},
+				Data: map[string]string{
+					"policy.json": `{"Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`,
+				},
+			}
+			it.Eventually().Create(configMap).Should(Succeed())
+			key = types.NamespacedName{Name: fmt.Sprintf("configmap-%s", uuid.New().String()[:8])}
+			it.Eventually().Create(&awsv1alpha1.IamPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name},
+				Spec: awsv1alpha1.IamPolicySpec{
+					DocumentFrom: &awsv1alpha1.DocumentSource{
+						ConfigMapKeyRef: &awsv1alpha1.DocumentKeySelector{
+							Name:      configMap.GetName(),
+							Namespace: configMap.GetNamespace(),
+							Key:       "policy.json",
+						},
+					},
+				},
+			}).Should(Succeed())
+		})
+		It("should create the policy with the configmap document", func() {
+			policy := &awsv1alpha1.IamPolicy{}
+			it.Eventually().GetWhen(key, policy, func(obj client.Object) bool {
+				return len(obj.(*awsv1alpha1.IamPolicy).Status.Arn) > 0
+			}).Should(Succeed())
+			out, err := service.Get(it.GetContext(), &iampolicy.GetOptions{Arn: policy.Status.Arn})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(out.Document).Should(Equal(
+				`{"Statement":[{"Effect":"Allow","Action":["s3:GetObject"],"Resource":["*"]}]}`,
+			))
+		})
+		It("should update the policy when the configmap changes", func() {
+			policy := &awsv1alpha1.IamPolicy{}
+			it.Eventually().GetWhen(key, policy, func(obj client.Object) bool {
+				return len(obj.(*awsv1alpha1.IamPolicy).Status.Md5Sum) > 0
+			}).Should(Succeed())
+			sum := policy.Status.Md5Sum
+			patch := client.MergeFrom(configMap.DeepCopy())
+			configMap.Data["policy.json"] = `{"Statement":[{"Effect":"Allow","Action":"s3:*","Resource":"*"}]}`
+			Expect(it.Uncached().Patch(it.GetContext(), configMap, patch)).Should(Succeed())
+			it.Eventually().GetWhen(key, policy, func(obj client.Object) bool {
+				return obj.(*awsv1alpha1.IamPolicy).Status.Md5Sum != sum
+			}).Should(Succeed())
+			out, err := service.Get(it.GetContext(), &iampolicy.GetOptions{Arn: policy.Status.Arn})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(out.Document).Should(ContainSubstring(`"Action":["s3:*"]`))
+		})
+	})
+	When("the policy document source does not exist", func() {
+		var key types.NamespacedName
+		BeforeEach(func() {
+			key = types.NamespacedName{Name: fmt.Sprintf("missing-%s", uuid.New().String()[:8])}
+			it.Eventually().Create(&awsv1alpha1.IamPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name},
+				Spec: awsv1alpha1.IamPolicySpec{
+					DocumentFrom: &awsv1alpha1.DocumentSource{
+						SecretKeyRef: &awsv1alpha1.DocumentKeySelector{
+							Name:      "does-not-exist",
+							Namespace: "default",
+							Key:       "policy.json",
+						},
+					},
+				},
+			}).Should(Succeed())
+		})
+		It("should report the document as not found", func() {
+			policy := &awsv1alpha1.IamPolicy{}
+			it.Eventually().GetWhen(key, policy, func(obj client.Object) bool {
+				condition := meta.FindStatusCondition(obj.(*awsv1alpha1.IamPolicy).Status.Conditions, awsv1alpha1.ConditionTypeSynced)
+				return condition != nil && condition.Reason == awsv1alpha1.ReasonDocumentNotFound
+			}).Should(Succeed())
+			Expect(policy.Status.Arn).Should(BeEmpty())
+		})
+	})
+	When("the policy instantiates a template", func() {
+		var key types.NamespacedName
+		var template *awsv1alpha1.IamPolicyTemplate
+		BeforeEach(func() {
+			template = &awsv1alpha1.IamPolicyTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("s3-read-%s", uuid.New().String()[:8])},
+				Spec: awsv1alpha1.IamPolicyTemplateSpec{
+					Parameters: []awsv1alpha1.TemplateParameter{{Name: "bucket"}},
+					Document: awsv1alpha1.IamPolicyDocument{
+						Statements: []awsv1alpha1.Statement{{
+							Effect:    awsv1alpha1.PolicyStatementEffectAllow,
+							Actions:   []string{"s3:GetObject"},
+							Resources: []string{"arn:${aws.partition}:s3:::${bucket}-${aws.accountId}/${aws:username}/*"},
+						}},
+					},
+				},
+			}
+			it.Eventually().Create(template).Should(Succeed())
+			key = types.NamespacedName{Name: fmt.Sprintf("template-%s", uuid.New().String()[:8])}
+			it.Eventually().Create(&awsv1alpha1.IamPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name},
+				Spec: awsv1alpha1.IamPolicySpec{
+					Template: &awsv1alpha1.PolicyTemplateReference{
+						Name:   template.GetName(),
+						Values: map[string]string{"bucket": "webservice"},
+					},
+				},
+			}).Should(Succeed())
+		})
+		It("should create the policy with the rendered document", func() {
+			policy := &awsv1alpha1.IamPolicy{}
+			it.Eventually().GetWhen(key, policy, func(obj client.Object) bool {
+				return len(obj.(*awsv1alpha1.IamPolicy).Status.Arn) > 0
+			}).Should(Succeed())
+			out, err := service.Get(it.GetContext(), &iampolicy.GetOptions{Arn: policy.Status.Arn})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(out.Document).Should(Equal(
+				`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:GetObject"],"Resource":["arn:aws:s3:::webservice-111122223333/${aws:username}/*"]}]}`,
+			))
+		})
+		It("should update the policy when the template changes", func() {
+			policy := &awsv1alpha1.IamPolicy{}
+			it.Eventually().GetWhen(key, policy, func(obj client.Object) bool {
+				return len(obj.(*awsv1alpha1.IamPolicy).Status.Md5Sum) > 0
+			}).Should(Succeed())
+			sum := policy.Status.Md5Sum
+			patch := client.MergeFrom(template.DeepCopy())
+			template.Spec.Document.Statements[0].Actions = []string{"s3:GetObject", "s3:ListBucket"}
+			Expect(it.Uncached().Patch(it.GetContext(), template, patch)).Should(Succeed())
+			it.Eventually().GetWhen(key, policy, func(obj client.Object) bool {
+				return obj.(*awsv1alpha1.IamPolicy).Status.Md5Sum != sum
+			}).Should(Succeed())
+		})
+	})
+	When("the policy document is too large for a single managed policy", func() {
+		var key types.NamespacedName
+		statements := func(n int) []awsv1alpha1.Statement {
+			items := make([]awsv1alpha1.Statement, 0, n)
+			for k := 0; k < n; k++ {
+				items = append(items, awsv1alpha1.Statement{
+					Sid:       fmt.Sprintf("Bucket%d", k),
+					Effect:    awsv1alpha1.PolicyStatementEffectAllow,
+					Actions:   []string{"s3:GetObject"},
+					Resources: []string{fmt.Sprintf("arn:aws:s3:::%s-%d/*", strings.Repeat("b", 600), k)},
+				})
+			}
+			return items
+		}
+		BeforeEach(func() {
+			key = types.NamespacedName{Name: fmt.Sprintf("large-%s", uuid.New().String()[:8])}
+			it.Eventually().Create(&awsv1alpha1.IamPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name},
+				Spec: awsv1alpha1.IamPolicySpec{
+					Document: &awsv1alpha1.IamPolicyDocument{Statements: statements(20)},
+				},
+			}).Should(Succeed())
+		})
+		It("should split the statements into shards", func() {
+			policy := &awsv1alpha1.IamPolicy{}
+			it.Eventually().GetWhen(key, policy, func(obj client.Object) bool {
+				return len(obj.(*awsv1alpha1.IamPolicy).Status.Arn) > 0 && len(obj.(*awsv1alpha1.IamPolicy).Status.Shards) > 0
+			}).Should(Succeed())
+			count := 0
+			for _, arn := range append([]string{policy.Status.Arn}, policy.Status.Shards...) {
+				out, err := service.Get(it.GetContext(), &iampolicy.GetOptions{Arn: arn})
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(len(out.Document)).Should(BeNumerically("<=", iampolicy.MaxDocumentSize))
+				count = count + strings.Count(out.Document, `"Sid"`)
+			}
+			Expect(count).Should(Equal(20))
+		})
+		It("should keep the shards while a version is pinned", func() {
+			policy := &awsv1alpha1.IamPolicy{}
+			it.Eventually().GetWhen(key, policy, func(obj client.Object) bool {
+				return len(obj.(*awsv1alpha1.IamPolicy).Status.Shards) > 0 && len(obj.(*awsv1alpha1.IamPolicy).Status.Versions) > 0
+			}).Should(Succeed())
+			shards := policy.Status.Shards
+			patch := client.MergeFrom(policy.DeepCopy())
+			policy.Spec.PinnedVersion = "v1"
+			policy.Spec.Document.Statements = statements(1)
+			Expect(it.Uncached().Patch(it.GetContext(), policy, patch)).Should(Succeed())
+			Consistently(func() []string {
+				out := &awsv1alpha1.IamPolicy{}
+				if err := it.Uncached().Get(it.GetContext(), key, out); err != nil {
+					return nil
+				}
+				return out.Status.Shards
+			}).Should(Equal(shards))
+		})
+		It("should remove the shards when the document fits", func() {
+			policy := &awsv1alpha1.IamPolicy{}
+			it.Eventually().GetWhen(key, policy, func(obj client.Object) bool {
+				return len(obj.(*awsv1alpha1.IamPolicy).Status.Shards) > 0
+			}).Should(Succeed())
+			shards := policy.Status.Shards
+			patch := client.MergeFrom(policy.DeepCopy())
+			policy.Spec.Document.Statements = statements(1)
+			Expect(it.Uncached().Patch(it.GetContext(), policy, patch)).Should(Succeed())
+			it.Eventually().GetWhen(key, policy, func(obj client.Object) bool {
+				status := obj.(*awsv1alpha1.IamPolicy).Status
+				return len(status.Shards) == 0 && len(status.RetiredShards) == 0
+			}).Should(Succeed())
+			for _, arn := range shards {
+				Eventually(func() error {
+					_, err := service.Get(it.GetContext(), &iampolicy.GetOptions{Arn: arn})
+					return err
+				}).ShouldNot(Succeed())
+			}
 		})
 	})
 })
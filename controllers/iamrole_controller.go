@@ -18,15 +18,18 @@ package controllers
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"reflect"
 
 	"github.com/prometheus/client_golang/prometheus"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -35,7 +38,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
-	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	"github.com/johnhoman/aws-iam-controller/api/v1alpha1"
 	pkgaws "github.com/johnhoman/aws-iam-controller/pkg/aws"
 	"github.com/johnhoman/aws-iam-controller/pkg/aws/iamrole"
@@ -47,6 +51,10 @@ const (
 	PrometheusSubsystem                   = "role_reconciler"
 	Finalizer                             = "jackhoman.com/delete-iam-role"
 	FieldOwner          client.FieldOwner = "aws-iam-controller"
+
+	iamRoleRefIndex                   = "spec.iamRoleRef.name"
+	namespacedIamRoleRefIndex         = "spec.namespacedIamRoleRef.name"
+	permissionsBoundaryPolicyRefIndex = "spec.permissionsBoundary.policyRef.name"
 )
 
 var (
@@ -131,6 +139,14 @@ type IamRoleReconciler struct {
 	notify        Notifier
 	RoleService   iamrole.Interface
 	DefaultPolicy string
+	// DefaultPermissionsBoundary is the policy arn used as the permissions
+	// boundary for roles that don't specify one
+	DefaultPermissionsBoundary string
+	// Tags builds the tags for the upstream role
+	Tags Tagger
+	// DefaultDeletionPolicy is used for roles that don't set a deletion
+	// policy. Roles are deleted when it's empty
+	DefaultDeletionPolicy v1alpha1.DeletionPolicy
 	bindmanager.Manager
 }
 
@@ -138,6 +154,9 @@ type IamRoleReconciler struct {
 //+kubebuilder:rbac:groups=aws.jackhoman.com,resources=iamroles,verbs=get;list;watch;create;update;patch;delete;
 //+kubebuilder:rbac:groups=aws.jackhoman.com,resources=iamroles/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=aws.jackhoman.com,resources=iamroles/finalizers,verbs=update
+//+kubebuilder:rbac:groups=aws.jackhoman.com,resources=iampolicies,verbs=get;list;watch
+//+kubebuilder:rbac:groups=aws.jackhoman.com,resources=iaminstanceprofiles,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -145,15 +164,20 @@ type IamRoleReconciler struct {
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.10.0/pkg/reconcile
 func (r *IamRoleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	return r.reconcileRequest(ctx, req, &v1alpha1.IamRole{})
+}
+
+// reconcileRequest reconciles either role kind. instance is populated from
+// the request
+func (r *IamRoleReconciler) reconcileRequest(ctx context.Context, req ctrl.Request, instance v1alpha1.IamRoleObject) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
 
-	instance := &v1alpha1.IamRole{}
 	if err := r.Client.Get(ctx, req.NamespacedName, instance); err != nil {
 		logger.Error(err, "unable to get instance")
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
-	if !instance.DeletionTimestamp.IsZero() {
+	if !instance.GetDeletionTimestamp().IsZero() {
 		logger.Info("instance pending deletion")
 		// Delete resources
 		if err := r.Finalize(ctx, instance); err != nil {
@@ -183,19 +207,53 @@ func (r *IamRoleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		logger.Info("added finalizer")
 	}
 
-	logger = logger.WithValues("RoleName", instance.GetName())
+	logger = logger.WithValues("RoleName", upstreamRoleName(instance))
 	logger.Info("reconciling iam role")
+	reconcileErr := r.reconcile(ctx, instance)
+	reason := reasonForError(reconcileErr)
+	available := len(instance.GetStatus().RoleArn) > 0 &&
+		reason != v1alpha1.ReasonConflict &&
+		reason != v1alpha1.ReasonPermissionsBoundaryRequired
+	if err := updateConditions(ctx, r.Client, instance, &instance.GetStatus().ConditionedStatus,
+		readyCondition(available, reconcileErr),
+		syncedCondition(reconcileErr),
+	); err != nil {
+		logger.Error(err, "unable to update status conditions")
+		return ctrl.Result{}, err
+	}
+	if reconcileErr != nil {
+		return ctrl.Result{RequeueAfter: requeueAfter(reconcileErr)}, requeueError(reconcileErr)
+	}
+
+	logger.Info("Reconcile complete")
+	return ctrl.Result{}, nil
+}
+
+// reconcile converges the upstream role with the spec
+func (r *IamRoleReconciler) reconcile(ctx context.Context, instance v1alpha1.IamRoleObject) error {
+	logger := log.FromContext(ctx).WithValues("RoleName", upstreamRoleName(instance))
+	boundary, err := r.permissionsBoundary(ctx, instance)
+	if err != nil {
+		logger.Error(err, "unable to resolve permissions boundary")
+		r.Eventf(instance, corev1.EventTypeWarning, "InvalidPermissionsBoundary", "unable to resolve permissions boundary: %s", err)
+		return err
+	}
 	upstream := &iamrole.IamRole{}
-	out, err := r.RoleService.Get(ctx, &iamrole.GetOptions{Name: instance.GetName()})
+	out, err := r.RoleService.Get(ctx, &iamrole.GetOptions{Name: upstreamRoleName(instance)})
 	if err != nil {
 		if !pkgaws.IsNotFound(err) {
-			return ctrl.Result{}, err
+			return err
 		}
 		logger.Info("upstream iam role not found")
-		out, err := r.createIamRole(ctx, instance)
+		if value, ok := adoptArn(instance); ok {
+			message := fmt.Sprintf("iam role %s to adopt does not exist", value)
+			r.Event(instance, corev1.EventTypeWarning, v1alpha1.ReasonRoleNotFound, message)
+			return NewRoleNotFound(message)
+		}
+		out, err := r.createIamRole(ctx, instance, boundary)
 		if err != nil {
 			logger.Error(err, "unable to create iam role")
-			return ctrl.Result{}, err
+			return err
 		}
 		r.notify.Created(out.Name)
 		*upstream = *out
@@ -203,82 +261,178 @@ func (r *IamRoleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	} else {
 		*upstream = *out
 		logger.Info("upstream iam role exists", "arn", upstream.Arn)
-	}
-	// Need attached policies
-	policies, err := r.RoleService.ListAttachedPolicies(ctx, &iamrole.ListOptions{Name: instance.GetName()})
-	if err != nil {
-		logger.Error(err, "unable to list attached policies")
-		return ctrl.Result{}, err
-	}
-	// policies attached to the role from aws
-	attachments := policies.ToMap()
-	for _, ref := range instance.Spec.PolicyRefs {
-		if attachments.Contains(ref.Name) {
-			// If it's reference and attached then we don't need to add it,
-			//   and we also don't need to remove it
-			attachments.Delete(ref.Name)
-		} else {
-			// Policy isn't attach -- attach it
-			refLog := logger.WithValues("policyRef", ref.Name)
-			policy := &v1alpha1.IamPolicy{}
-			if err := r.Client.Get(ctx, types.NamespacedName{Name: ref.Name}, policy); err != nil {
-				logger.Error(err, fmt.Sprintf("unable to get referenced policy %s", ref.Name))
-			} else {
-				arn := policy.Status.Arn
-				if len(arn) == 0 {
-					r.Event(instance, corev1.EventTypeWarning, "InvalidPolicy", "Cannot attach policy with missing policy arn")
-				} else {
-					options := &iamrole.AttachOptions{Name: instance.GetName(), PolicyArn: arn}
-					if err := r.RoleService.AttachPolicy(ctx, options); err != nil {
-						T := &iamtypes.NoSuchEntityException{}
-						if errors.As(err, &T) {
-							r.Eventf(instance, corev1.EventTypeNormal, "PolicyNotFound", "policy %s does not exist", arn)
-						} else {
-							refLog.Error(err, "unable to attach policy")
-						}
-					} else {
-						r.Eventf(instance, corev1.EventTypeNormal, "AttachedPolicy", "attached policy %s to role", arn)
-					}
-				}
-			}
+		if canAdopt(instance, upstream.Arn, upstream.Tags) {
+			logger.Info("adopting upstream iam role")
+			r.Eventf(instance, corev1.EventTypeNormal, "Adopted", "adopted iam role %s", upstream.Arn)
+		} else if !r.Tags.Owns(instance, upstream.Tags) {
+			logger.Info("upstream iam role is not owned by this resource")
+			message := fmt.Sprintf("iam role %s exists and is not owned by this resource", upstream.Arn)
+			r.Event(instance, corev1.EventTypeWarning, v1alpha1.ReasonConflict, message)
+			return NewConflict(message)
 		}
 	}
-	// The remaining policies should be detached because they aren't reference
-	// by the role
-	for _, arn := range attachments {
-		options := &iamrole.DetachOptions{Name: instance.GetName(), PolicyArn: arn}
-		if err := r.RoleService.DetachPolicy(ctx, options); err != nil {
-			logger.Error(err, "unable to detach policy", "arn", arn)
+	if upstream.PermissionsBoundary != boundary {
+		logger.Info("permissions boundary out of sync", "have", upstream.PermissionsBoundary, "want", boundary)
+		if err := r.updatePermissionsBoundary(ctx, instance, boundary); err != nil {
+			logger.Error(err, "unable to update permissions boundary")
+			return err
 		}
-		r.Eventf(instance, corev1.EventTypeNormal, "DetachPolicy", "detached policy %s", arn)
-		logger.Info("detached non-referenced policy", "arn", arn)
+		r.notify.Updated(upstreamRoleName(instance))
+	}
+	if err := r.updateIamRole(ctx, instance, upstream); err != nil {
+		logger.Error(err, "unable to update iam role")
+		return err
+	}
+	if err := r.reconcileTags(ctx, instance, upstream); err != nil {
+		logger.Error(err, "unable to update tags")
+		return err
+	}
+	if err := r.reconcilePolicies(ctx, instance); err != nil {
+		logger.Error(err, "unable to reconcile attached policies")
+		return err
+	}
+	if err := r.reconcileInlinePolicies(ctx, instance); err != nil {
+		logger.Error(err, "unable to reconcile inline policies")
+		return err
 	}
 
-	if instance.Status.RoleArn != upstream.Arn {
-		logger.Info("Status out of sync", "have", instance.Status.RoleArn, "want", upstream.Arn)
-		patch := client.MergeFrom(instance.DeepCopy())
-		instance.Status.RoleArn = upstream.Arn
+	if instance.GetStatus().RoleArn != upstream.Arn {
+		logger.Info("Status out of sync", "have", instance.GetStatus().RoleArn, "want", upstream.Arn)
+		patch := client.MergeFrom(instance.DeepCopyObject().(client.Object))
+		instance.GetStatus().RoleArn = upstream.Arn
 		if err := r.Client.Status().Patch(ctx, instance, patch); err != nil {
 			logger.Error(err, "unable to update status")
-			return ctrl.Result{}, err
+			return err
 		}
 		logger.Info("Status updated")
 	}
 	if err := r.updateTrustPolicy(ctx, instance); err != nil {
 		logger.Error(err, "unable to update trust policy")
-		return ctrl.Result{}, err
+		return err
 	}
+	return nil
+}
 
-	logger.Info("Reconcile complete")
-	return ctrl.Result{}, nil
+// reconcilePolicies attaches the referenced policies, their shards and the
+// policies from the spec arns to the role and detaches every other policy.
+// Policies from the spec arns aren't managed by the controller, so they're
+// attached but never deleted
+func (r *IamRoleReconciler) reconcilePolicies(ctx context.Context, instance v1alpha1.IamRoleObject) error {
+	name := upstreamRoleName(instance)
+	policies, err := r.RoleService.ListAttachedPolicies(ctx, &iamrole.ListOptions{Name: name})
+	if err != nil {
+		return err
+	}
+	// Policies on different paths can share a name, so attachments are
+	// tracked by arn
+	attached := sets.NewString()
+	for _, policy := range policies {
+		attached.Insert(policy.Arn)
+	}
+	arns := instance.GetSpec().PolicyArns
+	if len(instance.GetNamespace()) > 0 {
+		// Namespaced roles can only attach policies from their own namespace
+		arns = make([]string, 0, len(instance.GetSpec().PolicyArns))
+		for _, value := range instance.GetSpec().PolicyArns {
+			if !v1alpha1.InNamespacePath(value, "policy", instance.GetNamespace()) {
+				r.Eventf(instance, corev1.EventTypeWarning, "InvalidPolicyArn", "policy %s is not in the path %s",
+					value, v1alpha1.NamespacePath(instance.GetNamespace()))
+				continue
+			}
+			arns = append(arns, value)
+		}
+	}
+	want, missing, err := policyArns(ctx, r.Client, instance.GetNamespace(), instance.GetSpec().PolicyRefs, arns)
+	if err != nil {
+		return err
+	}
+	// A referenced policy that's already attached stays attached until the
+	// policy has an arn again
+	pending := make([]string, 0, len(missing))
+	for _, ref := range missing {
+		if value, ok := attachedPolicyArn(instance, policies, ref); ok {
+			want.Insert(value)
+			continue
+		}
+		pending = append(pending, ref)
+	}
+	notFound, err := syncAttachments(r.EventRecorder, instance, attached, want,
+		func(arn string) error {
+			return r.RoleService.AttachPolicy(ctx, &iamrole.AttachOptions{Name: name, PolicyArn: arn})
+		},
+		func(arn string) error {
+			return r.RoleService.DetachPolicy(ctx, &iamrole.DetachOptions{Name: name, PolicyArn: arn})
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if pending = append(pending, notFound...); len(pending) > 0 {
+		return policyNotFound(pending, arns)
+	}
+	return nil
+}
+
+// attachedPolicyArn returns the arn of the attached policy the controller
+// created for the referenced policy. It's matched by path as well as name,
+// since policies on other paths, e.g. aws managed policies, can share the
+// name
+func attachedPolicyArn(instance v1alpha1.IamRoleObject, policies iamrole.AttachedPolicies, ref string) (string, bool) {
+	path := "/" + v1alpha1.ResourcePath + "/"
+	if len(instance.GetNamespace()) > 0 {
+		path = v1alpha1.NamespacePath(instance.GetNamespace())
+	}
+	resource := "policy" + path + policyName(instance, ref)
+	for _, policy := range policies {
+		if parsed, err := arn.Parse(policy.Arn); err == nil && parsed.Resource == resource {
+			return policy.Arn, true
+		}
+	}
+	return "", false
 }
 
-func (r *IamRoleReconciler) updateTrustPolicy(ctx context.Context, instance *v1alpha1.IamRole) error {
+// getPolicy returns the policy referenced by the role. Namespaced roles
+// reference NamespacedIamPolicies in the same namespace
+func (r *IamRoleReconciler) getPolicy(ctx context.Context, instance v1alpha1.IamRoleObject, name string) (v1alpha1.IamPolicyObject, error) {
+	var policy v1alpha1.IamPolicyObject = &v1alpha1.IamPolicy{}
+	if len(instance.GetNamespace()) > 0 {
+		policy = &v1alpha1.NamespacedIamPolicy{}
+	}
+	key := types.NamespacedName{Namespace: instance.GetNamespace(), Name: name}
+	if err := r.Client.Get(ctx, key, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// policyKind returns the kind of policy referenced by the role
+func policyKind(instance v1alpha1.IamRoleObject) string {
+	if len(instance.GetNamespace()) > 0 {
+		return v1alpha1.KindNamespacedIamPolicy
+	}
+	return v1alpha1.KindIamPolicy
+}
+
+// policyName returns the upstream name of a policy referenced by the role
+func policyName(instance v1alpha1.IamRoleObject, name string) string {
+	return v1alpha1.UpstreamName(&metav1.PartialObjectMetadata{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: instance.GetNamespace()},
+	})
+}
+
+func (r *IamRoleReconciler) updateTrustPolicy(ctx context.Context, instance v1alpha1.IamRoleObject) error {
 	logger := log.FromContext(ctx).WithValues("method", "UpdateTrustPolicy")
 	logger.Info("updating trust policy for iam role")
 
 	bindings := &v1alpha1.IamRoleBindingList{}
-	if err := r.Client.List(ctx, bindings, client.MatchingFields{"spec.iamRoleRef.name": instance.GetName()}); err != nil {
+	options := []client.ListOption{client.MatchingFields{iamRoleRefIndex: instance.GetName()}}
+	if len(instance.GetNamespace()) > 0 {
+		options = []client.ListOption{
+			client.InNamespace(instance.GetNamespace()),
+			client.MatchingFields{namespacedIamRoleRefIndex: instance.GetName()},
+		}
+	}
+	if err := r.Client.List(ctx, bindings, options...); err != nil {
 		logger.Error(err, "unable to list role bindings")
 		return err
 	}
@@ -286,22 +440,63 @@ func (r *IamRoleReconciler) updateTrustPolicy(ctx context.Context, instance *v1a
 		logger.Info("identified role binding", "bindingName", item.Name)
 	}
 	objectRefs := make([]corev1.ObjectReference, 0, len(bindings.Items))
-	for _, binding := range bindings.Items {
-		objectRefs = append(objectRefs, corev1.ObjectReference{
-			Name:      binding.Spec.ServiceAccountRef.Name,
-			Namespace: binding.GetNamespace(),
-		})
+	seen := sets.NewString()
+	for k := range bindings.Items {
+		binding := &bindings.Items[k]
+		names, err := bindingServiceAccounts(ctx, r.Client, binding)
+		if err != nil {
+			logger.Error(err, "unable to list service accounts", "bindingName", binding.GetName())
+			return err
+		}
+		for _, name := range names {
+			key := types.NamespacedName{Namespace: binding.GetNamespace(), Name: name}
+			if seen.Has(key.String()) {
+				continue
+			}
+			// The binding refuses service accounts that are already annotated
+			// for another role, so the role must not trust them either
+			other, err := boundToOtherRole(ctx, r.Client, key, instance.GetStatus().RoleArn)
+			if err != nil {
+				logger.Error(err, "unable to get service account", "serviceAccountName", name)
+				return err
+			}
+			if other {
+				continue
+			}
+			seen.Insert(key.String())
+			objectRefs = append(objectRefs, corev1.ObjectReference{Name: key.Name, Namespace: key.Namespace})
+		}
+	}
+	services, err := r.trustedServices(ctx, instance)
+	if err != nil {
+		logger.Error(err, "unable to list instance profiles")
+		return err
+	}
+	binding := bindmanager.Binding{
+		Role:            instance,
+		RoleName:        upstreamRoleName(instance),
+		ServiceAccounts: objectRefs,
+		Services:        services,
+		// Only restore the DenyAllAWS statement if it was removed by the
+		// controller
+		DenyAllAWSRemoved: instance.GetStatus().DenyAllAWSRemoved,
+	}
+	if trust := instance.GetSpec().Trust; trust != nil {
+		binding.Principals = r.trustedPrincipals(instance, trust.AWS)
+		binding.Conditions = trust.Conditions
 	}
-	binding := bindmanager.Binding{Role: instance, ServiceAccounts: objectRefs}
 	if err := r.Bind(ctx, &binding); err != nil {
 		logger.Error(err, "unable to bind service account")
 		return err
 	}
-	if !reflect.DeepEqual(instance.Status.BoundServiceAccounts, objectRefs) {
-		patch := client.MergeFrom(instance.DeepCopy())
-		instance.Status.BoundServiceAccounts = objectRefs
+	if !reflect.DeepEqual(instance.GetStatus().BoundServiceAccounts, objectRefs) ||
+		instance.GetStatus().DenyAllAWSRemoved != binding.DenyAllAWSRemoved {
+		patch := client.MergeFrom(instance.DeepCopyObject().(client.Object))
+		instance.GetStatus().BoundServiceAccounts = objectRefs
+		instance.GetStatus().DenyAllAWSRemoved = binding.DenyAllAWSRemoved
 		if err := r.Client.Status().Patch(ctx, instance, patch); err != nil {
 			logger.Error(err, "unable to update status")
+			return err
 		}
 		logger.Info("updated status with role bindings")
 	}
@@ -309,11 +504,234 @@ func (r *IamRoleReconciler) updateTrustPolicy(ctx context.Context, instance *v1a
 	return nil
 }
 
-func (r *IamRoleReconciler) createIamRole(ctx context.Context, instance *v1alpha1.IamRole) (*iamrole.IamRole, error) {
+// boundToOtherRole reports whether the service account is annotated with
+// the arn of a different role
+func boundToOtherRole(ctx context.Context, c client.Client, key types.NamespacedName, roleArn string) (bool, error) {
+	serviceAccount := &corev1.ServiceAccount{}
+	if err := c.Get(ctx, key, serviceAccount); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	existing, ok := serviceAccount.GetAnnotations()[ServiceAccountAnnotation]
+	return ok && existing != roleArn, nil
+}
+
+// trustedPrincipals returns the aws principals the role trusts. Namespaced
+// roles can only trust principals in the account of the role, so tenants
+// can't open a role up to other accounts
+func (r *IamRoleReconciler) trustedPrincipals(instance v1alpha1.IamRoleObject, principals []string) []string {
+	if len(instance.GetNamespace()) == 0 {
+		return principals
+	}
+	role, err := arn.Parse(instance.GetStatus().RoleArn)
+	if err != nil {
+		return nil
+	}
+	trusted := make([]string, 0, len(principals))
+	for _, value := range principals {
+		account := value
+		if parsed, err := arn.Parse(value); err == nil {
+			account = parsed.AccountID
+		}
+		if account != role.AccountID {
+			r.Eventf(instance, corev1.EventTypeWarning, "UntrustedPrincipal",
+				"namespaced roles can only trust principals in account %s, not %s", role.AccountID, value)
+			continue
+		}
+		trusted = append(trusted, value)
+	}
+	return trusted
+}
+
+// trustedServices returns the service principals the role has to trust,
+// which are the services in the spec and EC2 when the role is in an
+// instance profile
+func (r *IamRoleReconciler) trustedServices(ctx context.Context, instance v1alpha1.IamRoleObject) ([]string, error) {
+	services := sets.NewString()
+	if trust := instance.GetSpec().Trust; trust != nil {
+		services.Insert(trust.Services...)
+	}
+	if len(instance.GetNamespace()) > 0 {
+		// Instance profiles can only reference an IamRole
+		return services.List(), nil
+	}
+	profiles := &v1alpha1.IamInstanceProfileList{}
+	if err := r.Client.List(ctx, profiles); err != nil {
+		return nil, err
+	}
+	for _, profile := range profiles.Items {
+		if profile.Spec.RoleRef.Name == instance.GetName() && profile.GetDeletionTimestamp().IsZero() {
+			services.Insert(ServiceEC2)
+			break
+		}
+	}
+	return services.List(), nil
+}
+
+// reconcileInlinePolicies puts every inline policy declared on the role and
+// deletes any upstream inline policy that is no longer declared
+func (r *IamRoleReconciler) reconcileInlinePolicies(ctx context.Context, instance v1alpha1.IamRoleObject) error {
+	logger := log.FromContext(ctx).WithValues("method", "ReconcileInlinePolicies")
+
+	names, err := r.RoleService.ListInlinePolicies(ctx, &iamrole.ListOptions{Name: upstreamRoleName(instance)})
+	if err != nil {
+		logger.Error(err, "unable to list inline policies")
+		return err
+	}
+	existing := sets.NewString(names...)
+	for _, policy := range instance.GetSpec().InlinePolicies {
+		document, err := serializeDocument(&policy.Document)
+		if err != nil {
+			return err
+		}
+		if existing.Has(policy.Name) {
+			existing.Delete(policy.Name)
+			upstream, err := r.RoleService.GetInlinePolicy(ctx, &iamrole.GetInlinePolicyOptions{
+				Name:       upstreamRoleName(instance),
+				PolicyName: policy.Name,
+			})
+			if err != nil {
+				logger.Error(err, "unable to get inline policy", "policyName", policy.Name)
+				return err
+			}
+			if documentsEqual(upstream.Document, document) {
+				continue
+			}
+		}
+		if err := r.RoleService.PutInlinePolicy(ctx, &iamrole.PutInlinePolicyOptions{
+			Name:       upstreamRoleName(instance),
+			PolicyName: policy.Name,
+			Document:   document,
+		}); err != nil {
+			logger.Error(err, "unable to put inline policy", "policyName", policy.Name)
+			return err
+		}
+		r.Eventf(instance, corev1.EventTypeNormal, "PutInlinePolicy", "put inline policy %s", policy.Name)
+		logger.Info("put inline policy", "policyName", policy.Name)
+	}
+	// The remaining inline policies aren't declared on the role
+	for _, name := range existing.List() {
+		if err := r.RoleService.DeleteInlinePolicy(ctx, &iamrole.DeleteInlinePolicyOptions{
+			Name:       upstreamRoleName(instance),
+			PolicyName: name,
+		}); err != nil {
+			logger.Error(err, "unable to delete inline policy", "policyName", name)
+			return err
+		}
+		r.Eventf(instance, corev1.EventTypeNormal, "DeleteInlinePolicy", "deleted inline policy %s", name)
+		logger.Info("deleted undeclared inline policy", "policyName", name)
+	}
+	return nil
+}
+
+// permissionsBoundary resolves the arn of the permissions boundary for the
+// role. An empty arn means the role shouldn't have a boundary
+func (r *IamRoleReconciler) permissionsBoundary(ctx context.Context, instance v1alpha1.IamRoleObject) (string, error) {
+	boundary := instance.GetSpec().PermissionsBoundary
+	// Namespaced roles can't override the default boundary, and without one
+	// tenants could grant themselves anything the controller can
+	if len(instance.GetNamespace()) > 0 {
+		if len(r.DefaultPermissionsBoundary) == 0 {
+			return "", NewPermissionsBoundaryRequired("namespaced roles require the controller to run with --default-permissions-boundary")
+		}
+		return r.DefaultPermissionsBoundary, nil
+	}
+	if boundary == nil {
+		return r.DefaultPermissionsBoundary, nil
+	}
+	if boundary.PolicyRef != nil {
+		policy, err := r.getPolicy(ctx, instance, boundary.PolicyRef.Name)
+		if err != nil {
+			return "", err
+		}
+		if len(policy.GetStatus().Arn) == 0 {
+			return "", NewInvalidPolicyStatus(fmt.Sprintf("%s %s is missing arn from status", policyKind(instance), policy.GetName()))
+		}
+		if len(policy.GetStatus().Shards) > 0 {
+			// A role only has one permissions boundary, so a policy split
+			// into several managed policies can't be used as one
+			return "", NewInvalidPolicyStatus(fmt.Sprintf("%s %s is split into %d managed policies and can't be used as a permissions boundary",
+				policyKind(instance), policy.GetName(), len(policy.GetStatus().Shards)+1))
+		}
+		return policy.GetStatus().Arn, nil
+	}
+	if len(boundary.Arn) > 0 {
+		return boundary.Arn, nil
+	}
+	return r.DefaultPermissionsBoundary, nil
+}
+
+func (r *IamRoleReconciler) updatePermissionsBoundary(ctx context.Context, instance v1alpha1.IamRoleObject, boundary string) error {
+	if len(boundary) == 0 {
+		if err := r.RoleService.DeletePermissionsBoundary(ctx, &iamrole.DeletePermissionsBoundaryOptions{
+			Name: upstreamRoleName(instance),
+		}); err != nil && !pkgaws.IsNotFound(err) {
+			return err
+		}
+		r.Event(instance, corev1.EventTypeNormal, "DeletedPermissionsBoundary", "removed permissions boundary")
+		return nil
+	}
+	if _, err := r.RoleService.Update(ctx, &iamrole.UpdateOptions{
+		Name:                upstreamRoleName(instance),
+		PermissionsBoundary: boundary,
+	}); err != nil {
+		return err
+	}
+	r.Eventf(instance, corev1.EventTypeNormal, "UpdatedPermissionsBoundary", "set permissions boundary %s", boundary)
+	return nil
+}
+
+// updateIamRole reverts changes to the upstream role description and max
+// session duration
+func (r *IamRoleReconciler) updateIamRole(ctx context.Context, instance v1alpha1.IamRoleObject, upstream *iamrole.IamRole) error {
+	options := &iamrole.UpdateOptions{Name: upstreamRoleName(instance)}
+	if upstream.Description != instance.GetSpec().Description {
+		options.Description = aws.String(instance.GetSpec().Description)
+	}
+	if maxDuration := maxDurationSeconds(instance); upstream.MaxDurationSeconds != maxDuration {
+		options.MaxDurationSeconds = maxDuration
+	}
+	if options.Description == nil && options.MaxDurationSeconds == 0 {
+		return nil
+	}
+	log.FromContext(ctx).Info("iam role out of sync",
+		"description", upstream.Description, "maxDurationSeconds", upstream.MaxDurationSeconds)
+	if _, err := r.RoleService.Update(ctx, options); err != nil {
+		return err
+	}
+	r.Event(instance, corev1.EventTypeNormal, "UpdatedIamRole", "updated iam role description and max session duration")
+	r.notify.Updated(upstreamRoleName(instance))
+	return nil
+}
+
+// reconcileTags adds tags missing from the upstream role and removes tags
+// that are no longer wanted
+func (r *IamRoleReconciler) reconcileTags(ctx context.Context, instance v1alpha1.IamRoleObject, upstream *iamrole.IamRole) error {
+	add, remove := diffTags(upstream.Tags, r.Tags.Tags(instance.GetObjectKind().GroupVersionKind().Kind, instance, instance.GetSpec().Tags), instance.GetStatus().ManagedTags)
+	if len(add) > 0 {
+		if err := r.RoleService.Tag(ctx, &iamrole.TagOptions{Name: upstreamRoleName(instance), Tags: add}); err != nil {
+			return err
+		}
+	}
+	if len(remove) > 0 {
+		if err := r.RoleService.Untag(ctx, &iamrole.UntagOptions{Name: upstreamRoleName(instance), Keys: remove}); err != nil {
+			return err
+		}
+	}
+	if len(add) > 0 || len(remove) > 0 {
+		r.Eventf(instance, corev1.EventTypeNormal, "UpdatedTags", "added %d and removed %d tags", len(add), len(remove))
+	}
+	return updateManagedTags(ctx, r.Client, instance, &instance.GetStatus().ManagedTags, instance.GetSpec().Tags)
+}
+
+func (r *IamRoleReconciler) createIamRole(ctx context.Context, instance v1alpha1.IamRoleObject, boundary string) (*iamrole.IamRole, error) {
 	out, err := r.RoleService.Create(ctx, &iamrole.CreateOptions{
-		Name:               instance.GetName(),
-		MaxDurationSeconds: int32(instance.Spec.MaxDurationSeconds),
-		PolicyDocument:     r.DefaultPolicy,
+		Name:                upstreamRoleName(instance),
+		Description:         instance.GetSpec().Description,
+		MaxDurationSeconds:  maxDurationSeconds(instance),
+		PolicyDocument:      r.DefaultPolicy,
+		PermissionsBoundary: boundary,
+		Path:                instance.GetNamespace(),
+		Tags:                r.Tags.Tags(instance.GetObjectKind().GroupVersionKind().Kind, instance, instance.GetSpec().Tags),
 	})
 	if err != nil {
 		return nil, err
@@ -321,7 +739,16 @@ func (r *IamRoleReconciler) createIamRole(ctx context.Context, instance *v1alpha
 	return out, nil
 }
 
-func (r *IamRoleReconciler) addFinalizer(ctx context.Context, instance *v1alpha1.IamRole) error {
+// maxDurationSeconds returns the max session duration from the spec, or the
+// default when the spec doesn't set one
+func maxDurationSeconds(instance v1alpha1.IamRoleObject) int32 {
+	if instance.GetSpec().MaxDurationSeconds == 0 {
+		return v1alpha1.DefaultMaxDurationSeconds
+	}
+	return int32(instance.GetSpec().MaxDurationSeconds)
+}
+
+func (r *IamRoleReconciler) addFinalizer(ctx context.Context, instance v1alpha1.IamRoleObject) error {
 	logger := log.FromContext(ctx).WithValues("method", "AddFinalizer")
 	patch := &unstructured.Unstructured{Object: map[string]interface{}{
 		"metadata": map[string]interface{}{
@@ -329,7 +756,8 @@ func (r *IamRoleReconciler) addFinalizer(ctx context.Context, instance *v1alpha1
 		},
 	}}
 	patch.SetName(instance.GetName())
-	patch.SetGroupVersionKind(instance.GroupVersionKind())
+	patch.SetNamespace(instance.GetNamespace())
+	patch.SetGroupVersionKind(instance.GetObjectKind().GroupVersionKind())
 	if err := r.Client.Patch(ctx, patch, client.Apply, FieldOwner, client.ForceOwnership); err != nil {
 		logger.Error(err, "unable to add finalizer")
 		return err
@@ -338,10 +766,10 @@ func (r *IamRoleReconciler) addFinalizer(ctx context.Context, instance *v1alpha1
 	return nil
 }
 
-func (r *IamRoleReconciler) RemoveFinalizer(ctx context.Context, instance *v1alpha1.IamRole) error {
+func (r *IamRoleReconciler) RemoveFinalizer(ctx context.Context, instance v1alpha1.IamRoleObject) error {
 	logger := log.FromContext(ctx).WithValues("method", "RemoveFinalizer")
 
-	patch := client.MergeFrom(instance.DeepCopy())
+	patch := client.MergeFrom(instance.DeepCopyObject().(client.Object))
 	cu.RemoveFinalizer(instance, Finalizer)
 	if err := r.Client.Patch(ctx, instance, patch, FieldOwner); err != nil {
 		logger.Error(err, "unable to patch finalizers")
@@ -351,37 +779,129 @@ func (r *IamRoleReconciler) RemoveFinalizer(ctx context.Context, instance *v1alp
 	return nil
 }
 
-func (r *IamRoleReconciler) Finalize(ctx context.Context, instance *v1alpha1.IamRole) error {
+func (r *IamRoleReconciler) Finalize(ctx context.Context, instance v1alpha1.IamRoleObject) error {
 	logger := log.FromContext(ctx).WithValues("method", "Finalize")
 	logger.Info("Removing IAM Role")
 
-	out, err := r.RoleService.Get(ctx, &iamrole.GetOptions{Name: instance.GetName()})
+	out, err := r.RoleService.Get(ctx, &iamrole.GetOptions{Name: upstreamRoleName(instance)})
 	if err != nil {
 		if !pkgaws.IsNotFound(err) {
 			return err
 		}
 	} else {
-		if err := r.RoleService.Delete(ctx, &iamrole.DeleteOptions{Name: instance.GetName()}); err != nil {
+		if !r.Tags.Owns(instance, out.Tags) {
+			// The role wasn't created for this resource so leave it in place
+			logger.Info("upstream iam role is not owned by this resource, skipping deletion", "arn", out.Arn)
+			r.Eventf(instance, corev1.EventTypeWarning, v1alpha1.ReasonConflict, "not deleting iam role %s that is not owned by this resource", out.Arn)
+			return nil
+		}
+		if deletionPolicy(instance, instance.GetSpec().DeletionPolicy, r.DefaultDeletionPolicy) == v1alpha1.DeletionPolicyRetain {
+			return r.retain(ctx, instance, out)
+		}
+		// Inline policies have to be removed before the role can be deleted
+		names, err := r.RoleService.ListInlinePolicies(ctx, &iamrole.ListOptions{Name: upstreamRoleName(instance)})
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			if err := r.RoleService.DeleteInlinePolicy(ctx, &iamrole.DeleteInlinePolicyOptions{
+				Name:       upstreamRoleName(instance),
+				PolicyName: name,
+			}); err != nil && !pkgaws.IsNotFound(err) {
+				return err
+			}
+		}
+		// So do managed policies, including the ones attached outside the
+		// controller
+		policies, err := r.RoleService.ListAttachedPolicies(ctx, &iamrole.ListOptions{Name: upstreamRoleName(instance)})
+		if err != nil {
+			return err
+		}
+		for _, policy := range policies {
+			if err := r.RoleService.DetachPolicy(ctx, &iamrole.DetachOptions{
+				Name:      upstreamRoleName(instance),
+				PolicyArn: policy.Arn,
+			}); err != nil && !pkgaws.IsNotFound(err) {
+				return err
+			}
+		}
+		// The role also has to be removed from its instance profiles
+		profiles, err := r.RoleService.ListInstanceProfiles(ctx, &iamrole.ListOptions{Name: upstreamRoleName(instance)})
+		if err != nil {
+			return err
+		}
+		for _, profile := range profiles {
+			if err := r.RoleService.RemoveFromInstanceProfile(ctx, &iamrole.RemoveFromInstanceProfileOptions{
+				Name:                upstreamRoleName(instance),
+				InstanceProfileName: profile,
+			}); err != nil && !pkgaws.IsNotFound(err) {
+				return err
+			}
+		}
+		if err := r.RoleService.Delete(ctx, &iamrole.DeleteOptions{Name: upstreamRoleName(instance)}); err != nil {
 			return err
 		}
-		r.notify.Deleted(instance.GetName())
+		r.notify.Deleted(upstreamRoleName(instance))
 		logger.Info("Removed upstream role", "arn", out.Arn)
 	}
 	return nil
 }
 
+// retain releases the upstream role without deleting it. The trust policy
+// statements, ownership tags and service account annotations added by the
+// controller are removed
+func (r *IamRoleReconciler) retain(ctx context.Context, instance v1alpha1.IamRoleObject, upstream *iamrole.IamRole) error {
+	logger := log.FromContext(ctx).WithValues("method", "Retain")
+
+	binding := bindmanager.Binding{Role: instance, RoleName: upstreamRoleName(instance)}
+	if err := r.Bind(ctx, &binding); err != nil {
+		logger.Error(err, "unable to remove trust policy statements")
+		return err
+	}
+	for _, ref := range instance.GetStatus().BoundServiceAccounts {
+		serviceAccount := &corev1.ServiceAccount{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, serviceAccount); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if serviceAccount.GetAnnotations()[ServiceAccountAnnotation] != upstream.Arn {
+			continue
+		}
+		patch := client.MergeFrom(serviceAccount.DeepCopy())
+		delete(serviceAccount.Annotations, ServiceAccountAnnotation)
+		if err := r.Client.Patch(ctx, serviceAccount, patch); err != nil {
+			logger.Error(err, "unable to remove service account annotation", "serviceAccount", ref)
+			return err
+		}
+	}
+	if err := r.RoleService.Untag(ctx, &iamrole.UntagOptions{
+		Name: upstreamRoleName(instance),
+		Keys: ownershipTagKeys,
+	}); err != nil && !pkgaws.IsNotFound(err) {
+		return err
+	}
+	logger.Info("Retained upstream role", "arn", upstream.Arn)
+	r.Eventf(instance, corev1.EventTypeNormal, "Retained", "retained iam role %s", upstream.Arn)
+	return nil
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *IamRoleReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.notify = &notifier{}
-	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.IamRoleBinding{}, "spec.iamRoleRef.name", func(obj client.Object) []string {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.IamRoleBinding{}, iamRoleRefIndex, func(obj client.Object) []string {
 		binding, ok := obj.(*v1alpha1.IamRoleBinding)
-		if !ok {
+		if !ok || isNamespacedRoleRef(binding) {
 			return []string{}
 		}
 		return []string{binding.Spec.IamRoleRef.Name}
 	}); err != nil {
 		return err
 	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.IamRole{}, permissionsBoundaryPolicyRefIndex, permissionsBoundaryPolicyRef); err != nil {
+		return err
+	}
 
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.IamRole{}).
@@ -389,29 +909,90 @@ func (r *IamRoleReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			&source.Kind{Type: &v1alpha1.IamRoleBinding{}},
 			handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []ctrl.Request {
 				binding, ok := obj.(*v1alpha1.IamRoleBinding)
-				if ok {
+				if ok && !isNamespacedRoleRef(binding) {
 					return []ctrl.Request{{
-						NamespacedName: types.NamespacedName{
-							Name:      binding.Spec.IamRoleRef.Name,
-							Namespace: binding.GetNamespace(),
-						},
+						NamespacedName: types.NamespacedName{Name: binding.Spec.IamRoleRef.Name},
 					}}
 				}
 				return []ctrl.Request{}
 			}),
 		).
 		Watches(
-			&source.Kind{Type: &v1alpha1.IamPolicy{}},
+			&source.Kind{Type: &corev1.ServiceAccount{}},
+			handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []ctrl.Request {
+				return serviceAccountRoleRequests(mgr.GetClient(), obj, false)
+			}),
+		).
+		Watches(
+			&source.Kind{Type: &v1alpha1.IamInstanceProfile{}},
 			handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []ctrl.Request {
-				status := obj.(*v1alpha1.IamPolicy).Status
-				requests := make([]ctrl.Request, 0, len(status.AttachedRoles))
-				for _, ref := range status.AttachedRoles {
-					requests = append(requests, ctrl.Request{
-						NamespacedName: types.NamespacedName{Name: ref.Name},
-					})
+				profile, ok := obj.(*v1alpha1.IamInstanceProfile)
+				if !ok {
+					return []ctrl.Request{}
 				}
-				return requests
+				return []ctrl.Request{{NamespacedName: types.NamespacedName{Name: profile.Spec.RoleRef.Name}}}
+			}),
+		).
+		Watches(
+			&source.Kind{Type: &v1alpha1.IamPolicy{}},
+			handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []ctrl.Request {
+				return policyRoleRequests(mgr.GetClient(), obj.(v1alpha1.IamPolicyObject), &v1alpha1.IamRoleList{})
 			}),
 		).
 		Complete(r)
 }
+
+// serviceAccountRoleRequests returns a request for the role of every binding
+// that selects the service account. namespaced selects bindings that
+// reference a NamespacedIamRole
+func serviceAccountRoleRequests(c client.Client, serviceAccount client.Object, namespaced bool) []ctrl.Request {
+	bindings := serviceAccountBindings(c, serviceAccount)
+	requests := make([]ctrl.Request, 0, len(bindings))
+	for k := range bindings {
+		if isNamespacedRoleRef(&bindings[k]) == namespaced {
+			requests = append(requests, ctrl.Request{NamespacedName: roleRefKey(&bindings[k])})
+		}
+	}
+	return requests
+}
+
+// permissionsBoundaryPolicyRef indexes roles by the policy used as the
+// permissions boundary
+func permissionsBoundaryPolicyRef(obj client.Object) []string {
+	role, ok := obj.(v1alpha1.IamRoleObject)
+	if !ok {
+		return []string{}
+	}
+	boundary := role.GetSpec().PermissionsBoundary
+	if boundary == nil || boundary.PolicyRef == nil {
+		return []string{}
+	}
+	return []string{boundary.PolicyRef.Name}
+}
+
+// policyRoleRequests returns a request for every role the policy is
+// attached to or used as a permissions boundary by. roles is the list
+// type of the roles in the same scope as the policy
+func policyRoleRequests(c client.Client, policy v1alpha1.IamPolicyObject, roles client.ObjectList) []ctrl.Request {
+	status := policy.GetStatus()
+	requests := make([]ctrl.Request, 0, len(status.AttachedRoles))
+	for _, ref := range status.AttachedRoles {
+		requests = append(requests, ctrl.Request{
+			NamespacedName: types.NamespacedName{Namespace: policy.GetNamespace(), Name: ref.Name},
+		})
+	}
+	// Roles using the policy as a permissions boundary
+	if err := c.List(context.Background(), roles,
+		client.InNamespace(policy.GetNamespace()),
+		client.MatchingFields{permissionsBoundaryPolicyRefIndex: policy.GetName()},
+	); err == nil {
+		items, _ := meta.ExtractList(roles)
+		for _, item := range items {
+			role := item.(client.Object)
+			requests = append(requests, ctrl.Request{
+				NamespacedName: types.NamespacedName{Namespace: role.GetNamespace(), Name: role.GetName()},
+			})
+		}
+	}
+	return requests
+}
@@ -17,16 +17,21 @@ limitations under the License.
 package controllers_test
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
 	"github.com/google/uuid"
 	"github.com/johnhoman/controller-tools/manager"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	cu "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -48,7 +53,7 @@ var _ = Describe("IamRoleController", func() {
 		roleService = iamrole.New(iamService, "controller-test")
 		bm := bindmanager.New(
 			roleService,
-			"arn:aws:iam::111122223333:oidc-provider/oidc.eks.region-code.amazonaws.com/id/EXAMPLED539D4633E53DE1B716D3041E",
+			bindmanager.StaticProvider("arn:aws:iam::111122223333:oidc-provider/oidc.eks.region-code.amazonaws.com/id/EXAMPLED539D4633E53DE1B716D3041E"),
 		)
 
 		c, err := client.New(cfg, client.Options{Scheme: scheme.Scheme})
@@ -91,6 +96,97 @@ var _ = Describe("IamRoleController", func() {
 				return cu.ContainsFinalizer(obj, controllers.Finalizer)
 			}).Should(Succeed())
 		})
+		When("an instance profile references the role", func() {
+			BeforeEach(func() {
+				profile := &v1alpha1.IamInstanceProfile{
+					ObjectMeta: metav1.ObjectMeta{Name: name},
+					Spec:       v1alpha1.IamInstanceProfileSpec{RoleRef: corev1.LocalObjectReference{Name: name}},
+				}
+				mgr.Eventually().Create(profile).Should(Succeed())
+			})
+			It("trusts ec2", func() {
+				Eventually(func() string {
+					role, err := roleService.Get(mgr.GetContext(), &iamrole.GetOptions{Name: name})
+					if err != nil {
+						return ""
+					}
+					return role.TrustPolicy
+				}).Should(ContainSubstring(controllers.ServiceEC2))
+			})
+			It("removes the role from the instance profile when it's deleted", func() {
+				mgr.Eventually().GetWhen(key, instance, func(obj client.Object) bool {
+					return len(obj.(*v1alpha1.IamRole).Status.RoleArn) > 0
+				}).Should(Succeed())
+				_, err := iamService.CreateInstanceProfile(mgr.GetContext(), &iam.CreateInstanceProfileInput{InstanceProfileName: aws.String(name)})
+				Expect(err).ShouldNot(HaveOccurred())
+				_, err = iamService.AddRoleToInstanceProfile(mgr.GetContext(), &iam.AddRoleToInstanceProfileInput{
+					InstanceProfileName: aws.String(name),
+					RoleName:            aws.String(name),
+				})
+				Expect(err).ShouldNot(HaveOccurred())
+
+				Expect(mgr.Uncached().Delete(mgr.GetContext(), instance)).Should(Succeed())
+				Eventually(func() bool {
+					_, err := roleService.Get(mgr.GetContext(), &iamrole.GetOptions{Name: name})
+					return pkgaws.IsNotFound(err)
+				}).Should(BeTrue())
+				out, err := iamService.GetInstanceProfile(mgr.GetContext(), &iam.GetInstanceProfileInput{InstanceProfileName: aws.String(name)})
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(out.InstanceProfile.Roles).Should(BeEmpty())
+			})
+			It("detaches the managed policies when it's deleted", func() {
+				mgr.Eventually().GetWhen(key, instance, func(obj client.Object) bool {
+					return len(obj.(*v1alpha1.IamRole).Status.RoleArn) > 0
+				}).Should(Succeed())
+				out, err := iamService.CreatePolicy(mgr.GetContext(), &iam.CreatePolicyInput{
+					PolicyName:     aws.String(name),
+					PolicyDocument: aws.String(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`),
+				})
+				Expect(err).ShouldNot(HaveOccurred())
+				patch := client.MergeFrom(instance.DeepCopy())
+				instance.Spec.PolicyArns = []string{aws.ToString(out.Policy.Arn)}
+				Expect(mgr.Uncached().Patch(mgr.GetContext(), instance, patch)).Should(Succeed())
+				Eventually(func() int {
+					attached, err := roleService.ListAttachedPolicies(mgr.GetContext(), &iamrole.ListOptions{Name: name})
+					if err != nil {
+						return 0
+					}
+					return attached.Len()
+				}).Should(Equal(1))
+
+				Expect(mgr.Uncached().Delete(mgr.GetContext(), instance)).Should(Succeed())
+				Eventually(func() bool {
+					_, err := roleService.Get(mgr.GetContext(), &iamrole.GetOptions{Name: name})
+					return pkgaws.IsNotFound(err)
+				}).Should(BeTrue())
+			})
+		})
+		When("the role trusts aws principals and services", func() {
+			BeforeEach(func() {
+				patch := client.MergeFrom(instance.DeepCopy())
+				instance.Spec.Trust = &v1alpha1.IamRoleTrust{
+					AWS:        []string{"111122223333"},
+					Services:   []string{"lambda.amazonaws.com"},
+					Conditions: &v1alpha1.TrustConditions{ExternalID: "team-a", RequireMFA: true},
+				}
+				Expect(mgr.Uncached().Patch(mgr.GetContext(), instance, patch)).Should(Succeed())
+			})
+			It("adds the principals to the trust policy", func() {
+				Eventually(func() string {
+					role, err := roleService.Get(mgr.GetContext(), &iamrole.GetOptions{Name: name})
+					if err != nil {
+						return ""
+					}
+					return role.TrustPolicy
+				}).Should(And(
+					ContainSubstring("arn:aws:iam::111122223333:root"),
+					ContainSubstring("lambda.amazonaws.com"),
+					ContainSubstring("sts:ExternalId"),
+					ContainSubstring("aws:MultiFactorAuthPresent"),
+					Not(ContainSubstring(bindmanager.DenyAllAWSSid)),
+				))
+			})
+		})
 		When("a role binding is created", func() {
 			// var serviceAccount *corev1.ServiceAccount
 			var iamRoleBinding *v1alpha1.IamRoleBinding
@@ -100,7 +196,7 @@ var _ = Describe("IamRoleController", func() {
 						Name: name,
 					},
 					Spec: v1alpha1.IamRoleBindingSpec{
-						IamRoleRef:        corev1.LocalObjectReference{Name: name},
+						IamRoleRef:        v1alpha1.IamRoleReference{Name: name},
 						ServiceAccountRef: corev1.LocalObjectReference{Name: name},
 					},
 				}
@@ -110,7 +206,7 @@ var _ = Describe("IamRoleController", func() {
 						Name: name + "2",
 					},
 					Spec: v1alpha1.IamRoleBindingSpec{
-						IamRoleRef:        corev1.LocalObjectReference{Name: name},
+						IamRoleRef:        v1alpha1.IamRoleReference{Name: name},
 						ServiceAccountRef: corev1.LocalObjectReference{Name: name + "2"},
 					},
 				}
@@ -139,6 +235,58 @@ var _ = Describe("IamRoleController", func() {
 				))
 			})
 		})
+		When("a role binding selects service accounts", func() {
+			var namespace string
+			BeforeEach(func() {
+				for _, suffix := range []string{"-a", "-b"} {
+					serviceAccount := &corev1.ServiceAccount{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:   name + suffix,
+							Labels: map[string]string{"role": name},
+						},
+					}
+					mgr.Eventually().Create(serviceAccount).Should(Succeed())
+					namespace = serviceAccount.GetNamespace()
+				}
+				mgr.Eventually().Create(&corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        name + "-c",
+						Labels:      map[string]string{"role": name},
+						Annotations: map[string]string{controllers.ServiceAccountAnnotation: "arn:aws:iam::000000000000:role/other"},
+					},
+				}).Should(Succeed())
+				mgr.Eventually().Create(&v1alpha1.IamRoleBinding{
+					ObjectMeta: metav1.ObjectMeta{Name: name},
+					Spec: v1alpha1.IamRoleBindingSpec{
+						IamRoleRef: v1alpha1.IamRoleReference{Name: name},
+						ServiceAccountSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"role": name},
+						},
+					},
+				}).Should(Succeed())
+			})
+			It("trusts every selected service account", func() {
+				iamRole := &v1alpha1.IamRole{}
+				mgr.Eventually().GetWhen(key, iamRole, func(o client.Object) bool {
+					return len(o.(*v1alpha1.IamRole).Status.BoundServiceAccounts) == 2
+				}).Should(Succeed())
+				Expect(iamRole.Status.BoundServiceAccounts).To(ConsistOf(
+					corev1.ObjectReference{Name: name + "-a", Namespace: namespace},
+					corev1.ObjectReference{Name: name + "-b", Namespace: namespace},
+				))
+				role, err := roleService.Get(mgr.GetContext(), &iamrole.GetOptions{Name: name})
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(role.TrustPolicy).Should(ContainSubstring(fmt.Sprintf("system:serviceaccount:%s:%s-b", namespace, name)))
+			})
+			It("doesn't trust service accounts bound to another role", func() {
+				mgr.Eventually().GetWhen(key, &v1alpha1.IamRole{}, func(o client.Object) bool {
+					return len(o.(*v1alpha1.IamRole).Status.BoundServiceAccounts) == 2
+				}).Should(Succeed())
+				role, err := roleService.Get(mgr.GetContext(), &iamrole.GetOptions{Name: name})
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(role.TrustPolicy).ShouldNot(ContainSubstring(fmt.Sprintf("system:serviceaccount:%s:%s-c", namespace, name)))
+			})
+		})
 		When("it's being deleted", func() {
 			JustBeforeEach(func() {
 				mgr.Expect().Delete(instance.DeepCopy()).Should(Succeed())
@@ -167,6 +315,30 @@ var _ = Describe("IamRoleController", func() {
 							return !cu.ContainsFinalizer(obj, controllers.Finalizer)
 						}).Should(Succeed())
 					})
+					When("the deletion policy is Retain", func() {
+						BeforeEach(func() {
+							patch := client.MergeFrom(instance.DeepCopy())
+							instance.Spec.DeletionPolicy = v1alpha1.DeletionPolicyRetain
+							Expect(mgr.Uncached().Patch(mgr.GetContext(), instance, patch)).Should(Succeed())
+							mgr.Eventually().GetWhen(key, &v1alpha1.IamRole{}, func(obj client.Object) bool {
+								return obj.(*v1alpha1.IamRole).Spec.DeletionPolicy == v1alpha1.DeletionPolicyRetain
+							}).Should(Succeed())
+						})
+						It("should keep the upstream role and remove the ownership tags", func() {
+							mgr.Eventually().GetWhen(key, instance.DeepCopy(), func(obj client.Object) bool {
+								return !cu.ContainsFinalizer(obj, controllers.Finalizer)
+							}).Should(Succeed())
+							Eventually(func() map[string]string {
+								role, err := roleService.Get(mgr.GetContext(), &iamrole.GetOptions{Name: instance.GetName()})
+								if err != nil {
+									return map[string]string{}
+								}
+								return role.Tags
+							}).ShouldNot(HaveKey(controllers.TagKeyOwnerUID))
+							_, err := roleService.Get(mgr.GetContext(), &iamrole.GetOptions{Name: instance.GetName()})
+							Expect(err).ShouldNot(HaveOccurred())
+						})
+					})
 				})
 				When("The upstream resource does not exist", func() {
 					BeforeEach(func() {
@@ -234,6 +406,15 @@ var _ = Describe("IamRoleController", func() {
 						return err
 					}).Should(Succeed())
 				})
+				It("Sets the Ready and Synced conditions", func() {
+					obj := &v1alpha1.IamRole{}
+					mgr.Eventually().GetWhen(key, obj, func(obj client.Object) bool {
+						conditions := obj.(*v1alpha1.IamRole).Status.Conditions
+						return meta.IsStatusConditionTrue(conditions, v1alpha1.ConditionTypeReady) &&
+							meta.IsStatusConditionTrue(conditions, v1alpha1.ConditionTypeSynced)
+					}).Should(Succeed())
+					Expect(obj.Status.ObservedGeneration).Should(Equal(obj.GetGeneration()))
+				})
 				When("The status is out of sync", func() {
 					BeforeEach(func() {
 						Eventually(func() error {
@@ -251,6 +432,35 @@ var _ = Describe("IamRoleController", func() {
 					})
 				})
 			})
+			When("The upstream role is changed", func() {
+				JustBeforeEach(func() {
+					mgr.Eventually().GetWhen(key, &v1alpha1.IamRole{}, func(obj client.Object) bool {
+						return len(obj.(*v1alpha1.IamRole).Status.RoleArn) > 0
+					}).Should(Succeed())
+					_, err := roleService.Update(mgr.GetContext(), &iamrole.UpdateOptions{
+						Name:               instance.GetName(),
+						Description:        aws.String("changed in the console"),
+						MaxDurationSeconds: 7200,
+					})
+					Expect(err).Should(Succeed())
+					// Trigger a reconcile
+					obj := &v1alpha1.IamRole{}
+					mgr.Expect().Get(key, obj).Should(Succeed())
+					patch := client.MergeFrom(obj.DeepCopy())
+					obj.Spec.Description = "Updated description"
+					Expect(mgr.Uncached().Patch(mgr.GetContext(), obj, patch)).Should(Succeed())
+				})
+				It("Should revert the description and max duration", func() {
+					Eventually(func() *iamrole.IamRole {
+						out, err := roleService.Get(mgr.GetContext(), &iamrole.GetOptions{Name: instance.GetName()})
+						Expect(err).Should(Succeed())
+						return out
+					}).Should(And(
+						HaveField("Description", "Updated description"),
+						HaveField("MaxDurationSeconds", int32(v1alpha1.DefaultMaxDurationSeconds)),
+					))
+				})
+			})
 			When("The upstream resource doesn't exist", func() {
 				JustBeforeEach(func() {
 					Expect(roleService.Delete(mgr.GetContext(), &iamrole.DeleteOptions{
@@ -291,7 +501,7 @@ var _ = Describe("IamRoleController Policy Refs", func() {
 		roleService = iamrole.New(iamService, "controller-test")
 		bm := bindmanager.New(
 			roleService,
-			"arn:aws:iam::111122223333:oidc-provider/oidc.eks.region-code.amazonaws.com/id/EXAMPLED539D4633E53DE1B716D3041E",
+			bindmanager.StaticProvider("arn:aws:iam::111122223333:oidc-provider/oidc.eks.region-code.amazonaws.com/id/EXAMPLED539D4633E53DE1B716D3041E"),
 		)
 
 		c, err := client.New(cfg, client.Options{Scheme: scheme.Scheme})
@@ -355,7 +565,7 @@ var _ = Describe("IamRoleController Policy Refs", func() {
 						Name: policyName,
 					},
 					Spec: v1alpha1.IamPolicySpec{
-						Document: v1alpha1.IamPolicyDocument{
+						Document: &v1alpha1.IamPolicyDocument{
 							Statements: []v1alpha1.Statement{{
 								Effect:    v1alpha1.PolicyStatementEffectAllow,
 								Actions:   []string{"s3:ListBucket", "s3:CreateBucket", "s3:DeleteBucket"},
@@ -417,5 +627,423 @@ var _ = Describe("IamRoleController Policy Refs", func() {
 
 			})
 		})
+		When("a managed policy is referenced by arn", func() {
+			arn := "arn:aws:iam::aws:policy/AmazonEC2FullAccess"
+			listAttached := func() []string {
+				attached, err := roleService.ListAttachedPolicies(mgr.GetContext(), &iamrole.ListOptions{
+					Name: instance.GetName(),
+				})
+				if err != nil {
+					return nil
+				}
+				arns := make([]string, 0, len(attached))
+				for _, policy := range attached {
+					arns = append(arns, policy.Arn)
+				}
+				return arns
+			}
+			BeforeEach(func() {
+				patch := client.MergeFrom(instance.DeepCopy())
+				instance.Spec.PolicyRefs = nil
+				instance.Spec.PolicyArns = []string{arn}
+				Expect(mgr.Uncached().Patch(mgr.GetContext(), instance, patch)).Should(Succeed())
+			})
+			It("should attach the policy and not detach it", func() {
+				Eventually(listAttached).Should(ConsistOf(arn))
+				Consistently(listAttached).Should(ConsistOf(arn))
+			})
+		})
+	})
+})
+
+var _ = Describe("IamRoleController Inline Policies", func() {
+	var mgr manager.IntegrationTest
+	var roleService iamrole.Interface
+	BeforeEach(func() {
+		roleService = iamrole.New(newIamService(), "controller-test")
+		bm := bindmanager.New(
+			roleService,
+			bindmanager.StaticProvider("arn:aws:iam::111122223333:oidc-provider/oidc.eks.region-code.amazonaws.com/id/EXAMPLED539D4633E53DE1B716D3041E"),
+		)
+
+		mgr = manager.IntegrationTestBuilder().
+			WithScheme(scheme.Scheme).
+			Complete(cfg)
+
+		raw, err := json.Marshal(defaultPolicy())
+		Expect(err).To(BeNil())
+
+		Expect((&controllers.IamRoleReconciler{
+			Client:        mgr.GetClient(),
+			Scheme:        mgr.GetScheme(),
+			EventRecorder: mgr.GetEventRecorderFor("controller.test"),
+			DefaultPolicy: string(raw),
+			RoleService:   roleService,
+			Manager:       bm,
+		}).SetupWithManager(mgr)).Should(Succeed())
+		mgr.StartManager()
+	})
+	AfterEach(func() { mgr.StopManager() })
+	When("the role declares an inline policy", func() {
+		var key types.NamespacedName
+		var instance *v1alpha1.IamRole
+		listInlinePolicies := func() []string {
+			names, err := roleService.ListInlinePolicies(mgr.GetContext(), &iamrole.ListOptions{Name: key.Name})
+			if err != nil {
+				return nil
+			}
+			return names
+		}
+		BeforeEach(func() {
+			key = types.NamespacedName{Name: "inline-policies-" + uuid.New().String()[:8]}
+			instance = &v1alpha1.IamRole{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name},
+				Spec: v1alpha1.IamRoleSpec{
+					InlinePolicies: []v1alpha1.InlinePolicy{{
+						Name: "s3-list-bucket",
+						Document: v1alpha1.IamPolicyDocument{
+							Statements: []v1alpha1.Statement{{
+								Effect:    v1alpha1.PolicyStatementEffectAllow,
+								Actions:   []string{"s3:ListBucket"},
+								Resources: []string{"*"},
+							}},
+						},
+					}},
+				},
+			}
+			mgr.Eventually().Create(instance).Should(Succeed())
+		})
+		It("should put the inline policy", func() {
+			Eventually(listInlinePolicies).Should(ConsistOf("s3-list-bucket"))
+		})
+		It("should remove inline policies that are no longer declared", func() {
+			Eventually(listInlinePolicies).Should(ConsistOf("s3-list-bucket"))
+			mgr.Eventually().Get(key, instance).Should(Succeed())
+			patch := client.MergeFrom(instance.DeepCopy())
+			instance.Spec.InlinePolicies = nil
+			Expect(mgr.Uncached().Patch(mgr.GetContext(), instance, patch)).Should(Succeed())
+			Eventually(listInlinePolicies).Should(BeEmpty())
+		})
+	})
+})
+
+var _ = Describe("IamRoleController Permissions Boundary", func() {
+	var mgr manager.IntegrationTest
+	var roleService iamrole.Interface
+	var policyService iampolicy.Interface
+	var defaultBoundary *iampolicy.IamPolicy
+	BeforeEach(func() {
+		service := newIamService()
+		roleService = iamrole.New(service, "controller-test")
+		policyService = iampolicy.New(service, "controller-test")
+		bm := bindmanager.New(
+			roleService,
+			bindmanager.StaticProvider("arn:aws:iam::111122223333:oidc-provider/oidc.eks.region-code.amazonaws.com/id/EXAMPLED539D4633E53DE1B716D3041E"),
+		)
+
+		var err error
+		defaultBoundary, err = policyService.Create(context.Background(), &iampolicy.CreateOptions{
+			Name:     "default-boundary-" + uuid.New().String()[:8],
+			Document: `{"Version": "2012-10-17", "Statement": [{"Sid": "DefaultBoundary"}]}`,
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		mgr = manager.IntegrationTestBuilder().
+			WithScheme(scheme.Scheme).
+			Complete(cfg)
+
+		raw, err := json.Marshal(defaultPolicy())
+		Expect(err).To(BeNil())
+
+		Expect((&controllers.IamRoleReconciler{
+			Client:                     mgr.GetClient(),
+			Scheme:                     mgr.GetScheme(),
+			EventRecorder:              mgr.GetEventRecorderFor("controller.test"),
+			DefaultPolicy:              string(raw),
+			RoleService:                roleService,
+			Manager:                    bm,
+			DefaultPermissionsBoundary: defaultBoundary.Arn,
+		}).SetupWithManager(mgr)).Should(Succeed())
+		mgr.StartManager()
+	})
+	AfterEach(func() { mgr.StopManager() })
+	getPermissionsBoundary := func(name string) func() string {
+		return func() string {
+			out, err := roleService.Get(mgr.GetContext(), &iamrole.GetOptions{Name: name})
+			if err != nil {
+				return ""
+			}
+			return out.PermissionsBoundary
+		}
+	}
+	It("should use the default permissions boundary", func() {
+		instance := &v1alpha1.IamRole{ObjectMeta: metav1.ObjectMeta{Name: "boundary-" + uuid.New().String()[:8]}}
+		mgr.Eventually().Create(instance).Should(Succeed())
+		Eventually(getPermissionsBoundary(instance.GetName())).Should(Equal(defaultBoundary.Arn))
+	})
+	It("should use the permissions boundary from the spec", func() {
+		boundary, err := policyService.Create(mgr.GetContext(), &iampolicy.CreateOptions{
+			Name:     "boundary-" + uuid.New().String()[:8],
+			Document: `{"Version": "2012-10-17", "Statement": [{"Sid": "Boundary"}]}`,
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		instance := &v1alpha1.IamRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "boundary-" + uuid.New().String()[:8]},
+			Spec: v1alpha1.IamRoleSpec{
+				PermissionsBoundary: &v1alpha1.PermissionsBoundary{Arn: boundary.Arn},
+			},
+		}
+		mgr.Eventually().Create(instance).Should(Succeed())
+		Eventually(getPermissionsBoundary(instance.GetName())).Should(Equal(boundary.Arn))
+	})
+})
+
+var _ = Describe("IamRoleController Tags", func() {
+	var mgr manager.IntegrationTest
+	var roleService iamrole.Interface
+	BeforeEach(func() {
+		roleService = iamrole.New(newIamService(), "controller-test")
+		bm := bindmanager.New(
+			roleService,
+			bindmanager.StaticProvider("arn:aws:iam::111122223333:oidc-provider/oidc.eks.region-code.amazonaws.com/id/EXAMPLED539D4633E53DE1B716D3041E"),
+		)
+
+		mgr = manager.IntegrationTestBuilder().
+			WithScheme(scheme.Scheme).
+			Complete(cfg)
+
+		raw, err := json.Marshal(defaultPolicy())
+		Expect(err).To(BeNil())
+
+		Expect((&controllers.IamRoleReconciler{
+			Client:        mgr.GetClient(),
+			Scheme:        mgr.GetScheme(),
+			EventRecorder: mgr.GetEventRecorderFor("controller.test"),
+			DefaultPolicy: string(raw),
+			RoleService:   roleService,
+			Manager:       bm,
+			Tags: controllers.Tagger{
+				ClusterName: "controller-test",
+				Version:     "v0.0.0",
+				Enabled:     sets.NewString(controllers.AutomaticTags...),
+			},
+		}).SetupWithManager(mgr)).Should(Succeed())
+		mgr.StartManager()
+	})
+	AfterEach(func() { mgr.StopManager() })
+	It("should sync spec and automatic tags", func() {
+		key := types.NamespacedName{Name: "tags-" + uuid.New().String()[:8]}
+		instance := &v1alpha1.IamRole{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name},
+			Spec: v1alpha1.IamRoleSpec{
+				Tags: map[string]string{"team": "payments"},
+			},
+		}
+		mgr.Eventually().Create(instance).Should(Succeed())
+		getTags := func() map[string]string {
+			out, err := roleService.Get(mgr.GetContext(), &iamrole.GetOptions{Name: key.Name})
+			if err != nil {
+				return nil
+			}
+			return out.Tags
+		}
+		Eventually(getTags).Should(Equal(map[string]string{
+			"team":                         "payments",
+			"aws.jackhoman.com/cluster":    "controller-test",
+			"aws.jackhoman.com/kind":       "IamRole",
+			"aws.jackhoman.com/name":       key.Name,
+			"aws.jackhoman.com/uid":        string(instance.GetUID()),
+			"aws.jackhoman.com/version":    "v0.0.0",
+			controllers.TagKeyOwnerCluster: "",
+			controllers.TagKeyOwnerUID:     string(instance.GetUID()),
+		}))
+
+		mgr.Eventually().GetWhen(key, instance, func(obj client.Object) bool {
+			return len(obj.(*v1alpha1.IamRole).Status.ManagedTags) > 0
+		}).Should(Succeed())
+		Expect(instance.Status.ManagedTags).Should(Equal([]string{"team"}))
+		// Tags added outside the controller are kept
+		Expect(roleService.Tag(mgr.GetContext(), &iamrole.TagOptions{
+			Name: key.Name,
+			Tags: map[string]string{"cost-center": "1234"},
+		})).Should(Succeed())
+
+		patch := client.MergeFrom(instance.DeepCopy())
+		instance.Spec.Tags = nil
+		Expect(mgr.Uncached().Patch(mgr.GetContext(), instance, patch)).Should(Succeed())
+		Eventually(getTags).ShouldNot(HaveKey("team"))
+		Consistently(getTags).Should(HaveKeyWithValue("cost-center", "1234"))
+	})
+})
+
+var _ = Describe("IamRoleController Ownership", func() {
+	var mgr manager.IntegrationTest
+	var iamService pkgaws.IamService
+	var roleService iamrole.Interface
+	BeforeEach(func() {
+		iamService = newIamService()
+		roleService = iamrole.New(iamService, "controller-test")
+		bm := bindmanager.New(
+			roleService,
+			bindmanager.StaticProvider("arn:aws:iam::111122223333:oidc-provider/oidc.eks.region-code.amazonaws.com/id/EXAMPLED539D4633E53DE1B716D3041E"),
+		)
+
+		mgr = manager.IntegrationTestBuilder().
+			WithScheme(scheme.Scheme).
+			Complete(cfg)
+
+		raw, err := json.Marshal(defaultPolicy())
+		Expect(err).To(BeNil())
+
+		Expect((&controllers.IamRoleReconciler{
+			Client:        mgr.GetClient(),
+			Scheme:        mgr.GetScheme(),
+			EventRecorder: mgr.GetEventRecorderFor("controller.test"),
+			DefaultPolicy: string(raw),
+			RoleService:   roleService,
+			Manager:       bm,
+			Tags:          controllers.Tagger{ClusterID: "controller-test"},
+		}).SetupWithManager(mgr)).Should(Succeed())
+		mgr.StartManager()
+	})
+	AfterEach(func() { mgr.StopManager() })
+	When("a role with the same name exists upstream", func() {
+		var key types.NamespacedName
+		var instance *v1alpha1.IamRole
+		BeforeEach(func() {
+			key = types.NamespacedName{Name: "ownership-" + uuid.New().String()[:8]}
+			raw, err := json.Marshal(defaultPolicy())
+			Expect(err).To(BeNil())
+			_, err = roleService.Create(mgr.GetContext(), &iamrole.CreateOptions{
+				Name:           key.Name,
+				PolicyDocument: string(raw),
+				Tags: map[string]string{
+					controllers.TagKeyOwnerCluster: "another-cluster",
+					controllers.TagKeyOwnerUID:     uuid.New().String(),
+				},
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			instance = &v1alpha1.IamRole{ObjectMeta: metav1.ObjectMeta{Name: key.Name}}
+			mgr.Eventually().Create(instance).Should(Succeed())
+		})
+		It("should report a conflict", func() {
+			mgr.Eventually().GetWhen(key, instance, func(obj client.Object) bool {
+				condition := meta.FindStatusCondition(obj.(*v1alpha1.IamRole).Status.Conditions, v1alpha1.ConditionTypeSynced)
+				return condition != nil && condition.Reason == v1alpha1.ReasonConflict
+			}).Should(Succeed())
+			Expect(instance.Status.RoleArn).Should(BeEmpty())
+		})
+		It("should not delete the upstream role", func() {
+			mgr.Eventually().GetWhen(key, instance, func(obj client.Object) bool {
+				return cu.ContainsFinalizer(obj, controllers.Finalizer)
+			}).Should(Succeed())
+			Expect(mgr.Uncached().Delete(mgr.GetContext(), instance)).Should(Succeed())
+			Eventually(func() error {
+				return mgr.Uncached().Get(mgr.GetContext(), key, &v1alpha1.IamRole{})
+			}).ShouldNot(Succeed())
+			_, err := roleService.Get(mgr.GetContext(), &iamrole.GetOptions{Name: key.Name})
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+	})
+	When("an existing role is adopted", func() {
+		var key types.NamespacedName
+		var upstream *iamrole.IamRole
+		BeforeEach(func() {
+			key = types.NamespacedName{Name: "adopt-" + uuid.New().String()[:8]}
+			raw, err := json.Marshal(defaultPolicy())
+			Expect(err).To(BeNil())
+			// Roles made outside the controller can have any name and path
+			upstream, err = iamrole.New(iamService, "hand-made").Create(mgr.GetContext(), &iamrole.CreateOptions{
+				Name:           "Legacy-" + key.Name,
+				Description:    "made by hand",
+				PolicyDocument: string(raw),
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			instance := &v1alpha1.IamRole{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        key.Name,
+					Annotations: map[string]string{v1alpha1.AnnotationAdoptArn: upstream.Arn},
+				},
+				Spec: v1alpha1.IamRoleSpec{Description: "managed by the controller"},
+			}
+			mgr.Eventually().Create(instance).Should(Succeed())
+		})
+		It("should take over the role without recreating it", func() {
+			mgr.Eventually().GetWhen(key, &v1alpha1.IamRole{}, func(obj client.Object) bool {
+				return obj.(*v1alpha1.IamRole).Status.RoleArn == upstream.Arn
+			}).Should(Succeed())
+			Eventually(func() string {
+				out, err := roleService.Get(mgr.GetContext(), &iamrole.GetOptions{Name: upstream.Name})
+				if err != nil {
+					return ""
+				}
+				return out.Description
+			}).Should(Equal("managed by the controller"))
+			out, err := roleService.Get(mgr.GetContext(), &iamrole.GetOptions{Name: upstream.Name})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(out.Id).Should(Equal(upstream.Id))
+			Expect(out.Tags).Should(HaveKey(controllers.TagKeyOwnerUID))
+			_, err = roleService.Get(mgr.GetContext(), &iamrole.GetOptions{Name: key.Name})
+			Expect(pkgaws.IsNotFound(err)).Should(BeTrue())
+		})
+	})
+})
+
+var _ = Describe("IamRoleController Conditions", func() {
+	var mgr manager.IntegrationTest
+	BeforeEach(func() {
+		roleService := iamrole.New(newIamService(), "controller-test")
+		bm := bindmanager.New(
+			roleService,
+			bindmanager.StaticProvider("arn:aws:iam::111122223333:oidc-provider/oidc.eks.region-code.amazonaws.com/id/EXAMPLED539D4633E53DE1B716D3041E"),
+		)
+
+		mgr = manager.IntegrationTestBuilder().
+			WithScheme(scheme.Scheme).
+			Complete(cfg)
+
+		raw, err := json.Marshal(defaultPolicy())
+		Expect(err).To(BeNil())
+
+		Expect((&controllers.IamRoleReconciler{
+			Client:        mgr.GetClient(),
+			Scheme:        mgr.GetScheme(),
+			EventRecorder: mgr.GetEventRecorderFor("controller.test"),
+			DefaultPolicy: string(raw),
+			RoleService:   roleService,
+			Manager:       bm,
+		}).SetupWithManager(mgr)).Should(Succeed())
+		mgr.StartManager()
+	})
+	AfterEach(func() { mgr.StopManager() })
+	It("should report a missing policy", func() {
+		key := types.NamespacedName{Name: "conditions-" + uuid.New().String()[:8]}
+		instance := &v1alpha1.IamRole{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name},
+			Spec: v1alpha1.IamRoleSpec{
+				PolicyRefs: []corev1.ObjectReference{{Name: "does-not-exist"}},
+			},
+		}
+		mgr.Eventually().Create(instance).Should(Succeed())
+		mgr.Eventually().GetWhen(key, instance, func(obj client.Object) bool {
+			condition := meta.FindStatusCondition(obj.(*v1alpha1.IamRole).Status.Conditions, v1alpha1.ConditionTypeSynced)
+			return condition != nil && condition.Reason == v1alpha1.ReasonPolicyNotFound
+		}).Should(Succeed())
+		// The role itself was still created
+		Expect(meta.IsStatusConditionTrue(instance.Status.Conditions, v1alpha1.ConditionTypeReady)).Should(BeTrue())
+	})
+	It("should report a missing policy arn", func() {
+		key := types.NamespacedName{Name: "conditions-" + uuid.New().String()[:8]}
+		instance := &v1alpha1.IamRole{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name},
+			Spec: v1alpha1.IamRoleSpec{
+				PolicyArns: []string{"arn:aws:iam::aws:policy/DoesNotExist"},
+			},
+		}
+		mgr.Eventually().Create(instance).Should(Succeed())
+		mgr.Eventually().GetWhen(key, instance, func(obj client.Object) bool {
+			condition := meta.FindStatusCondition(obj.(*v1alpha1.IamRole).Status.Conditions, v1alpha1.ConditionTypeSynced)
+			return condition != nil && condition.Reason == v1alpha1.ReasonPolicyNotFound
+		}).Should(Succeed())
 	})
 })
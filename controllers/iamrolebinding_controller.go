@@ -2,17 +2,25 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	awsv1alpha1 "github.com/johnhoman/aws-iam-controller/api/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
+	"reflect"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"sort"
 )
 
 type IamRoleBindingReconciler struct {
@@ -31,6 +39,8 @@ const (
 //+kubebuilder:rbac:groups=aws.jackhoman.com,resources=iamrolebindings/finalizers,verbs=update
 //+kubebuilder:rbac:groups=aws.jackhoman.com,resources=iamroles,verbs=get;list;watch;
 //+kubebuilder:rbac:groups=aws.jackhoman.com,resources=iamroles/status,verbs=get;
+//+kubebuilder:rbac:groups=aws.jackhoman.com,resources=namespacediamroles,verbs=get;list;watch;
+//+kubebuilder:rbac:groups=aws.jackhoman.com,resources=namespacediamroles/status,verbs=get;
 //+kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch;patch;update;
 
 func (r *IamRoleBindingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -39,7 +49,7 @@ func (r *IamRoleBindingReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 
 	instance := &awsv1alpha1.IamRoleBinding{}
 	if err := k8s.Get(ctx, req.NamespacedName, instance); err != nil {
-		return ctrl.Result{}, err
+		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 	if !instance.GetDeletionTimestamp().IsZero() {
 		if controllerutil.ContainsFinalizer(instance, ServiceAccountFinalizer) {
@@ -68,65 +78,106 @@ func (r *IamRoleBindingReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 			return ctrl.Result{}, err
 		}
 	}
-	boundServiceAccountName := boundServiceAccountKey(instance).Name
-	refServiceAccountName := referencedServiceAccountKey(instance).Name
-	if isSet(boundServiceAccountName) && boundServiceAccountName != refServiceAccountName {
-		logger.Info("sync service accounts",
-			"have",
-			boundServiceAccountName,
-			"want",
-			refServiceAccountName,
-		)
-		if err := r.finalize(ctx, instance); err != nil {
-			logger.Error(err, "unable to remove binding from service account")
-			return ctrl.Result{}, err
+	reconcileErr := r.reconcile(ctx, instance)
+	if err := updateConditions(ctx, r.Client, instance, &instance.Status.ConditionedStatus,
+		readyCondition(reconcileErr == nil && len(instance.Status.BoundIamRoleArn) > 0, reconcileErr),
+		syncedCondition(reconcileErr),
+	); err != nil {
+		logger.Error(err, "unable to update status conditions")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, reconcileErr
+}
+
+// reconcile binds the selected service accounts to the iam role
+func (r *IamRoleBindingReconciler) reconcile(ctx context.Context, instance *awsv1alpha1.IamRoleBinding) error {
+	logger := log.FromContext(ctx)
+	k8s := client.NewNamespacedClient(r.Client, instance.GetNamespace())
+
+	names, err := bindingServiceAccounts(ctx, r.Client, instance)
+	if err != nil {
+		logger.Error(err, "unable to list service accounts")
+		return err
+	}
+	desired := sets.NewString(names...)
+	for _, name := range boundServiceAccounts(instance).List() {
+		if !desired.Has(name) {
+			logger.Info("sync service accounts", "unbind", name)
+			if err := r.unbindServiceAccount(ctx, instance, name); err != nil {
+				logger.Error(err, "unable to remove binding from service account")
+				return err
+			}
 		}
 	}
 
-	iamRole := &awsv1alpha1.IamRole{}
+	var iamRole awsv1alpha1.IamRoleObject = &awsv1alpha1.IamRole{}
+	kind := awsv1alpha1.KindIamRole
+	if isNamespacedRoleRef(instance) {
+		iamRole = &awsv1alpha1.NamespacedIamRole{}
+		kind = awsv1alpha1.KindNamespacedIamRole
+	}
 	if err := r.Client.Get(ctx, roleRefKey(instance), iamRole); err != nil {
-		logger.Error(err, "unable to get IamRole")
-		return ctrl.Result{}, err
+		logger.Error(err, "unable to get "+kind)
+		if apierrors.IsNotFound(err) {
+			return NewRoleNotFound(fmt.Sprintf("%s %s not found", kind, roleRefKey(instance).Name))
+		}
+		return err
 	}
 
-	if len(iamRole.Status.RoleArn) == 0 {
-		err := NewInvalidRoleStatus("IamRole is missing role-arn from status")
-		return ctrl.Result{}, err
+	if len(iamRole.GetStatus().RoleArn) == 0 {
+		return NewInvalidRoleStatus(kind + " is missing role-arn from status")
 	}
 
-	if err := r.bindServiceAccount(ctx, instance, iamRole); err != nil {
-		logger.Error(err, "unable to bind service account")
-		return ctrl.Result{}, err
+	var bindErr error
+	bound := make([]corev1.LocalObjectReference, 0, len(names))
+	for _, name := range names {
+		if err := r.bindServiceAccount(ctx, instance, name, iamRole); err != nil {
+			logger.Error(err, "unable to bind service account", "serviceAccountName", name)
+			bindErr = err
+			continue
+		}
+		bound = append(bound, corev1.LocalObjectReference{Name: name})
 	}
-	if instance.Status.BoundServiceAccountRef != instance.Spec.ServiceAccountRef || instance.Status.BoundIamRoleArn != iamRole.Status.RoleArn {
+	boundRef := corev1.LocalObjectReference{}
+	if instance.Spec.ServiceAccountSelector == nil && len(bound) > 0 {
+		boundRef = instance.Spec.ServiceAccountRef
+	}
+	if len(bound) == 0 {
+		bound = nil
+	}
+	if instance.Status.BoundServiceAccountRef != boundRef ||
+		instance.Status.BoundIamRoleArn != iamRole.GetStatus().RoleArn ||
+		!reflect.DeepEqual(instance.Status.BoundServiceAccounts, bound) {
 		patch := client.MergeFrom(instance.DeepCopy())
-		instance.Status.BoundServiceAccountRef = instance.Spec.ServiceAccountRef
-		instance.Status.BoundIamRoleArn = iamRole.Status.RoleArn
+		instance.Status.BoundServiceAccountRef = boundRef
+		instance.Status.BoundServiceAccounts = bound
+		instance.Status.BoundIamRoleArn = iamRole.GetStatus().RoleArn
 		if err := k8s.Status().Patch(ctx, instance, patch); err != nil {
 			logger.Error(err, "unable to update status")
-			return ctrl.Result{}, err
+			return err
 		}
 	}
 
-	return ctrl.Result{}, nil
+	return bindErr
 }
 
 func (r *IamRoleBindingReconciler) bindServiceAccount(
 	ctx context.Context,
 	instance *awsv1alpha1.IamRoleBinding,
-	iamRole *awsv1alpha1.IamRole,
+	name string,
+	iamRole awsv1alpha1.IamRoleObject,
 ) error {
 	k8s := client.NewNamespacedClient(r.Client, instance.GetNamespace())
 	keysAndValues := []interface{}{
 		"serviceAccountName",
-		instance.Spec.ServiceAccountRef,
+		name,
 		"iamRoleName",
 		instance.Spec.IamRoleRef,
 	}
 	logger := log.FromContext(ctx).WithValues(keysAndValues...)
 
 	serviceAccount := &corev1.ServiceAccount{}
-	if err := k8s.Get(ctx, types.NamespacedName{Name: instance.Spec.ServiceAccountRef.Name}, serviceAccount); err != nil {
+	if err := k8s.Get(ctx, types.NamespacedName{Name: name}, serviceAccount); err != nil {
 		logger.Error(err, "unable to get service account")
 		return err
 	}
@@ -136,14 +187,14 @@ func (r *IamRoleBindingReconciler) bindServiceAccount(
 		annotations = make(map[string]string)
 	}
 	existing, ok := annotations[ServiceAccountAnnotation]
-	if ok && existing != iamRole.Status.RoleArn {
+	if ok && existing != iamRole.GetStatus().RoleArn {
 		// Something else is bound to this instance
-		r.Event(instance, corev1.EventTypeWarning, "Conflict", "Service account is already bound to an iam role")
-		return NewConflict("unable to bind service account")
+		r.Eventf(instance, corev1.EventTypeWarning, "Conflict", "Service account %s is already bound to an iam role", name)
+		return NewConflict("unable to bind service account " + name)
 	}
 	if !ok {
 		patch := client.MergeFrom(serviceAccount.DeepCopy())
-		annotations[ServiceAccountAnnotation] = iamRole.Status.RoleArn
+		annotations[ServiceAccountAnnotation] = iamRole.GetStatus().RoleArn
 		serviceAccount.SetAnnotations(annotations)
 		if err := k8s.Patch(ctx, serviceAccount, patch); err != nil {
 			logger.Error(err, "unable to add service account annotation")
@@ -154,14 +205,22 @@ func (r *IamRoleBindingReconciler) bindServiceAccount(
 }
 
 func (r *IamRoleBindingReconciler) finalize(ctx context.Context, instance *awsv1alpha1.IamRoleBinding) error {
-	// Remove bound service account annotation
-	k8s := client.NewNamespacedClient(r.Client, instance.GetNamespace())
-	logger := log.FromContext(ctx, "finalize", instance.Status.BoundServiceAccountRef)
-	if instance.Status.BoundServiceAccountRef.Name == "" {
-		return nil
+	// Remove bound service account annotations
+	for _, name := range boundServiceAccounts(instance).List() {
+		if err := r.unbindServiceAccount(ctx, instance, name); err != nil {
+			return err
+		}
 	}
+	return nil
+}
+
+// unbindServiceAccount removes the role arn annotation from the service
+// account if it was added by the binding
+func (r *IamRoleBindingReconciler) unbindServiceAccount(ctx context.Context, instance *awsv1alpha1.IamRoleBinding, name string) error {
+	k8s := client.NewNamespacedClient(r.Client, instance.GetNamespace())
+	logger := log.FromContext(ctx, "finalize", name)
 	serviceAccount := &corev1.ServiceAccount{}
-	if err := k8s.Get(ctx, boundServiceAccountKey(instance), serviceAccount); err != nil {
+	if err := k8s.Get(ctx, types.NamespacedName{Name: name}, serviceAccount); err != nil {
 		logger.Error(err, "unable to get service account")
 		return client.IgnoreNotFound(err)
 	}
@@ -186,29 +245,96 @@ func (r *IamRoleBindingReconciler) finalize(ctx context.Context, instance *awsv1
 func (r *IamRoleBindingReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&awsv1alpha1.IamRoleBinding{}).
+		Watches(
+			&source.Kind{Type: &corev1.ServiceAccount{}},
+			handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []ctrl.Request {
+				bindings := serviceAccountBindings(mgr.GetClient(), obj)
+				requests := make([]ctrl.Request, 0, len(bindings))
+				for _, binding := range bindings {
+					requests = append(requests, ctrl.Request{
+						NamespacedName: types.NamespacedName{Namespace: binding.GetNamespace(), Name: binding.GetName()},
+					})
+				}
+				return requests
+			}),
+		).
 		Complete(r)
 }
 
 var _ reconcile.Reconciler = &IamRoleBindingReconciler{}
 
-func boundServiceAccountKey(instance *awsv1alpha1.IamRoleBinding) types.NamespacedName {
-	key := types.NamespacedName{}
-	key.Name = instance.Status.BoundServiceAccountRef.Name
-	key.Namespace = instance.GetNamespace()
-	return key
+// boundServiceAccounts returns the names of the service accounts currently
+// annotated by the binding
+func boundServiceAccounts(instance *awsv1alpha1.IamRoleBinding) sets.String {
+	names := sets.NewString()
+	if isSet(instance.Status.BoundServiceAccountRef.Name) {
+		names.Insert(instance.Status.BoundServiceAccountRef.Name)
+	}
+	for _, ref := range instance.Status.BoundServiceAccounts {
+		names.Insert(ref.Name)
+	}
+	return names
+}
+
+// bindingServiceAccounts returns the names of the service accounts selected
+// by the binding
+func bindingServiceAccounts(ctx context.Context, c client.Client, instance *awsv1alpha1.IamRoleBinding) ([]string, error) {
+	if instance.Spec.ServiceAccountSelector == nil {
+		if isSet(instance.Spec.ServiceAccountRef.Name) {
+			return []string{instance.Spec.ServiceAccountRef.Name}, nil
+		}
+		return []string{}, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(instance.Spec.ServiceAccountSelector)
+	if err != nil {
+		return nil, err
+	}
+	serviceAccounts := &corev1.ServiceAccountList{}
+	if err := c.List(ctx, serviceAccounts,
+		client.InNamespace(instance.GetNamespace()),
+		client.MatchingLabelsSelector{Selector: selector},
+	); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(serviceAccounts.Items))
+	for _, item := range serviceAccounts.Items {
+		names = append(names, item.GetName())
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// serviceAccountBindings returns the bindings in the service account
+// namespace that reference, select or have bound the service account
+func serviceAccountBindings(c client.Client, serviceAccount client.Object) []awsv1alpha1.IamRoleBinding {
+	bindings := &awsv1alpha1.IamRoleBindingList{}
+	if err := c.List(context.Background(), bindings, client.InNamespace(serviceAccount.GetNamespace())); err != nil {
+		return []awsv1alpha1.IamRoleBinding{}
+	}
+	matched := make([]awsv1alpha1.IamRoleBinding, 0, len(bindings.Items))
+	for _, binding := range bindings.Items {
+		if binding.Spec.ServiceAccountSelector != nil ||
+			binding.Spec.ServiceAccountRef.Name == serviceAccount.GetName() ||
+			boundServiceAccounts(&binding).Has(serviceAccount.GetName()) {
+			matched = append(matched, binding)
+		}
+	}
+	return matched
 }
 
 func roleRefKey(instance *awsv1alpha1.IamRoleBinding) types.NamespacedName {
 	key := types.NamespacedName{}
 	key.Name = instance.Spec.IamRoleRef.Name
+	if isNamespacedRoleRef(instance) {
+		key.Namespace = instance.GetNamespace()
+	}
 	return key
 }
 
-func referencedServiceAccountKey(instance *awsv1alpha1.IamRoleBinding) types.NamespacedName {
-	key := types.NamespacedName{}
-	key.Name = instance.Spec.ServiceAccountRef.Name
-	key.Namespace = instance.GetNamespace()
-	return key
+// isNamespacedRoleRef returns true if the binding references a
+// NamespacedIamRole
+func isNamespacedRoleRef(instance *awsv1alpha1.IamRoleBinding) bool {
+	return instance.Spec.IamRoleRef.Kind == awsv1alpha1.KindNamespacedIamRole
 }
 
 func isSet(s string) bool {
@@ -41,7 +41,7 @@ var _ = Describe("IamRoleBindingController", func() {
 					Name: randomName,
 				},
 				Spec: v1alpha1.IamRoleBindingSpec{
-					IamRoleRef:        corev1.LocalObjectReference{Name: randomName},
+					IamRoleRef:        v1alpha1.IamRoleReference{Name: randomName},
 					ServiceAccountRef: corev1.LocalObjectReference{Name: randomName},
 				},
 			}
@@ -182,4 +182,70 @@ var _ = Describe("IamRoleBindingController", func() {
 			})
 		})
 	})
+	When("the RoleBinding selects service accounts by label", func() {
+		var randomName = "selector-test"
+		var iamRoleArn = "arn:aws:iam::123456789012:role/selector"
+		BeforeEach(func() {
+			iamRole := &v1alpha1.IamRole{ObjectMeta: metav1.ObjectMeta{Name: randomName}}
+			it.Eventually().Create(iamRole).Should(Succeed())
+			iamRole.Status.RoleArn = iamRoleArn
+			Expect(it.Uncached().Status().Update(it.GetContext(), iamRole)).Should(Succeed())
+			for _, name := range []string{"worker-a", "worker-b", "web"} {
+				labels := map[string]string{"app": "worker"}
+				if name == "web" {
+					labels["app"] = "web"
+				}
+				it.Eventually().Create(&corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+				}).Should(Succeed())
+			}
+			it.Eventually().Create(&v1alpha1.IamRoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: randomName},
+				Spec: v1alpha1.IamRoleBindingSpec{
+					IamRoleRef: v1alpha1.IamRoleReference{Name: randomName},
+					ServiceAccountSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"app": "worker"},
+					},
+				},
+			}).Should(Succeed())
+		})
+		It("annotates every matching service account", func() {
+			for _, name := range []string{"worker-a", "worker-b"} {
+				it.Eventually().GetWhen(types.NamespacedName{Name: name}, &corev1.ServiceAccount{}, func(obj client.Object) bool {
+					return obj.GetAnnotations()[controllers.ServiceAccountAnnotation] == iamRoleArn
+				}).Should(Succeed())
+			}
+			instance := &v1alpha1.IamRoleBinding{}
+			it.Eventually().GetWhen(types.NamespacedName{Name: randomName}, instance, func(obj client.Object) bool {
+				return len(obj.(*v1alpha1.IamRoleBinding).Status.BoundServiceAccounts) == 2
+			}).Should(Succeed())
+			Expect(instance.Status.BoundServiceAccounts).Should(ConsistOf(
+				corev1.LocalObjectReference{Name: "worker-a"},
+				corev1.LocalObjectReference{Name: "worker-b"},
+			))
+			serviceAccount := &corev1.ServiceAccount{}
+			it.Expect().Get(types.NamespacedName{Name: "web"}, serviceAccount).Should(Succeed())
+			Expect(serviceAccount.GetAnnotations()).ShouldNot(HaveKey(controllers.ServiceAccountAnnotation))
+		})
+		When("a service account stops matching", func() {
+			BeforeEach(func() {
+				serviceAccount := &corev1.ServiceAccount{}
+				it.Eventually().GetWhen(types.NamespacedName{Name: "worker-b"}, serviceAccount, func(obj client.Object) bool {
+					return metav1.HasAnnotation(obj.(*corev1.ServiceAccount).ObjectMeta, controllers.ServiceAccountAnnotation)
+				}).Should(Succeed())
+				patch := client.MergeFrom(serviceAccount.DeepCopy())
+				serviceAccount.Labels["app"] = "web"
+				Expect(it.Uncached().Patch(it.GetContext(), serviceAccount, patch)).Should(Succeed())
+			})
+			It("removes the annotation", func() {
+				it.Eventually().GetWhen(types.NamespacedName{Name: "worker-b"}, &corev1.ServiceAccount{}, func(obj client.Object) bool {
+					return !metav1.HasAnnotation(obj.(*corev1.ServiceAccount).ObjectMeta, controllers.ServiceAccountAnnotation)
+				}).Should(Succeed())
+				it.Eventually().GetWhen(types.NamespacedName{Name: randomName}, &v1alpha1.IamRoleBinding{}, func(obj client.Object) bool {
+					bound := obj.(*v1alpha1.IamRoleBinding).Status.BoundServiceAccounts
+					return len(bound) == 1 && bound[0].Name == "worker-a"
+				}).Should(Succeed())
+			})
+		})
+	})
 })
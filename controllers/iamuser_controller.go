@@ -0,0 +1,530 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	cu "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/johnhoman/aws-iam-controller/api/v1alpha1"
+	pkgaws "github.com/johnhoman/aws-iam-controller/pkg/aws"
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/iamuser"
+)
+
+const (
+	IamUserFinalizer = "aws.jackhoman.com/delete-iam-user"
+
+	// maxAccessKeys is the number of access keys AWS allows per user
+	maxAccessKeys = 2
+
+	userPolicyRefIndex = "spec.policyRefs"
+)
+
+// IamUserReconciler reconciles a IamUser object
+type IamUserReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	record.EventRecorder
+
+	UserService iamuser.Interface
+	// Tags builds the tags for the upstream user
+	Tags Tagger
+	// DefaultDeletionPolicy is used for users that don't set a deletion
+	// policy. Users are deleted when it's empty
+	DefaultDeletionPolicy v1alpha1.DeletionPolicy
+}
+
+//+kubebuilder:rbac:groups=aws.jackhoman.com,resources=iamusers,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=aws.jackhoman.com,resources=iamusers/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=aws.jackhoman.com,resources=iamusers/finalizers,verbs=update
+//+kubebuilder:rbac:groups=aws.jackhoman.com,resources=iampolicies,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch
+
+// Reconcile creates the upstream iam user, attaches the referenced policies
+// and keeps the user's access key in the referenced secret
+func (r *IamUserReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	instance := &v1alpha1.IamUser{}
+	if err := r.Client.Get(ctx, req.NamespacedName, instance); err != nil {
+		logger.Error(err, "unable to get instance")
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !instance.GetDeletionTimestamp().IsZero() {
+		logger.Info("instance pending deletion")
+		if err := r.Finalize(ctx, instance); err != nil {
+			logger.Error(err, "unable to finalize instance")
+			return ctrl.Result{}, err
+		}
+		if cu.ContainsFinalizer(instance, IamUserFinalizer) {
+			patch := client.MergeFrom(instance.DeepCopy())
+			cu.RemoveFinalizer(instance, IamUserFinalizer)
+			if err := r.Client.Patch(ctx, instance, patch, FieldOwner); err != nil {
+				logger.Error(err, "unable to remove finalizer")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+	if !cu.ContainsFinalizer(instance, IamUserFinalizer) {
+		patch := &unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"finalizers": []string{IamUserFinalizer},
+			},
+		}}
+		patch.SetName(instance.GetName())
+		patch.SetGroupVersionKind(v1alpha1.GroupVersion.WithKind(v1alpha1.KindIamUser))
+		if err := r.Client.Patch(ctx, patch, client.Apply, FieldOwner, client.ForceOwnership); err != nil {
+			logger.Error(err, "unable to add finalizer")
+			return ctrl.Result{}, err
+		}
+	}
+
+	logger = logger.WithValues("UserName", v1alpha1.UpstreamName(instance))
+	logger.Info("reconciling iam user")
+	rotateAfter, reconcileErr := r.reconcile(ctx, instance)
+	if err := updateConditions(ctx, r.Client, instance, &instance.Status.ConditionedStatus,
+		readyCondition(len(instance.Status.Arn) > 0 && reasonForError(reconcileErr) != v1alpha1.ReasonConflict, reconcileErr),
+		syncedCondition(reconcileErr),
+	); err != nil {
+		logger.Error(err, "unable to update status conditions")
+		return ctrl.Result{}, err
+	}
+	if reconcileErr != nil {
+		return ctrl.Result{RequeueAfter: requeueAfter(reconcileErr)}, requeueError(reconcileErr)
+	}
+	logger.Info("Reconcile complete")
+	return ctrl.Result{RequeueAfter: rotateAfter}, nil
+}
+
+// reconcile converges the upstream user with the spec. It returns how long
+// to wait before the next access key rotation or expiry
+func (r *IamUserReconciler) reconcile(ctx context.Context, instance *v1alpha1.IamUser) (time.Duration, error) {
+	logger := log.FromContext(ctx)
+	name := v1alpha1.UpstreamName(instance)
+	tags := r.Tags.Tags(v1alpha1.KindIamUser, instance, instance.Spec.Tags)
+
+	upstream, err := r.UserService.Get(ctx, &iamuser.GetOptions{Name: name})
+	if err != nil {
+		if !pkgaws.IsNotFound(err) {
+			return 0, err
+		}
+		upstream, err = r.UserService.Create(ctx, &iamuser.CreateOptions{Name: name, Tags: tags})
+		if err != nil {
+			logger.Error(err, "unable to create iam user")
+			return 0, err
+		}
+		logger.Info("created upstream iam user", "arn", upstream.Arn)
+		r.Eventf(instance, corev1.EventTypeNormal, "Created", "created iam user %s", upstream.Arn)
+	} else if !r.Tags.Owns(instance, upstream.Tags) {
+		logger.Info("upstream iam user is not owned by this resource")
+		message := fmt.Sprintf("iam user %s exists and is not owned by this resource", upstream.Arn)
+		r.Event(instance, corev1.EventTypeWarning, v1alpha1.ReasonConflict, message)
+		return 0, NewConflict(message)
+	}
+	if err := r.reconcileTags(ctx, instance, upstream, tags); err != nil {
+		logger.Error(err, "unable to update tags")
+		return 0, err
+	}
+	if instance.Status.Arn != upstream.Arn {
+		patch := client.MergeFrom(instance.DeepCopy())
+		instance.Status.Arn = upstream.Arn
+		if err := r.Client.Status().Patch(ctx, instance, patch); err != nil {
+			logger.Error(err, "unable to update status")
+			return 0, err
+		}
+	}
+	// Missing policies don't block the access key, so the error is
+	// returned once the key is reconciled
+	policyErr := r.reconcilePolicies(ctx, instance)
+	var notFound PolicyNotFoundError
+	if policyErr != nil && !errors.As(policyErr, &notFound) {
+		logger.Error(policyErr, "unable to reconcile policies")
+		return 0, policyErr
+	}
+	requeueAfter, err := r.reconcileAccessKeys(ctx, instance)
+	if err != nil {
+		logger.Error(err, "unable to reconcile access keys")
+		return 0, err
+	}
+	return requeueAfter, policyErr
+}
+
+// reconcileTags adds tags missing from the upstream user and removes tags
+// that are no longer wanted
+func (r *IamUserReconciler) reconcileTags(ctx context.Context, instance *v1alpha1.IamUser, upstream *iamuser.IamUser, tags map[string]string) error {
+	add, remove := diffTags(upstream.Tags, tags, instance.Status.ManagedTags)
+	if len(add) > 0 {
+		if err := r.UserService.Tag(ctx, &iamuser.TagOptions{Name: upstream.Name, Tags: add}); err != nil {
+			return err
+		}
+	}
+	if len(remove) > 0 {
+		if err := r.UserService.Untag(ctx, &iamuser.UntagOptions{Name: upstream.Name, Keys: remove}); err != nil {
+			return err
+		}
+	}
+	if len(add) > 0 || len(remove) > 0 {
+		r.Eventf(instance, corev1.EventTypeNormal, "UpdatedTags", "added %d and removed %d tags", len(add), len(remove))
+	}
+	return updateManagedTags(ctx, r.Client, instance, &instance.Status.ManagedTags, instance.Spec.Tags)
+}
+
+// reconcilePolicies attaches the referenced policies, including their
+// shards, and detaches every other policy from the user
+func (r *IamUserReconciler) reconcilePolicies(ctx context.Context, instance *v1alpha1.IamUser) error {
+	name := v1alpha1.UpstreamName(instance)
+	attached, err := r.UserService.ListAttachedPolicies(ctx, &iamuser.ListOptions{Name: name})
+	if err != nil {
+		return err
+	}
+	want, missing, err := policyArns(ctx, r.Client, "", instance.Spec.PolicyRefs, instance.Spec.PolicyArns)
+	if err != nil {
+		return err
+	}
+	notFound, err := syncAttachments(r.EventRecorder, instance, sets.NewString(attached...), want,
+		func(arn string) error {
+			return r.UserService.AttachPolicy(ctx, &iamuser.AttachOptions{Name: name, PolicyArn: arn})
+		},
+		func(arn string) error {
+			return r.UserService.DetachPolicy(ctx, &iamuser.DetachOptions{Name: name, PolicyArn: arn})
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if missing = append(missing, notFound...); len(missing) > 0 {
+		return policyNotFound(missing, instance.Spec.PolicyArns)
+	}
+	return nil
+}
+
+// reconcileAccessKeys keeps a current access key in the secret. A new key is
+// created when the secret doesn't have one of the user's keys or the key is
+// older than the rotation period. The previous key is deleted once the
+// overlap period has passed. It returns how long until the next rotation or
+// expiry
+func (r *IamUserReconciler) reconcileAccessKeys(ctx context.Context, instance *v1alpha1.IamUser) (time.Duration, error) {
+	logger := log.FromContext(ctx)
+	name := v1alpha1.UpstreamName(instance)
+	keys, err := r.UserService.ListAccessKeys(ctx, &iamuser.ListAccessKeysOptions{Name: name})
+	if err != nil {
+		return 0, err
+	}
+	spec := instance.Spec.AccessKey
+	if spec == nil {
+		for _, key := range keys {
+			if err := r.deleteAccessKey(ctx, instance, key.AccessKeyId); err != nil {
+				return 0, err
+			}
+		}
+		return 0, r.updateAccessKeys(ctx, instance, nil)
+	}
+
+	secret := &corev1.Secret{}
+	secretKey := types.NamespacedName{Namespace: spec.SecretRef.Namespace, Name: spec.SecretRef.Name}
+	if err := r.Client.Get(ctx, secretKey, secret); err != nil && !apierrors.IsNotFound(err) {
+		return 0, err
+	}
+	if len(secret.GetUID()) > 0 && !metav1.IsControlledBy(secret, instance) {
+		message := fmt.Sprintf("secret %s exists and is not owned by this resource", secretKey)
+		r.Event(instance, corev1.EventTypeWarning, v1alpha1.ReasonConflict, message)
+		return 0, NewConflict(message)
+	}
+	current := -1
+	for k, key := range keys {
+		if key.AccessKeyId == string(secret.Data[v1alpha1.AccessKeyIdKey]) {
+			current = k
+		}
+	}
+
+	overlap := v1alpha1.DefaultAccessKeyOverlap
+	if spec.OverlapPeriod != nil {
+		overlap = spec.OverlapPeriod.Duration
+	}
+	now := time.Now()
+	rotate := current < 0 || (spec.RotationPeriod != nil && !now.Before(keys[current].CreateDate.Add(spec.RotationPeriod.Duration)))
+	deferred := false
+	if rotate && current >= 0 && len(keys) >= maxAccessKeys && now.Before(keys[current].CreateDate.Add(overlap)) {
+		// The previous key is still in its overlap period, e.g. when the
+		// overlap is longer than the rotation period. Deleting it would break
+		// clients that haven't picked up the current key, so the rotation
+		// waits until it expires
+		for k, key := range keys {
+			if k != current && key.CreateDate.Before(keys[current].CreateDate) {
+				deferred = true
+			}
+		}
+	}
+	if rotate && !deferred {
+		if len(keys) >= maxAccessKeys {
+			// Make room for the new key by deleting the oldest key that
+			// isn't in the secret
+			oldest := len(keys) - 1
+			if oldest == current {
+				oldest--
+			}
+			if err := r.deleteAccessKey(ctx, instance, keys[oldest].AccessKeyId); err != nil {
+				return 0, err
+			}
+			keys = append(keys[:oldest], keys[oldest+1:]...)
+		}
+		created, err := r.UserService.CreateAccessKey(ctx, &iamuser.CreateAccessKeyOptions{Name: name})
+		if err != nil {
+			return 0, err
+		}
+		if err := r.writeAccessKey(ctx, instance, created); err != nil {
+			logger.Error(err, "unable to write access key to secret", "secret", secretKey)
+			return 0, err
+		}
+		logger.Info("created access key", "accessKeyId", created.AccessKeyId)
+		r.Eventf(instance, corev1.EventTypeNormal, "CreatedAccessKey", "created access key %s and wrote it to secret %s", created.AccessKeyId, secretKey)
+		keys = append([]iamuser.AccessKeyMetadata{{AccessKeyId: created.AccessKeyId, CreateDate: created.CreateDate}}, keys...)
+		current = 0
+	}
+
+	var requeueAfter time.Duration
+	// A deferred rotation is retried when the previous key expires
+	if spec.RotationPeriod != nil && !deferred {
+		requeueAfter = keys[current].CreateDate.Add(spec.RotationPeriod.Duration).Sub(now)
+	}
+	// Older keys stay active until the overlap after the current key was
+	// created. Newer keys were never written to the secret
+	expires := keys[current].CreateDate.Add(overlap)
+	active := []iamuser.AccessKeyMetadata{keys[current]}
+	for k, key := range keys {
+		if k == current {
+			continue
+		}
+		if key.CreateDate.Before(keys[current].CreateDate) && now.Before(expires) {
+			active = append(active, key)
+			if requeueAfter == 0 || expires.Sub(now) < requeueAfter {
+				requeueAfter = expires.Sub(now)
+			}
+			continue
+		}
+		if err := r.deleteAccessKey(ctx, instance, key.AccessKeyId); err != nil {
+			return 0, err
+		}
+	}
+	return requeueAfter, r.updateAccessKeys(ctx, instance, active)
+}
+
+// writeAccessKey writes the access key to the secret, creating the secret
+// if it doesn't exist
+func (r *IamUserReconciler) writeAccessKey(ctx context.Context, instance *v1alpha1.IamUser, key *iamuser.AccessKey) error {
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Name:      instance.Spec.AccessKey.SecretRef.Name,
+		Namespace: instance.Spec.AccessKey.SecretRef.Namespace,
+	}}
+	_, err := cu.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		if secret.Data == nil {
+			secret.Data = make(map[string][]byte)
+		}
+		secret.Data[v1alpha1.AccessKeyIdKey] = []byte(key.AccessKeyId)
+		secret.Data[v1alpha1.SecretAccessKeyKey] = []byte(key.SecretAccessKey)
+		return cu.SetControllerReference(instance, secret, r.Scheme)
+	})
+	return err
+}
+
+func (r *IamUserReconciler) deleteAccessKey(ctx context.Context, instance *v1alpha1.IamUser, accessKeyId string) error {
+	if err := r.UserService.DeleteAccessKey(ctx, &iamuser.DeleteAccessKeyOptions{
+		Name:        v1alpha1.UpstreamName(instance),
+		AccessKeyId: accessKeyId,
+	}); err != nil && !pkgaws.IsNotFound(err) {
+		return err
+	}
+	r.Eventf(instance, corev1.EventTypeNormal, "DeletedAccessKey", "deleted access key %s", accessKeyId)
+	return nil
+}
+
+// updateAccessKeys patches the access keys in the status when they changed
+func (r *IamUserReconciler) updateAccessKeys(ctx context.Context, instance *v1alpha1.IamUser, keys []iamuser.AccessKeyMetadata) error {
+	var accessKeys []v1alpha1.AccessKeyStatus
+	for _, key := range keys {
+		accessKeys = append(accessKeys, v1alpha1.AccessKeyStatus{
+			AccessKeyId: key.AccessKeyId,
+			CreateDate:  metav1.NewTime(key.CreateDate.Truncate(time.Second)),
+		})
+	}
+	if equality.Semantic.DeepEqual(instance.Status.AccessKeys, accessKeys) {
+		return nil
+	}
+	patch := client.MergeFrom(instance.DeepCopy())
+	instance.Status.AccessKeys = accessKeys
+	return r.Client.Status().Patch(ctx, instance, patch)
+}
+
+// Finalize deletes the upstream user along with its access keys, policy
+// attachments and group memberships, or releases it when the user is
+// retained
+func (r *IamUserReconciler) Finalize(ctx context.Context, instance *v1alpha1.IamUser) error {
+	logger := log.FromContext(ctx).WithValues("method", "Finalize")
+	name := v1alpha1.UpstreamName(instance)
+
+	upstream, err := r.UserService.Get(ctx, &iamuser.GetOptions{Name: name})
+	if err != nil {
+		if pkgaws.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !r.Tags.Owns(instance, upstream.Tags) {
+		logger.Info("upstream iam user is not owned by this resource, skipping deletion", "arn", upstream.Arn)
+		r.Eventf(instance, corev1.EventTypeWarning, v1alpha1.ReasonConflict, "not deleting iam user %s that is not owned by this resource", upstream.Arn)
+		return nil
+	}
+	if deletionPolicy(instance, instance.Spec.DeletionPolicy, r.DefaultDeletionPolicy) == v1alpha1.DeletionPolicyRetain {
+		return r.retain(ctx, instance, upstream)
+	}
+	// Access keys, policies and group memberships have to be removed
+	// before the user can be deleted
+	keys, err := r.UserService.ListAccessKeys(ctx, &iamuser.ListAccessKeysOptions{Name: name})
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := r.deleteAccessKey(ctx, instance, key.AccessKeyId); err != nil {
+			return err
+		}
+	}
+	attached, err := r.UserService.ListAttachedPolicies(ctx, &iamuser.ListOptions{Name: name})
+	if err != nil {
+		return err
+	}
+	for _, arn := range attached {
+		if err := r.UserService.DetachPolicy(ctx, &iamuser.DetachOptions{Name: name, PolicyArn: arn}); err != nil && !pkgaws.IsNotFound(err) {
+			return err
+		}
+	}
+	groups, err := r.UserService.ListGroups(ctx, &iamuser.ListGroupsOptions{Name: name})
+	if err != nil {
+		return err
+	}
+	for _, group := range groups {
+		if err := r.UserService.RemoveFromGroup(ctx, &iamuser.RemoveFromGroupOptions{Name: name, GroupName: group}); err != nil && !pkgaws.IsNotFound(err) {
+			return err
+		}
+	}
+	if err := r.UserService.Delete(ctx, &iamuser.DeleteOptions{Name: name}); err != nil && !pkgaws.IsNotFound(err) {
+		return err
+	}
+	logger.Info("Removed upstream user", "arn", upstream.Arn)
+	return nil
+}
+
+// retain releases the upstream user without deleting it. The user keeps its
+// access key, so the owner reference is removed from the secret to keep it
+// from being garbage collected with the resource
+func (r *IamUserReconciler) retain(ctx context.Context, instance *v1alpha1.IamUser, upstream *iamuser.IamUser) error {
+	logger := log.FromContext(ctx).WithValues("method", "Retain")
+	if spec := instance.Spec.AccessKey; spec != nil {
+		secret := &corev1.Secret{}
+		key := types.NamespacedName{Namespace: spec.SecretRef.Namespace, Name: spec.SecretRef.Name}
+		if err := r.Client.Get(ctx, key, secret); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return err
+			}
+		} else if metav1.IsControlledBy(secret, instance) {
+			patch := client.MergeFrom(secret.DeepCopy())
+			refs := make([]metav1.OwnerReference, 0, len(secret.OwnerReferences))
+			for _, ref := range secret.OwnerReferences {
+				if ref.UID != instance.GetUID() {
+					refs = append(refs, ref)
+				}
+			}
+			secret.SetOwnerReferences(refs)
+			if err := r.Client.Patch(ctx, secret, patch); err != nil {
+				logger.Error(err, "unable to remove secret owner reference", "secret", key)
+				return err
+			}
+		}
+	}
+	if err := r.UserService.Untag(ctx, &iamuser.UntagOptions{
+		Name: upstream.Name,
+		Keys: ownershipTagKeys,
+	}); err != nil && !pkgaws.IsNotFound(err) {
+		return err
+	}
+	logger.Info("Retained upstream user", "arn", upstream.Arn)
+	r.Eventf(instance, corev1.EventTypeNormal, "Retained", "retained iam user %s", upstream.Arn)
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *IamUserReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.IamUser{}, userPolicyRefIndex, userPolicyRefs); err != nil {
+		return err
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.IamUser{}).
+		Owns(&corev1.Secret{}).
+		Watches(
+			&source.Kind{Type: &v1alpha1.IamPolicy{}},
+			handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []ctrl.Request {
+				return policyUserRequests(mgr.GetClient(), obj)
+			}),
+		).
+		Complete(r)
+}
+
+// userPolicyRefs indexes users by the policies they reference
+func userPolicyRefs(obj client.Object) []string {
+	user, ok := obj.(*v1alpha1.IamUser)
+	if !ok {
+		return []string{}
+	}
+	matches := make([]string, 0, len(user.Spec.PolicyRefs))
+	for _, ref := range user.Spec.PolicyRefs {
+		matches = append(matches, ref.Name)
+	}
+	return matches
+}
+
+// policyUserRequests returns a request for every user that references
+// the policy
+func policyUserRequests(c client.Client, policy client.Object) []ctrl.Request {
+	users := &v1alpha1.IamUserList{}
+	if err := c.List(context.Background(), users, client.MatchingFields{userPolicyRefIndex: policy.GetName()}); err != nil {
+		return []ctrl.Request{}
+	}
+	requests := make([]ctrl.Request, 0, len(users.Items))
+	for _, user := range users.Items {
+		requests = append(requests, ctrl.Request{NamespacedName: types.NamespacedName{Name: user.GetName()}})
+	}
+	return requests
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/google/uuid"
+	"github.com/johnhoman/controller-tools/manager"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	cu "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/johnhoman/aws-iam-controller/api/v1alpha1"
+	"github.com/johnhoman/aws-iam-controller/controllers"
+	pkgaws "github.com/johnhoman/aws-iam-controller/pkg/aws"
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/fake"
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/iamuser"
+)
+
+var _ = Describe("IamUserController", func() {
+	var mgr manager.IntegrationTest
+	var iamService *fake.IamService
+	var userService iamuser.Interface
+	var key types.NamespacedName
+	var secretKey types.NamespacedName
+	var instance *v1alpha1.IamUser
+	BeforeEach(func() {
+		iamService = fake.NewIamService()
+		userService = iamuser.New(iamService, "controller-test")
+		mgr = manager.IntegrationTestBuilder().
+			WithScheme(scheme.Scheme).
+			Complete(cfg)
+
+		Expect((&controllers.IamUserReconciler{
+			Client:        mgr.GetClient(),
+			Scheme:        mgr.GetScheme(),
+			EventRecorder: mgr.GetEventRecorderFor("controller.test"),
+			UserService:   userService,
+			Tags:          controllers.Tagger{ClusterID: "controller-test"},
+		}).SetupWithManager(mgr)).Should(Succeed())
+		mgr.StartManager()
+
+		key = types.NamespacedName{Name: "iam-user-" + uuid.New().String()[:8]}
+		secretKey = types.NamespacedName{Namespace: "default", Name: key.Name + "-credentials"}
+		instance = &v1alpha1.IamUser{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name},
+			Spec: v1alpha1.IamUserSpec{
+				AccessKey: &v1alpha1.AccessKeySpec{
+					SecretRef: v1alpha1.SecretReference{Name: secretKey.Name, Namespace: secretKey.Namespace},
+				},
+			},
+		}
+	})
+	AfterEach(func() { mgr.StopManager() })
+	When("the user is created", func() {
+		BeforeEach(func() {
+			mgr.Eventually().Create(instance).Should(Succeed())
+		})
+		It("should create the upstream user", func() {
+			mgr.Eventually().GetWhen(key, instance, func(obj client.Object) bool {
+				return len(obj.(*v1alpha1.IamUser).Status.Arn) > 0
+			}).Should(Succeed())
+			Expect(cu.ContainsFinalizer(instance, controllers.IamUserFinalizer)).Should(BeTrue())
+			upstream, err := userService.Get(mgr.GetContext(), &iamuser.GetOptions{Name: key.Name})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(upstream.Arn).Should(Equal(instance.Status.Arn))
+		})
+		It("should write the access key to the secret", func() {
+			mgr.Eventually().GetWhen(key, instance, func(obj client.Object) bool {
+				return len(obj.(*v1alpha1.IamUser).Status.AccessKeys) > 0
+			}).Should(Succeed())
+			secret := &corev1.Secret{}
+			Expect(mgr.Uncached().Get(mgr.GetContext(), secretKey, secret)).Should(Succeed())
+			Expect(string(secret.Data[v1alpha1.AccessKeyIdKey])).Should(Equal(instance.Status.AccessKeys[0].AccessKeyId))
+			Expect(secret.Data[v1alpha1.SecretAccessKeyKey]).ShouldNot(BeEmpty())
+			Expect(metav1.IsControlledBy(secret, instance)).Should(BeTrue())
+		})
+		It("should delete the upstream user", func() {
+			mgr.Eventually().GetWhen(key, instance, func(obj client.Object) bool {
+				return len(obj.(*v1alpha1.IamUser).Status.AccessKeys) > 0
+			}).Should(Succeed())
+			Expect(mgr.Uncached().Delete(mgr.GetContext(), instance)).Should(Succeed())
+			Eventually(func() bool {
+				_, err := userService.Get(mgr.GetContext(), &iamuser.GetOptions{Name: key.Name})
+				return pkgaws.IsNotFound(err)
+			}).Should(BeTrue())
+		})
+	})
+	When("the user references a policy", func() {
+		var arn string
+		BeforeEach(func() {
+			out, err := iamService.CreatePolicy(mgr.GetContext(), &iam.CreatePolicyInput{
+				PolicyName:     aws.String(key.Name),
+				PolicyDocument: aws.String(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`),
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			arn = aws.ToString(out.Policy.Arn)
+			policy := &v1alpha1.IamPolicy{ObjectMeta: metav1.ObjectMeta{Name: key.Name}}
+			policy.Spec.Document = &v1alpha1.IamPolicyDocument{
+				Statements: []v1alpha1.Statement{{
+					Effect:    v1alpha1.PolicyStatementEffectAllow,
+					Actions:   []string{"s3:GetObject"},
+					Resources: []string{"*"},
+				}},
+			}
+			mgr.Eventually().Create(policy).Should(Succeed())
+			patch := client.MergeFrom(policy.DeepCopy())
+			policy.Status.Arn = arn
+			Expect(mgr.Uncached().Status().Patch(mgr.GetContext(), policy, patch)).Should(Succeed())
+
+			instance.Spec.PolicyRefs = []corev1.ObjectReference{{Name: policy.GetName()}}
+			mgr.Eventually().Create(instance).Should(Succeed())
+		})
+		It("should attach the policy", func() {
+			Eventually(func() []string {
+				attached, _ := userService.ListAttachedPolicies(mgr.GetContext(), &iamuser.ListOptions{Name: key.Name})
+				return attached
+			}).Should(ConsistOf(arn))
+		})
+	})
+	When("the access key has a rotation period", func() {
+		BeforeEach(func() {
+			instance.Spec.AccessKey.RotationPeriod = &metav1.Duration{Duration: 2 * time.Second}
+			instance.Spec.AccessKey.OverlapPeriod = &metav1.Duration{Duration: time.Second}
+			mgr.Eventually().Create(instance).Should(Succeed())
+		})
+		It("should rotate the access key", func() {
+			mgr.Eventually().GetWhen(key, instance, func(obj client.Object) bool {
+				return len(obj.(*v1alpha1.IamUser).Status.AccessKeys) > 0
+			}).Should(Succeed())
+			first := instance.Status.AccessKeys[0].AccessKeyId
+			Eventually(func() string {
+				secret := &corev1.Secret{}
+				if err := mgr.Uncached().Get(mgr.GetContext(), secretKey, secret); err != nil {
+					return ""
+				}
+				return string(secret.Data[v1alpha1.AccessKeyIdKey])
+			}, 10*time.Second).ShouldNot(Or(BeEmpty(), Equal(first)))
+			Eventually(func() int {
+				keys, _ := userService.ListAccessKeys(mgr.GetContext(), &iamuser.ListAccessKeysOptions{Name: key.Name})
+				for _, key := range keys {
+					if key.AccessKeyId == first {
+						return 1
+					}
+				}
+				return 0
+			}, 10*time.Second).Should(Equal(0))
+		})
+	})
+	When("the overlap period is longer than the rotation period", func() {
+		BeforeEach(func() {
+			instance.Spec.AccessKey.RotationPeriod = &metav1.Duration{Duration: time.Second}
+			instance.Spec.AccessKey.OverlapPeriod = &metav1.Duration{Duration: 4 * time.Second}
+			mgr.Eventually().Create(instance).Should(Succeed())
+		})
+		It("should keep the previous key until it expires", func() {
+			mgr.Eventually().GetWhen(key, instance, func(obj client.Object) bool {
+				return len(obj.(*v1alpha1.IamUser).Status.AccessKeys) == 2
+			}).Should(Succeed())
+			previous := instance.Status.AccessKeys[1].AccessKeyId
+			Consistently(func() bool {
+				keys, _ := userService.ListAccessKeys(mgr.GetContext(), &iamuser.ListAccessKeysOptions{Name: key.Name})
+				for _, key := range keys {
+					if key.AccessKeyId == previous {
+						return true
+					}
+				}
+				return false
+			}, 2*time.Second).Should(BeTrue())
+		})
+	})
+})
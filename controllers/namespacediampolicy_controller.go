@@ -0,0 +1,87 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/johnhoman/aws-iam-controller/api/v1alpha1"
+)
+
+// NamespacedIamPolicyReconciler reconciles a NamespacedIamPolicy object. It
+// uses the same reconcile logic as the IamPolicyReconciler
+type NamespacedIamPolicyReconciler struct {
+	IamPolicyReconciler
+}
+
+//+kubebuilder:rbac:groups=aws.jackhoman.com,resources=namespacediamroles,verbs=get;list;watch
+//+kubebuilder:rbac:groups=aws.jackhoman.com,resources=namespacediampolicies,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=aws.jackhoman.com,resources=namespacediampolicies/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=aws.jackhoman.com,resources=namespacediampolicies/finalizers,verbs=update
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+func (r *NamespacedIamPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	return r.reconcileRequest(ctx, req, &v1alpha1.NamespacedIamPolicy{})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NamespacedIamPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.NamespacedIamRole{}, "spec.policyRefs", policyRefs); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.NamespacedIamPolicy{}, documentConfigMapRefIndex, documentConfigMapRef); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.NamespacedIamPolicy{}, documentSecretRefIndex, documentSecretRef); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.NamespacedIamPolicy{}, policyTemplateIndex, policyTemplateRef); err != nil {
+		return err
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.NamespacedIamPolicy{}).
+		Watches(
+			&source.Kind{Type: &v1alpha1.NamespacedIamRole{}},
+			handler.EnqueueRequestsFromMapFunc(rolePolicyRequests),
+		).
+		Watches(
+			&source.Kind{Type: &corev1.ConfigMap{}},
+			handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []ctrl.Request {
+				return documentSourceRequests(mgr.GetClient(), obj, documentConfigMapRefIndex, &v1alpha1.NamespacedIamPolicyList{})
+			}),
+		).
+		Watches(
+			&source.Kind{Type: &corev1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []ctrl.Request {
+				return documentSourceRequests(mgr.GetClient(), obj, documentSecretRefIndex, &v1alpha1.NamespacedIamPolicyList{})
+			}),
+		).
+		Watches(
+			&source.Kind{Type: &v1alpha1.IamPolicyTemplate{}},
+			handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []ctrl.Request {
+				return indexedPolicyRequests(mgr.GetClient(), &v1alpha1.NamespacedIamPolicyList{}, policyTemplateIndex, obj.GetName())
+			}),
+		).
+		Complete(r)
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/johnhoman/aws-iam-controller/api/v1alpha1"
+)
+
+// NamespacedIamRoleReconciler reconciles a NamespacedIamRole object. It uses
+// the same reconcile logic as the IamRoleReconciler
+type NamespacedIamRoleReconciler struct {
+	IamRoleReconciler
+}
+
+//+kubebuilder:rbac:groups=aws.jackhoman.com,resources=namespacediamroles,verbs=get;list;watch;create;update;patch;delete;
+//+kubebuilder:rbac:groups=aws.jackhoman.com,resources=namespacediamroles/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=aws.jackhoman.com,resources=namespacediamroles/finalizers,verbs=update
+//+kubebuilder:rbac:groups=aws.jackhoman.com,resources=namespacediampolicies,verbs=get;list;watch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+func (r *NamespacedIamRoleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	return r.reconcileRequest(ctx, req, &v1alpha1.NamespacedIamRole{})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NamespacedIamRoleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.notify = &notifier{}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.IamRoleBinding{}, namespacedIamRoleRefIndex, func(obj client.Object) []string {
+		binding, ok := obj.(*v1alpha1.IamRoleBinding)
+		if !ok || !isNamespacedRoleRef(binding) {
+			return []string{}
+		}
+		return []string{binding.Spec.IamRoleRef.Name}
+	}); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.NamespacedIamRole{}, permissionsBoundaryPolicyRefIndex, permissionsBoundaryPolicyRef); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.NamespacedIamRole{}).
+		Watches(
+			&source.Kind{Type: &v1alpha1.IamRoleBinding{}},
+			handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []ctrl.Request {
+				binding, ok := obj.(*v1alpha1.IamRoleBinding)
+				if ok && isNamespacedRoleRef(binding) {
+					return []ctrl.Request{{
+						NamespacedName: types.NamespacedName{
+							Name:      binding.Spec.IamRoleRef.Name,
+							Namespace: binding.GetNamespace(),
+						},
+					}}
+				}
+				return []ctrl.Request{}
+			}),
+		).
+		Watches(
+			&source.Kind{Type: &corev1.ServiceAccount{}},
+			handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []ctrl.Request {
+				return serviceAccountRoleRequests(mgr.GetClient(), obj, true)
+			}),
+		).
+		Watches(
+			&source.Kind{Type: &v1alpha1.NamespacedIamPolicy{}},
+			handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []ctrl.Request {
+				return policyRoleRequests(mgr.GetClient(), obj.(v1alpha1.IamPolicyObject), &v1alpha1.NamespacedIamRoleList{})
+			}),
+		).
+		Complete(r)
+}
@@ -0,0 +1,262 @@
+package controllers_test
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/google/uuid"
+	"github.com/johnhoman/controller-tools/manager"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/johnhoman/aws-iam-controller/api/v1alpha1"
+	"github.com/johnhoman/aws-iam-controller/controllers"
+	pkgaws "github.com/johnhoman/aws-iam-controller/pkg/aws"
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/iampolicy"
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/iamrole"
+	"github.com/johnhoman/aws-iam-controller/pkg/bindmanager"
+)
+
+var _ = Describe("NamespacedIamRoleController", func() {
+	var mgr manager.IntegrationTest
+	var roleService iamrole.Interface
+	var iamService pkgaws.IamService
+	BeforeEach(func() {
+		iamService = newIamService()
+		roleService = iamrole.New(iamService, "controller-test")
+		bm := bindmanager.New(
+			roleService,
+			bindmanager.StaticProvider("arn:aws:iam::111122223333:oidc-provider/oidc.eks.region-code.amazonaws.com/id/EXAMPLED539D4633E53DE1B716D3041E"),
+		)
+
+		mgr = manager.IntegrationTestBuilder().
+			WithScheme(scheme.Scheme).
+			Complete(cfg)
+
+		raw, err := json.Marshal(defaultPolicy())
+		Expect(err).To(BeNil())
+
+		boundary, err := iampolicy.New(iamService, "controller-test").Create(mgr.GetContext(), &iampolicy.CreateOptions{
+			Name:     "default-boundary-" + uuid.New().String()[:8],
+			Document: `{"Version": "2012-10-17", "Statement": [{"Sid": "DefaultBoundary"}]}`,
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect((&controllers.NamespacedIamRoleReconciler{
+			IamRoleReconciler: controllers.IamRoleReconciler{
+				Client:                     mgr.GetClient(),
+				Scheme:                     mgr.GetScheme(),
+				EventRecorder:              mgr.GetEventRecorderFor("controller.test"),
+				DefaultPolicy:              string(raw),
+				RoleService:                roleService,
+				Manager:                    bm,
+				DefaultPermissionsBoundary: boundary.Arn,
+			},
+		}).SetupWithManager(mgr)).Should(Succeed())
+		Expect((&controllers.NamespacedIamPolicyReconciler{
+			IamPolicyReconciler: controllers.IamPolicyReconciler{
+				Client:        mgr.GetClient(),
+				Scheme:        mgr.GetScheme(),
+				EventRecorder: mgr.GetEventRecorderFor("controller.test"),
+				AWS:           iampolicy.New(iamService, "controller-test"),
+			},
+		}).SetupWithManager(mgr)).Should(Succeed())
+		Expect((&controllers.IamRoleBindingReconciler{
+			Client:        mgr.GetClient(),
+			Scheme:        mgr.GetScheme(),
+			EventRecorder: mgr.GetEventRecorderFor("controller.test"),
+		}).SetupWithManager(mgr)).Should(Succeed())
+		mgr.StartManager()
+	})
+	AfterEach(func() { mgr.StopManager() })
+	When("a namespaced role references a namespaced policy", func() {
+		var instance *v1alpha1.NamespacedIamRole
+		var policy *v1alpha1.NamespacedIamPolicy
+		var key types.NamespacedName
+		BeforeEach(func() {
+			key = types.NamespacedName{Name: "namespaced-" + uuid.New().String()[:8]}
+			policy = &v1alpha1.NamespacedIamPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name},
+				Spec: v1alpha1.IamPolicySpec{
+					Document: &v1alpha1.IamPolicyDocument{
+						Statements: []v1alpha1.Statement{{
+							Effect:    v1alpha1.PolicyStatementEffectAllow,
+							Actions:   []string{"s3:GetObject"},
+							Resources: []string{"*"},
+						}},
+					},
+				},
+			}
+			mgr.Eventually().Create(policy).Should(Succeed())
+			instance = &v1alpha1.NamespacedIamRole{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name},
+				Spec: v1alpha1.IamRoleSpec{
+					PolicyRefs: []corev1.ObjectReference{{Name: key.Name}},
+				},
+			}
+			mgr.Eventually().Create(instance).Should(Succeed())
+		})
+		It("should create the upstream role scoped by the namespace", func() {
+			out := &iamrole.IamRole{}
+			Eventually(func() error {
+				var err error
+				out, err = roleService.Get(mgr.GetContext(), &iamrole.GetOptions{Name: v1alpha1.UpstreamName(instance)})
+				return err
+			}).Should(Succeed())
+			Expect(out.Arn).Should(HaveSuffix("/controller-test/" + instance.GetNamespace() + "/" + out.Name))
+			mgr.Eventually().GetWhen(key, &v1alpha1.NamespacedIamRole{}, func(obj client.Object) bool {
+				return obj.(*v1alpha1.NamespacedIamRole).Status.RoleArn == out.Arn
+			}).Should(Succeed())
+		})
+		It("should attach the namespaced policy", func() {
+			Eventually(func() iamrole.AttachedPolicies {
+				attached, err := roleService.ListAttachedPolicies(mgr.GetContext(), &iamrole.ListOptions{
+					Name: v1alpha1.UpstreamName(instance),
+				})
+				if err != nil {
+					return nil
+				}
+				return attached
+			}).Should(ConsistOf(HaveField("Name", v1alpha1.UpstreamName(instance))))
+		})
+		When("a binding references the namespaced role", func() {
+			BeforeEach(func() {
+				mgr.Eventually().Create(&corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{Name: key.Name},
+				}).Should(Succeed())
+				mgr.Eventually().Create(&v1alpha1.IamRoleBinding{
+					ObjectMeta: metav1.ObjectMeta{Name: key.Name},
+					Spec: v1alpha1.IamRoleBindingSpec{
+						IamRoleRef: v1alpha1.IamRoleReference{
+							Kind: v1alpha1.KindNamespacedIamRole,
+							Name: key.Name,
+						},
+						ServiceAccountRef: corev1.LocalObjectReference{Name: key.Name},
+					},
+				}).Should(Succeed())
+			})
+			It("annotates the service account with the role arn", func() {
+				role := &v1alpha1.NamespacedIamRole{}
+				mgr.Eventually().GetWhen(key, role, func(obj client.Object) bool {
+					return len(obj.(*v1alpha1.NamespacedIamRole).Status.RoleArn) > 0
+				}).Should(Succeed())
+				serviceAccount := &corev1.ServiceAccount{}
+				mgr.Eventually().GetWhen(key, serviceAccount, func(obj client.Object) bool {
+					return metav1.HasAnnotation(obj.(*corev1.ServiceAccount).ObjectMeta, controllers.ServiceAccountAnnotation)
+				}).Should(Succeed())
+				Expect(serviceAccount.GetAnnotations()).Should(HaveKeyWithValue(controllers.ServiceAccountAnnotation, role.Status.RoleArn))
+			})
+		})
+	})
+	It("should ignore a permissions boundary and policies outside of the namespace", func() {
+		ctx := mgr.GetContext()
+		out, err := iamService.CreatePolicy(ctx, &iam.CreatePolicyInput{
+			PolicyName:     aws.String("AdministratorAccess-" + uuid.New().String()[:8]),
+			PolicyDocument: aws.String(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"*","Resource":"*"}]}`),
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		instance := &v1alpha1.NamespacedIamRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "namespaced-" + uuid.New().String()[:8]},
+			Spec: v1alpha1.IamRoleSpec{
+				PermissionsBoundary: &v1alpha1.PermissionsBoundary{Arn: aws.ToString(out.Policy.Arn)},
+				PolicyArns:          []string{aws.ToString(out.Policy.Arn)},
+			},
+		}
+		mgr.Eventually().Create(instance).Should(Succeed())
+		mgr.Eventually().GetWhen(types.NamespacedName{Name: instance.Name}, instance, func(obj client.Object) bool {
+			return len(obj.(*v1alpha1.NamespacedIamRole).Status.RoleArn) > 0
+		}).Should(Succeed())
+		role, err := roleService.Get(ctx, &iamrole.GetOptions{Name: v1alpha1.UpstreamName(instance)})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(role.PermissionsBoundary).Should(BeEmpty())
+		attached, err := roleService.ListAttachedPolicies(ctx, &iamrole.ListOptions{Name: role.Name})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(attached).Should(BeEmpty())
+	})
+	It("should not adopt a role outside of the namespace", func() {
+		ctx := mgr.GetContext()
+		out, err := iamService.CreateRole(ctx, &iam.CreateRoleInput{
+			RoleName:                 aws.String("legacy-" + uuid.New().String()[:8]),
+			AssumeRolePolicyDocument: aws.String("{}"),
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		instance := &v1alpha1.NamespacedIamRole{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "namespaced-" + uuid.New().String()[:8],
+				Annotations: map[string]string{v1alpha1.AnnotationAdoptArn: aws.ToString(out.Role.Arn)},
+			},
+		}
+		mgr.Eventually().Create(instance).Should(Succeed())
+		mgr.Eventually().GetWhen(types.NamespacedName{Name: instance.Name}, instance, func(obj client.Object) bool {
+			return len(obj.(*v1alpha1.NamespacedIamRole).Status.RoleArn) > 0
+		}).Should(Succeed())
+		Expect(instance.Status.RoleArn).ShouldNot(Equal(aws.ToString(out.Role.Arn)))
+		Expect(instance.Status.RoleArn).Should(HaveSuffix("/" + v1alpha1.UpstreamName(instance)))
+	})
+	It("should only trust principals in the account of the role", func() {
+		instance := &v1alpha1.NamespacedIamRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "namespaced-" + uuid.New().String()[:8]},
+			Spec: v1alpha1.IamRoleSpec{
+				Trust: &v1alpha1.IamRoleTrust{AWS: []string{"999999999999"}},
+			},
+		}
+		mgr.Eventually().Create(instance).Should(Succeed())
+		mgr.Eventually().GetWhen(types.NamespacedName{Name: instance.Name}, instance, func(obj client.Object) bool {
+			return len(obj.(*v1alpha1.NamespacedIamRole).Status.RoleArn) > 0
+		}).Should(Succeed())
+		Consistently(func() string {
+			role, err := roleService.Get(mgr.GetContext(), &iamrole.GetOptions{Name: v1alpha1.UpstreamName(instance)})
+			if err != nil {
+				return ""
+			}
+			return role.TrustPolicy
+		}).ShouldNot(ContainSubstring("999999999999"))
+	})
+})
+
+var _ = Describe("NamespacedIamRoleController without a default permissions boundary", func() {
+	var mgr manager.IntegrationTest
+	var roleService iamrole.Interface
+	BeforeEach(func() {
+		roleService = iamrole.New(newIamService(), "controller-test")
+		mgr = manager.IntegrationTestBuilder().
+			WithScheme(scheme.Scheme).
+			Complete(cfg)
+
+		raw, err := json.Marshal(defaultPolicy())
+		Expect(err).To(BeNil())
+
+		Expect((&controllers.NamespacedIamRoleReconciler{
+			IamRoleReconciler: controllers.IamRoleReconciler{
+				Client:        mgr.GetClient(),
+				Scheme:        mgr.GetScheme(),
+				EventRecorder: mgr.GetEventRecorderFor("controller.test"),
+				DefaultPolicy: string(raw),
+				RoleService:   roleService,
+			},
+		}).SetupWithManager(mgr)).Should(Succeed())
+		mgr.StartManager()
+	})
+	AfterEach(func() { mgr.StopManager() })
+	It("doesn't create the upstream role", func() {
+		instance := &v1alpha1.NamespacedIamRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "namespaced-" + uuid.New().String()[:8]},
+		}
+		mgr.Eventually().Create(instance).Should(Succeed())
+		key := types.NamespacedName{Name: instance.GetName()}
+		mgr.Eventually().GetWhen(key, &v1alpha1.NamespacedIamRole{}, func(obj client.Object) bool {
+			condition := meta.FindStatusCondition(obj.(*v1alpha1.NamespacedIamRole).Status.Conditions, v1alpha1.ConditionTypeReady)
+			return condition != nil &&
+				condition.Status == metav1.ConditionFalse &&
+				condition.Reason == v1alpha1.ReasonPermissionsBoundaryRequired
+		}).Should(Succeed())
+		_, err := roleService.Get(mgr.GetContext(), &iamrole.GetOptions{Name: v1alpha1.UpstreamName(instance)})
+		Expect(pkgaws.IsNotFound(err)).To(BeTrue())
+	})
+})
@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	TagKeyPrefix = "aws.jackhoman.com/"
+
+	TagCluster = "cluster"
+	TagKind    = "kind"
+	TagName    = "name"
+	TagUID     = "uid"
+	TagVersion = "version"
+
+	// TagKeyOwnerCluster and TagKeyOwnerUID mark the resources created by
+	// the controller. They're always added and can't be disabled
+	TagKeyOwnerCluster = TagKeyPrefix + "owner-cluster"
+	TagKeyOwnerUID     = TagKeyPrefix + "owner-uid"
+)
+
+// AutomaticTags are the names of all the tags the controller can add to
+// the resources it manages
+var AutomaticTags = []string{TagCluster, TagKind, TagName, TagUID, TagVersion}
+
+// Tagger builds the tags for upstream iam resources from the tags in the
+// resource spec and the automatic tags enabled on the controller
+type Tagger struct {
+	// ClusterID identifies the cluster in the ownership tags
+	ClusterID   string
+	ClusterName string
+	Version     string
+	// Enabled is the set of automatic tags to add. No automatic tags
+	// are added when it's empty
+	Enabled sets.String
+}
+
+// Tags returns the tags the upstream resource should have. Automatic and
+// ownership tags take precedence over tags from the spec
+func (t Tagger) Tags(kind string, obj client.Object, tags map[string]string) map[string]string {
+	rv := make(map[string]string, len(tags)+t.Enabled.Len()+2)
+	for key, value := range tags {
+		rv[key] = value
+	}
+	automatic := map[string]string{
+		TagCluster: t.ClusterName,
+		TagKind:    kind,
+		TagName:    obj.GetName(),
+		TagUID:     string(obj.GetUID()),
+		TagVersion: t.Version,
+	}
+	for name, value := range automatic {
+		if t.Enabled.Has(name) && len(value) > 0 {
+			rv[TagKeyPrefix+name] = value
+		}
+	}
+	rv[TagKeyOwnerCluster] = t.ClusterID
+	rv[TagKeyOwnerUID] = string(obj.GetUID())
+	return rv
+}
+
+// Owns returns true if the upstream tags have the ownership marker
+// for the object
+func (t Tagger) Owns(obj client.Object, tags map[string]string) bool {
+	uid, ok := tags[TagKeyOwnerUID]
+	if !ok || uid != string(obj.GetUID()) {
+		return false
+	}
+	return tags[TagKeyOwnerCluster] == t.ClusterID
+}
+
+// diffTags returns the tags that need to be added or updated on the
+// upstream resource and the tag keys that need to be removed. Only tags
+// added by the controller are removed, which are the tags with the
+// controller prefix and the managed keys from the status. Tags that aren't
+// managed, e.g. tags added by other tooling, are never removed
+func diffTags(have, want map[string]string, managed []string) (map[string]string, []string) {
+	add := make(map[string]string)
+	for key, value := range want {
+		if current, ok := have[key]; !ok || current != value {
+			add[key] = value
+		}
+	}
+	owned := sets.NewString(managed...)
+	remove := make([]string, 0)
+	for key := range have {
+		if _, ok := want[key]; ok {
+			continue
+		}
+		if strings.HasPrefix(key, TagKeyPrefix) || owned.Has(key) {
+			remove = append(remove, key)
+		}
+	}
+	sort.Strings(remove)
+	return add, remove
+}
+
+// managedTagKeys returns the keys of the spec tags
+func managedTagKeys(tags map[string]string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+	return sets.StringKeySet(tags).List()
+}
+
+// updateManagedTags records the keys of the spec tags in the status once
+// they've been added to the upstream resource, so they're removed when
+// they're dropped from the spec
+func updateManagedTags(ctx context.Context, c client.Client, obj client.Object, managed *[]string, tags map[string]string) error {
+	keys := managedTagKeys(tags)
+	if reflect.DeepEqual(keys, *managed) || (len(keys) == 0 && len(*managed) == 0) {
+		return nil
+	}
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	*managed = keys
+	return c.Status().Patch(ctx, obj, patch)
+}
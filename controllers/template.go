@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/johnhoman/aws-iam-controller/api/v1alpha1"
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/iampolicy"
+)
+
+const policyTemplateIndex = "spec.template.name"
+
+// TemplateVariables are the built in variables available to every
+// IamPolicyTemplate
+type TemplateVariables struct {
+	AccountID   string
+	Partition   string
+	Region      string
+	ClusterName string
+}
+
+// variables returns the built in variables by name. Variables that aren't
+// known are left out so referencing them is an error
+func (v TemplateVariables) variables(policy client.Object) map[string]string {
+	rv := map[string]string{}
+	for name, value := range map[string]string{
+		"aws.accountId":    v.AccountID,
+		"aws.partition":    v.Partition,
+		"aws.region":       v.Region,
+		"cluster.name":     v.ClusterName,
+		"policy.namespace": policy.GetNamespace(),
+	} {
+		if len(value) > 0 {
+			rv[name] = value
+		}
+	}
+	return rv
+}
+
+// policyTemplateRef indexes policies by the template they instantiate
+func policyTemplateRef(obj client.Object) []string {
+	policy, ok := obj.(v1alpha1.IamPolicyObject)
+	if !ok || policy.GetSpec().Template == nil {
+		return []string{}
+	}
+	return []string{policy.GetSpec().Template.Name}
+}
+
+// renderTemplate returns the template document with the parameters and
+// built in variables replaced by their values
+func renderTemplate(template *v1alpha1.IamPolicyTemplate, policy v1alpha1.IamPolicyObject, variables TemplateVariables) (*v1alpha1.IamPolicyDocument, error) {
+	values := policy.GetSpec().Template.Values
+	parameters := make(map[string]struct{}, len(template.Spec.Parameters))
+	lookup := variables.variables(policy)
+	for k := range template.Spec.Parameters {
+		parameter := &template.Spec.Parameters[k]
+		parameters[parameter.Name] = struct{}{}
+		value, ok := values[parameter.Name]
+		if !ok {
+			if parameter.Default == nil {
+				return nil, fmt.Errorf("parameter %s is required", parameter.Name)
+			}
+			value = *parameter.Default
+		}
+		if err := v1alpha1.ValidateParameterValue(parameter, value); err != nil {
+			return nil, err
+		}
+		lookup[parameter.Name] = value
+	}
+	for name := range values {
+		if _, ok := parameters[name]; !ok {
+			return nil, fmt.Errorf("unknown parameter %s", name)
+		}
+	}
+
+	// Expand every string in the document, including condition keys and
+	// values
+	raw, err := json.Marshal(&template.Spec.Document)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var tree interface{}
+	if err := decoder.Decode(&tree); err != nil {
+		return nil, err
+	}
+	tree, err = expandTree(tree, func(name string) (string, bool) {
+		value, ok := lookup[name]
+		return value, ok
+	})
+	if err != nil {
+		return nil, err
+	}
+	raw, err = json.Marshal(tree)
+	if err != nil {
+		return nil, err
+	}
+	document := &v1alpha1.IamPolicyDocument{}
+	if err := json.Unmarshal(raw, document); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+// expandTree expands the variables in every string of a decoded JSON value
+func expandTree(value interface{}, lookup func(string) (string, bool)) (interface{}, error) {
+	switch value := value.(type) {
+	case string:
+		return iampolicy.ExpandVariables(value, lookup)
+	case []interface{}:
+		for k := range value {
+			item, err := expandTree(value[k], lookup)
+			if err != nil {
+				return nil, err
+			}
+			value[k] = item
+		}
+	case map[string]interface{}:
+		for key := range value {
+			item, err := expandTree(value[key], lookup)
+			if err != nil {
+				return nil, err
+			}
+			value[key] = item
+		}
+	}
+	return value, nil
+}
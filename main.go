@@ -20,15 +20,25 @@ import (
 	"context"
 	"flag"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"go.uber.org/zap/zapcore"
 	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/sets"
 
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/iamgroup"
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/iaminstanceprofile"
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/iamoidcprovider"
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/iampolicy"
 	"github.com/johnhoman/aws-iam-controller/pkg/aws/iamrole"
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/iamuser"
 	"github.com/johnhoman/aws-iam-controller/pkg/bindmanager"
+	"github.com/johnhoman/aws-iam-controller/pkg/discovery"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -36,6 +46,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
@@ -50,6 +61,9 @@ const (
 	DefaultWebhookPort = 9443
 )
 
+// version is set at build time with -ldflags "-X main.version=<version>"
+var version = "dev"
+
 var (
 	scheme     = runtime.NewScheme()
 	setupLog   = ctrl.Log.WithName("setup")
@@ -57,7 +71,7 @@ var (
 		"Version": "2012-10-17",
 		"Statement": []interface{}{
 			map[string]interface{}{
-				"Sid":       "DenyAllAWS",
+				"Sid":       bindmanager.DenyAllAWSSid,
 				"Effect":    "Deny",
 				"Principal": map[string]interface{}{"AWS": "*"},
 				"Action":    "sts:AssumeRole",
@@ -83,8 +97,17 @@ func main() {
 		webhookPort          int
 		path                 string
 		oidcArn              string
+		oidcProviderName     string
+		accountID            string
+		permissionsBoundary  string
+		clusterName          string
+		clusterID            string
+		automaticTags        string
+		deletionPolicy       string
+		versionLimit         int
 		awsRegion            string
 		awsProfile           string
+		awsPartition         string
 		enableWebhook        bool
 	)
 
@@ -92,9 +115,27 @@ func main() {
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.IntVar(&webhookPort, "webhook-port", DefaultWebhookPort, "The port to expose the webhook server on")
 	flag.StringVar(&path, "resource-default-path", "", "The path prefix to use for creating IAM resources")
-	flag.StringVar(&oidcArn, "oidc-arn", "", "The EKS cluster oidc provider")
+	flag.StringVar(&oidcArn, "oidc-arn", "",
+		"The EKS cluster oidc provider. Defaults to the provider for the service account issuer of the api server")
+	flag.StringVar(&oidcProviderName, "oidc-provider", "",
+		"The name of the IamOIDCProvider that is the EKS cluster oidc provider. Used when -oidc-arn isn't set")
+	flag.StringVar(&accountID, "account-id", "",
+		"The aws account id the controller manages. Defaults to the account of -oidc-arn or of the aws credentials")
+	flag.StringVar(&permissionsBoundary, "default-permissions-boundary", "",
+		"The policy arn to use as the permissions boundary for roles that don't specify one. Required for NamespacedIamRoles")
+	flag.StringVar(&clusterName, "cluster-name", "", "The cluster name to tag iam resources with")
+	flag.StringVar(&clusterID, "cluster-id", "",
+		"Unique id of the cluster used to mark the iam resources it owns. Defaults to --cluster-name")
+	flag.StringVar(&automaticTags, "automatic-tags", strings.Join(controllers.AutomaticTags, ","),
+		"Comma separated list of tags to add to iam resources. Supported tags are "+strings.Join(controllers.AutomaticTags, ", "))
+	flag.StringVar(&deletionPolicy, "default-deletion-policy", string(awsv1alpha1.DeletionPolicyDelete),
+		"The deletion policy for iam resources that don't set one. One of Delete or Retain")
+	flag.IntVar(&versionLimit, "default-policy-version-limit", iampolicy.MaxVersions,
+		"The number of versions to keep for iam policies that don't set a version limit. Between 1 and 5")
 	flag.StringVar(&awsRegion, "aws-region", "", "aws region")
 	flag.StringVar(&awsProfile, "aws-profile", "", "aws shared credentials profile")
+	flag.StringVar(&awsPartition, "aws-partition", "",
+		"The aws partition the controller manages, e.g. aws-cn. Defaults to the partition of -oidc-arn or of the aws credentials")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
@@ -107,6 +148,9 @@ func main() {
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	// The webhooks and controllers check that namespaced resources stay in
+	// their namespace path
+	awsv1alpha1.ResourcePath = path
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
@@ -137,13 +181,106 @@ func main() {
 		Exit(1)
 	}
 
-	if len(oidcArn) == 0 {
-		setupLog.Info("missing required argument -oidc-arn")
-		Exit(1)
+	// Discovery calls the api server and sts, so it gets more time than
+	// loading the aws config
+	discoveryCtx, discoveryCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer discoveryCancel()
+
+	issuer, err := discovery.Issuer(discoveryCtx, kubernetes.NewForConfigOrDie(mgr.GetConfig()).Discovery().RESTClient())
+	if err != nil {
+		setupLog.Error(err, "unable to discover the service account issuer")
+	}
+
+	var oidcProvider bindmanager.Provider
+	partition := awsPartition
+	switch {
+	case len(oidcArn) > 0:
+		// The oidc provider is in the account the controller manages
+		parsed, err := arn.Parse(oidcArn)
+		if err != nil {
+			setupLog.Error(err, "invalid argument -oidc-arn")
+			Exit(1)
+		}
+		if len(accountID) == 0 {
+			accountID = parsed.AccountID
+		}
+		if len(partition) == 0 {
+			partition = parsed.Partition
+		}
+		oidcProvider = bindmanager.StaticProvider(oidcArn)
+	case len(oidcProviderName) > 0:
+		oidcProvider = &bindmanager.ResourceProvider{Client: mgr.GetClient(), Name: oidcProviderName}
+	}
+	if len(accountID) == 0 || len(partition) == 0 {
+		id, p, err := discovery.Account(discoveryCtx, sts.NewFromConfig(cfg))
+		if err != nil {
+			setupLog.Error(err, "unable to look up the aws account, set -account-id and -aws-partition")
+			Exit(1)
+		}
+		if len(accountID) == 0 {
+			accountID = id
+		}
+		if len(partition) == 0 {
+			partition = p
+		}
+	}
+	if oidcProvider == nil {
+		if len(issuer) == 0 {
+			setupLog.Info("unable to discover the oidc provider, set -oidc-arn or -oidc-provider")
+			Exit(1)
+		}
+		oidcArn = iamoidcprovider.ProviderArn(partition, accountID, issuer)
+		setupLog.Info("discovered oidc provider", "arn", oidcArn)
+		oidcProvider = bindmanager.StaticProvider(oidcArn)
 	}
 
 	client := iam.NewFromConfig(cfg)
 	service := iamrole.New(client, path)
+	providerService := iamoidcprovider.New(client)
+
+	providerChecker := &discovery.ProviderChecker{
+		Provider:        oidcProvider,
+		ProviderService: providerService,
+		Issuer:          issuer,
+	}
+	// An IamOIDCProvider is read from the cache, which isn't started until
+	// the manager is, so it's only checked by the readiness probe
+	if len(oidcProviderName) == 0 {
+		if err := providerChecker.Check(discoveryCtx); err != nil {
+			setupLog.Error(err, "invalid oidc provider")
+		}
+	}
+
+	if len(clusterID) == 0 {
+		clusterID = clusterName
+	}
+	tagger := controllers.Tagger{
+		ClusterID:   clusterID,
+		ClusterName: clusterName,
+		Version:     version,
+		Enabled:     sets.NewString(),
+	}
+	for _, tag := range strings.Split(automaticTags, ",") {
+		tag = strings.TrimSpace(tag)
+		if len(tag) == 0 {
+			continue
+		}
+		if !sets.NewString(controllers.AutomaticTags...).Has(tag) {
+			setupLog.Info("unsupported automatic tag", "tag", tag)
+			Exit(1)
+		}
+		tagger.Enabled.Insert(tag)
+	}
+
+	defaultDeletionPolicy := awsv1alpha1.DeletionPolicy(deletionPolicy)
+	if defaultDeletionPolicy != awsv1alpha1.DeletionPolicyDelete && defaultDeletionPolicy != awsv1alpha1.DeletionPolicyRetain {
+		setupLog.Info("unsupported deletion policy", "deletionPolicy", deletionPolicy)
+		Exit(1)
+	}
+	if versionLimit < 1 || versionLimit > iampolicy.MaxVersions {
+		setupLog.Info("unsupported policy version limit", "versionLimit", versionLimit)
+		Exit(1)
+	}
 
 	raw, err := json.Marshal(denyPolicy)
 	if err != nil {
@@ -151,17 +288,30 @@ func main() {
 		Exit(1)
 	}
 
-	if err = (&controllers.IamRoleReconciler{
-		Client:        mgr.GetClient(),
-		Scheme:        mgr.GetScheme(),
-		EventRecorder: mgr.GetEventRecorderFor("controller.iamrole"),
-		RoleService:   service,
-		DefaultPolicy: string(raw),
-		Manager:       bindmanager.New(service, oidcArn),
-	}).SetupWithManager(mgr); err != nil {
+	roleReconciler := controllers.IamRoleReconciler{
+		Client:                     mgr.GetClient(),
+		Scheme:                     mgr.GetScheme(),
+		EventRecorder:              mgr.GetEventRecorderFor("controller.iamrole"),
+		RoleService:                service,
+		DefaultPolicy:              string(raw),
+		Manager:                    bindmanager.New(service, oidcProvider),
+		DefaultPermissionsBoundary: permissionsBoundary,
+		Tags:                       tagger,
+		DefaultDeletionPolicy:      defaultDeletionPolicy,
+	}
+	if err = (&roleReconciler).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "IamRole")
 		Exit(1)
 	}
+	if len(permissionsBoundary) == 0 {
+		setupLog.Info("namespaced roles won't be reconciled without -default-permissions-boundary")
+	}
+	namespacedRoleReconciler := &controllers.NamespacedIamRoleReconciler{IamRoleReconciler: roleReconciler}
+	namespacedRoleReconciler.EventRecorder = mgr.GetEventRecorderFor("controller.namespacediamrole")
+	if err = namespacedRoleReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NamespacedIamRole")
+		Exit(1)
+	}
 
 	if enableWebhook {
 		if err = (&awsv1alpha1.IamRole{}).SetupWebhookWithManager(mgr); err != nil {
@@ -172,14 +322,115 @@ func main() {
 			setupLog.Error(err, "unable to create webhook", "webhook", "IamRoleBinding")
 			Exit(1)
 		}
+		if err = (&awsv1alpha1.NamespacedIamRole{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "NamespacedIamRole")
+			Exit(1)
+		}
+		if err = (&awsv1alpha1.IamPolicy{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "IamPolicy")
+			Exit(1)
+		}
+		if err = (&awsv1alpha1.NamespacedIamPolicy{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "NamespacedIamPolicy")
+			Exit(1)
+		}
+		if err = (&awsv1alpha1.IamPolicyTemplate{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "IamPolicyTemplate")
+			Exit(1)
+		}
+		if err = (&awsv1alpha1.IamUser{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "IamUser")
+			Exit(1)
+		}
+		if err = (&awsv1alpha1.IamGroup{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "IamGroup")
+			Exit(1)
+		}
+		if err = (&awsv1alpha1.IamInstanceProfile{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "IamInstanceProfile")
+			Exit(1)
+		}
+		if err = (&awsv1alpha1.IamOIDCProvider{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "IamOIDCProvider")
+			Exit(1)
+		}
 	}
-	if err = (&controllers.IamPolicyReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
+	policyReconciler := controllers.IamPolicyReconciler{
+		Client:                mgr.GetClient(),
+		Scheme:                mgr.GetScheme(),
+		EventRecorder:         mgr.GetEventRecorderFor("controller.iampolicy"),
+		AWS:                   iampolicy.New(client, path),
+		Tags:                  tagger,
+		DefaultDeletionPolicy: defaultDeletionPolicy,
+		DefaultVersionLimit:   versionLimit,
+		TemplateVariables: controllers.TemplateVariables{
+			AccountID:   accountID,
+			Partition:   partition,
+			Region:      cfg.Region,
+			ClusterName: clusterName,
+		},
+	}
+	if err = (&policyReconciler).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "IamPolicy")
 		Exit(1)
 	}
+	namespacedPolicyReconciler := &controllers.NamespacedIamPolicyReconciler{IamPolicyReconciler: policyReconciler}
+	namespacedPolicyReconciler.EventRecorder = mgr.GetEventRecorderFor("controller.namespacediampolicy")
+	if err = namespacedPolicyReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NamespacedIamPolicy")
+		Exit(1)
+	}
+	if err = (&controllers.IamRoleBindingReconciler{
+		Client:        mgr.GetClient(),
+		Scheme:        mgr.GetScheme(),
+		EventRecorder: mgr.GetEventRecorderFor("controller.iamrolebinding"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "IamRoleBinding")
+		Exit(1)
+	}
+	if err = (&controllers.IamUserReconciler{
+		Client:                mgr.GetClient(),
+		Scheme:                mgr.GetScheme(),
+		EventRecorder:         mgr.GetEventRecorderFor("controller.iamuser"),
+		UserService:           iamuser.New(client, path),
+		Tags:                  tagger,
+		DefaultDeletionPolicy: defaultDeletionPolicy,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "IamUser")
+		Exit(1)
+	}
+	if err = (&controllers.IamGroupReconciler{
+		Client:                mgr.GetClient(),
+		Scheme:                mgr.GetScheme(),
+		EventRecorder:         mgr.GetEventRecorderFor("controller.iamgroup"),
+		GroupService:          iamgroup.New(client, path),
+		DefaultDeletionPolicy: defaultDeletionPolicy,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "IamGroup")
+		Exit(1)
+	}
+	if err = (&controllers.IamInstanceProfileReconciler{
+		Client:                 mgr.GetClient(),
+		Scheme:                 mgr.GetScheme(),
+		EventRecorder:          mgr.GetEventRecorderFor("controller.iaminstanceprofile"),
+		InstanceProfileService: iaminstanceprofile.New(client, path),
+		Tags:                   tagger,
+		DefaultDeletionPolicy:  defaultDeletionPolicy,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "IamInstanceProfile")
+		Exit(1)
+	}
+	if err = (&controllers.IamOIDCProviderReconciler{
+		Client:                mgr.GetClient(),
+		Scheme:                mgr.GetScheme(),
+		EventRecorder:         mgr.GetEventRecorderFor("controller.iamoidcprovider"),
+		ProviderService:       providerService,
+		Tags:                  tagger,
+		DefaultDeletionPolicy: defaultDeletionPolicy,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "IamOIDCProvider")
+		Exit(1)
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -190,6 +441,10 @@ func main() {
 		setupLog.Error(err, "unable to set up ready check")
 		Exit(1)
 	}
+	if err := mgr.AddReadyzCheck("oidc-provider", providerChecker.ReadyzCheck); err != nil {
+		setupLog.Error(err, "unable to set up oidc provider check")
+		Exit(1)
+	}
 
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
@@ -3,9 +3,18 @@ package aws
 import (
 	"errors"
 	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/smithy-go"
 )
 
 func IsNotFound(err error) bool {
 	oe := &iamtypes.NoSuchEntityException{}
 	return errors.As(err, &oe)
 }
+
+func IsAccessDenied(err error) bool {
+	var oe smithy.APIError
+	if !errors.As(err, &oe) {
+		return false
+	}
+	return oe.ErrorCode() == "AccessDenied" || oe.ErrorCode() == "AccessDeniedException"
+}
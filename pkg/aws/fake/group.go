@@ -0,0 +1,239 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func (i *IamService) CreateGroup(
+	_ context.Context,
+	params *iam.CreateGroupInput,
+	_ ...func(*iam.Options),
+) (*iam.CreateGroupOutput, error) {
+	if params == nil {
+		params = &iam.CreateGroupInput{}
+	}
+	if _, ok := i.Groups.Load(aws.ToString(params.GroupName)); ok {
+		return nil, &iamtypes.EntityAlreadyExistsException{}
+	}
+	path := "/"
+	if params.Path != nil {
+		path = aws.ToString(params.Path)
+		if !strings.HasPrefix(path, "/") || !strings.HasSuffix(path, "/") {
+			return nil, &iamtypes.InvalidInputException{}
+		}
+	}
+	group := &iamtypes.Group{
+		Arn:        aws.String(fmt.Sprintf("arn:aws:iam::%s:group%s%s", i.AccountID, path, aws.ToString(params.GroupName))),
+		CreateDate: aws.Time(time.Now()),
+		GroupId:    aws.String(randStringSuffix("AGPA")),
+		GroupName:  params.GroupName,
+		Path:       aws.String(path),
+	}
+	i.Groups.Store(aws.ToString(params.GroupName), group)
+	return &iam.CreateGroupOutput{Group: group}, nil
+}
+
+// GetGroup returns the group along with its users
+func (i *IamService) GetGroup(
+	_ context.Context,
+	params *iam.GetGroupInput,
+	_ ...func(*iam.Options),
+) (*iam.GetGroupOutput, error) {
+	if params == nil {
+		params = &iam.GetGroupInput{}
+	}
+	name := aws.ToString(params.GroupName)
+	v, ok := i.Groups.Load(name)
+	if !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	rv := &iam.GetGroupOutput{Group: v.(*iamtypes.Group), Users: []iamtypes.User{}}
+	if members, ok := i.GroupMembers.Load(name); ok {
+		for _, userName := range members.(sets.String).List() {
+			if user, ok := i.Users.Load(userName); ok {
+				rv.Users = append(rv.Users, *user.(*iamtypes.User))
+			}
+		}
+	}
+	return rv, nil
+}
+
+// DeleteGroup deletes a group. Like AWS, a group with users or attached
+// policies can't be deleted
+func (i *IamService) DeleteGroup(
+	_ context.Context,
+	params *iam.DeleteGroupInput,
+	_ ...func(*iam.Options),
+) (*iam.DeleteGroupOutput, error) {
+	if params == nil {
+		params = &iam.DeleteGroupInput{}
+	}
+	name := aws.ToString(params.GroupName)
+	if _, ok := i.Groups.Load(name); !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	if v, ok := i.GroupMembers.Load(name); ok && v.(sets.String).Len() > 0 {
+		return nil, &iamtypes.DeleteConflictException{}
+	}
+	if v, ok := i.GroupAttachments.Load(name); ok && v.(sets.String).Len() > 0 {
+		return nil, &iamtypes.DeleteConflictException{}
+	}
+	i.Groups.Delete(name)
+	i.GroupMembers.Delete(name)
+	i.GroupAttachments.Delete(name)
+	return &iam.DeleteGroupOutput{}, nil
+}
+
+func (i *IamService) AddUserToGroup(
+	_ context.Context,
+	params *iam.AddUserToGroupInput,
+	_ ...func(*iam.Options),
+) (*iam.AddUserToGroupOutput, error) {
+	if params == nil {
+		params = &iam.AddUserToGroupInput{}
+	}
+	name := aws.ToString(params.GroupName)
+	if _, ok := i.Groups.Load(name); !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	if _, ok := i.Users.Load(aws.ToString(params.UserName)); !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	v, _ := i.GroupMembers.LoadOrStore(name, sets.NewString())
+	v.(sets.String).Insert(aws.ToString(params.UserName))
+	return &iam.AddUserToGroupOutput{}, nil
+}
+
+func (i *IamService) RemoveUserFromGroup(
+	_ context.Context,
+	params *iam.RemoveUserFromGroupInput,
+	_ ...func(*iam.Options),
+) (*iam.RemoveUserFromGroupOutput, error) {
+	if params == nil {
+		params = &iam.RemoveUserFromGroupInput{}
+	}
+	v, ok := i.GroupMembers.Load(aws.ToString(params.GroupName))
+	if !ok || !v.(sets.String).Has(aws.ToString(params.UserName)) {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	v.(sets.String).Delete(aws.ToString(params.UserName))
+	return &iam.RemoveUserFromGroupOutput{}, nil
+}
+
+// userGroups returns the names of the groups the user is in
+func (i *IamService) userGroups(userName string) []string {
+	names := make([]string, 0)
+	i.GroupMembers.Range(func(key interface{}, value interface{}) bool {
+		if value.(sets.String).Has(userName) {
+			names = append(names, key.(string))
+		}
+		return true
+	})
+	return names
+}
+
+func (i *IamService) ListGroupsForUser(
+	_ context.Context,
+	params *iam.ListGroupsForUserInput,
+	_ ...func(*iam.Options),
+) (*iam.ListGroupsForUserOutput, error) {
+	if params == nil {
+		params = &iam.ListGroupsForUserInput{}
+	}
+	if _, ok := i.Users.Load(aws.ToString(params.UserName)); !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	rv := &iam.ListGroupsForUserOutput{Groups: []iamtypes.Group{}}
+	for _, name := range i.userGroups(aws.ToString(params.UserName)) {
+		if v, ok := i.Groups.Load(name); ok {
+			rv.Groups = append(rv.Groups, *v.(*iamtypes.Group))
+		}
+	}
+	return rv, nil
+}
+
+func (i *IamService) AttachGroupPolicy(
+	_ context.Context,
+	params *iam.AttachGroupPolicyInput,
+	_ ...func(*iam.Options),
+) (*iam.AttachGroupPolicyOutput, error) {
+	if params == nil {
+		params = &iam.AttachGroupPolicyInput{}
+	}
+	name := aws.ToString(params.GroupName)
+	if _, ok := i.Groups.Load(name); !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	arn := aws.ToString(params.PolicyArn)
+	if _, ok := i.policyArnMapping.Load(arn); !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	v, _ := i.GroupAttachments.LoadOrStore(name, sets.NewString())
+	v.(sets.String).Insert(arn)
+	return &iam.AttachGroupPolicyOutput{}, nil
+}
+
+func (i *IamService) DetachGroupPolicy(
+	_ context.Context,
+	params *iam.DetachGroupPolicyInput,
+	_ ...func(*iam.Options),
+) (*iam.DetachGroupPolicyOutput, error) {
+	if params == nil {
+		params = &iam.DetachGroupPolicyInput{}
+	}
+	v, ok := i.GroupAttachments.Load(aws.ToString(params.GroupName))
+	if !ok || !v.(sets.String).Has(aws.ToString(params.PolicyArn)) {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	v.(sets.String).Delete(aws.ToString(params.PolicyArn))
+	return &iam.DetachGroupPolicyOutput{}, nil
+}
+
+func (i *IamService) ListAttachedGroupPolicies(
+	_ context.Context,
+	params *iam.ListAttachedGroupPoliciesInput,
+	_ ...func(*iam.Options),
+) (*iam.ListAttachedGroupPoliciesOutput, error) {
+	if params == nil {
+		params = &iam.ListAttachedGroupPoliciesInput{}
+	}
+	name := aws.ToString(params.GroupName)
+	if _, ok := i.Groups.Load(name); !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	rv := &iam.ListAttachedGroupPoliciesOutput{}
+	v, ok := i.GroupAttachments.Load(name)
+	if !ok {
+		return rv, nil
+	}
+	attachments := i.attachedPolicies(v.(sets.String).List(), params.PathPrefix)
+	start, end, marker, truncated := page(len(attachments), params.Marker, params.MaxItems)
+	rv.AttachedPolicies = attachments[start:end]
+	rv.Marker = marker
+	rv.IsTruncated = truncated
+	return rv, nil
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/fake"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("IamGroupService", func() {
+	var iamService = fake.NewIamService()
+	var inputCache = policies{}
+	BeforeEach(func() {
+		iamService.Reset()
+		inputCache.Reset()
+	})
+
+	It("should create a group", func() {
+		out, err := iamService.CreateGroup(ctx, &iam.CreateGroupInput{
+			GroupName: aws.String("should-create-a-group"),
+			Path:      aws.String("/controller/"),
+		})
+		Expect(err).To(Succeed())
+		Expect(aws.ToString(out.Group.Arn)).To(Equal(fmt.Sprintf("arn:aws:iam::%s:group/controller/should-create-a-group", iamService.AccountID)))
+
+		_, err = iamService.CreateGroup(ctx, &iam.CreateGroupInput{GroupName: aws.String("should-create-a-group")})
+		var e *iamtypes.EntityAlreadyExistsException
+		Expect(errors.As(err, &e)).To(BeTrue())
+	})
+	It("should add users to a group", func() {
+		_, err := iamService.CreateGroup(ctx, &iam.CreateGroupInput{GroupName: aws.String("should-add-users")})
+		Expect(err).To(Succeed())
+		_, err = iamService.CreateUser(ctx, &iam.CreateUserInput{UserName: aws.String("member")})
+		Expect(err).To(Succeed())
+		_, err = iamService.AddUserToGroup(ctx, &iam.AddUserToGroupInput{
+			GroupName: aws.String("should-add-users"),
+			UserName:  aws.String("member"),
+		})
+		Expect(err).To(Succeed())
+
+		out, err := iamService.GetGroup(ctx, &iam.GetGroupInput{GroupName: aws.String("should-add-users")})
+		Expect(err).To(Succeed())
+		Expect(out.Users).To(HaveLen(1))
+		Expect(aws.ToString(out.Users[0].UserName)).To(Equal("member"))
+
+		groups, err := iamService.ListGroupsForUser(ctx, &iam.ListGroupsForUserInput{UserName: aws.String("member")})
+		Expect(err).To(Succeed())
+		Expect(groups.Groups).To(HaveLen(1))
+
+		var e *iamtypes.DeleteConflictException
+		_, err = iamService.DeleteUser(ctx, &iam.DeleteUserInput{UserName: aws.String("member")})
+		Expect(errors.As(err, &e)).To(BeTrue())
+		_, err = iamService.DeleteGroup(ctx, &iam.DeleteGroupInput{GroupName: aws.String("should-add-users")})
+		Expect(errors.As(err, &e)).To(BeTrue())
+
+		_, err = iamService.RemoveUserFromGroup(ctx, &iam.RemoveUserFromGroupInput{
+			GroupName: aws.String("should-add-users"),
+			UserName:  aws.String("member"),
+		})
+		Expect(err).To(Succeed())
+		_, err = iamService.DeleteGroup(ctx, &iam.DeleteGroupInput{GroupName: aws.String("should-add-users")})
+		Expect(err).To(Succeed())
+	})
+	It("should attach a policy to a group", func() {
+		_, err := iamService.CreateGroup(ctx, &iam.CreateGroupInput{GroupName: aws.String("should-attach")})
+		Expect(err).To(Succeed())
+		policy, err := iamService.CreatePolicy(ctx, inputCache.Pop("AWSHealthFullAccess"))
+		Expect(err).To(Succeed())
+		_, err = iamService.AttachGroupPolicy(ctx, &iam.AttachGroupPolicyInput{
+			GroupName: aws.String("should-attach"),
+			PolicyArn: policy.Policy.Arn,
+		})
+		Expect(err).To(Succeed())
+		out, err := iamService.ListAttachedGroupPolicies(ctx, &iam.ListAttachedGroupPoliciesInput{
+			GroupName: aws.String("should-attach"),
+		})
+		Expect(err).To(Succeed())
+		Expect(out.AttachedPolicies).To(HaveLen(1))
+		_, err = iamService.DetachGroupPolicy(ctx, &iam.DetachGroupPolicyInput{
+			GroupName: aws.String("should-attach"),
+			PolicyArn: policy.Policy.Arn,
+		})
+		Expect(err).To(Succeed())
+	})
+})
@@ -18,6 +18,8 @@ package fake
 
 import (
 	"math/rand"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -35,6 +37,21 @@ type Cache struct {
 	Attachments     sync.Map
 	Roles           sync.Map
 	ManagedPolicies sync.Map
+	// mapping role names to their inline policy documents
+	InlinePolicies sync.Map
+	Users          sync.Map
+	// mapping user names to the arns of their attached policies
+	UserAttachments sync.Map
+	// mapping user names to their access keys
+	AccessKeys sync.Map
+	Groups     sync.Map
+	// mapping group names to the names of their users
+	GroupMembers sync.Map
+	// mapping group names to the arns of their attached policies
+	GroupAttachments sync.Map
+	InstanceProfiles sync.Map
+	// mapping arns to oidc providers
+	OIDCProviders sync.Map
 	// mapping ARNs to policy names
 	policyArnMapping sync.Map
 }
@@ -69,13 +86,13 @@ func (pv *policyVersions) Default() iamtypes.PolicyVersion {
 	return *v
 }
 
+// Latest returns the version with the highest version number. Version ids
+// are never reused, so it's the most recently created version
 func (pv *policyVersions) Latest() iamtypes.PolicyVersion {
-	policy := &iamtypes.PolicyVersion{
-		CreateDate: aws.Time(time.Now().UTC().Add(time.Second * DefaultMaxSessionDuration)),
-	}
+	policy := &iamtypes.PolicyVersion{}
 	pv.Range(func(key interface{}, value interface{}) bool {
 		v := value.(iamtypes.PolicyVersion)
-		if aws.ToTime(v.CreateDate).Before(aws.ToTime(policy.CreateDate)) {
+		if versionNumber(v.VersionId) > versionNumber(policy.VersionId) {
 			*policy = v
 		}
 		return true
@@ -83,6 +100,12 @@ func (pv *policyVersions) Latest() iamtypes.PolicyVersion {
 	return *policy
 }
 
+// versionNumber returns the number of a policy version id, e.g. 3 for v3
+func versionNumber(versionId *string) int {
+	number, _ := strconv.Atoi(strings.TrimPrefix(aws.ToString(versionId), "v"))
+	return number
+}
+
 type managedPolicy struct {
 	versions *policyVersions
 	policy   iamtypes.Policy
@@ -93,6 +116,15 @@ func (i *IamService) Reset() {
 		Attachments:      sync.Map{},
 		Roles:            sync.Map{},
 		ManagedPolicies:  sync.Map{},
+		InlinePolicies:   sync.Map{},
+		Users:            sync.Map{},
+		UserAttachments:  sync.Map{},
+		AccessKeys:       sync.Map{},
+		Groups:           sync.Map{},
+		GroupMembers:     sync.Map{},
+		GroupAttachments: sync.Map{},
+		InstanceProfiles: sync.Map{},
+		OIDCProviders:    sync.Map{},
 		policyArnMapping: sync.Map{},
 	}
 
@@ -0,0 +1,179 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+func (i *IamService) CreateInstanceProfile(
+	_ context.Context,
+	params *iam.CreateInstanceProfileInput,
+	_ ...func(*iam.Options),
+) (*iam.CreateInstanceProfileOutput, error) {
+	if params == nil {
+		params = &iam.CreateInstanceProfileInput{}
+	}
+	if _, ok := i.InstanceProfiles.Load(aws.ToString(params.InstanceProfileName)); ok {
+		return nil, &iamtypes.EntityAlreadyExistsException{}
+	}
+	path := "/"
+	if params.Path != nil {
+		path = aws.ToString(params.Path)
+		if !strings.HasPrefix(path, "/") || !strings.HasSuffix(path, "/") {
+			return nil, &iamtypes.InvalidInputException{}
+		}
+	}
+	profile := &iamtypes.InstanceProfile{
+		Arn:                 aws.String(fmt.Sprintf("arn:aws:iam::%s:instance-profile%s%s", i.AccountID, path, aws.ToString(params.InstanceProfileName))),
+		CreateDate:          aws.Time(time.Now()),
+		InstanceProfileId:   aws.String(randStringSuffix("AIPA")),
+		InstanceProfileName: params.InstanceProfileName,
+		Path:                aws.String(path),
+		Roles:               []iamtypes.Role{},
+		Tags:                params.Tags,
+	}
+	i.InstanceProfiles.Store(aws.ToString(params.InstanceProfileName), profile)
+	return &iam.CreateInstanceProfileOutput{InstanceProfile: profile}, nil
+}
+
+func (i *IamService) GetInstanceProfile(
+	_ context.Context,
+	params *iam.GetInstanceProfileInput,
+	_ ...func(*iam.Options),
+) (*iam.GetInstanceProfileOutput, error) {
+	if params == nil {
+		params = &iam.GetInstanceProfileInput{}
+	}
+	v, ok := i.InstanceProfiles.Load(aws.ToString(params.InstanceProfileName))
+	if !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	return &iam.GetInstanceProfileOutput{InstanceProfile: v.(*iamtypes.InstanceProfile)}, nil
+}
+
+// DeleteInstanceProfile deletes an instance profile. Like AWS, an instance
+// profile with a role can't be deleted
+func (i *IamService) DeleteInstanceProfile(
+	_ context.Context,
+	params *iam.DeleteInstanceProfileInput,
+	_ ...func(*iam.Options),
+) (*iam.DeleteInstanceProfileOutput, error) {
+	if params == nil {
+		params = &iam.DeleteInstanceProfileInput{}
+	}
+	name := aws.ToString(params.InstanceProfileName)
+	v, ok := i.InstanceProfiles.Load(name)
+	if !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	if len(v.(*iamtypes.InstanceProfile).Roles) > 0 {
+		return nil, &iamtypes.DeleteConflictException{}
+	}
+	i.InstanceProfiles.Delete(name)
+	return &iam.DeleteInstanceProfileOutput{}, nil
+}
+
+// AddRoleToInstanceProfile adds a role to an instance profile. Like AWS, an
+// instance profile can only have one role
+func (i *IamService) AddRoleToInstanceProfile(
+	_ context.Context,
+	params *iam.AddRoleToInstanceProfileInput,
+	_ ...func(*iam.Options),
+) (*iam.AddRoleToInstanceProfileOutput, error) {
+	if params == nil {
+		params = &iam.AddRoleToInstanceProfileInput{}
+	}
+	v, ok := i.InstanceProfiles.Load(aws.ToString(params.InstanceProfileName))
+	if !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	role, ok := i.Roles.Load(aws.ToString(params.RoleName))
+	if !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	profile := v.(*iamtypes.InstanceProfile)
+	if len(profile.Roles) > 0 {
+		return nil, &iamtypes.LimitExceededException{}
+	}
+	profile.Roles = []iamtypes.Role{*role.(*iamtypes.Role)}
+	return &iam.AddRoleToInstanceProfileOutput{}, nil
+}
+
+func (i *IamService) RemoveRoleFromInstanceProfile(
+	_ context.Context,
+	params *iam.RemoveRoleFromInstanceProfileInput,
+	_ ...func(*iam.Options),
+) (*iam.RemoveRoleFromInstanceProfileOutput, error) {
+	if params == nil {
+		params = &iam.RemoveRoleFromInstanceProfileInput{}
+	}
+	v, ok := i.InstanceProfiles.Load(aws.ToString(params.InstanceProfileName))
+	if !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	profile := v.(*iamtypes.InstanceProfile)
+	roles := make([]iamtypes.Role, 0, len(profile.Roles))
+	for _, role := range profile.Roles {
+		if aws.ToString(role.RoleName) != aws.ToString(params.RoleName) {
+			roles = append(roles, role)
+		}
+	}
+	if len(roles) == len(profile.Roles) {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	profile.Roles = roles
+	return &iam.RemoveRoleFromInstanceProfileOutput{}, nil
+}
+
+// roleInstanceProfiles returns the instance profiles the role is in
+func (i *IamService) roleInstanceProfiles(roleName string) []iamtypes.InstanceProfile {
+	profiles := make([]iamtypes.InstanceProfile, 0)
+	i.InstanceProfiles.Range(func(_ interface{}, value interface{}) bool {
+		profile := value.(*iamtypes.InstanceProfile)
+		for _, role := range profile.Roles {
+			if aws.ToString(role.RoleName) == roleName {
+				profiles = append(profiles, *profile)
+			}
+		}
+		return true
+	})
+	return profiles
+}
+
+func (i *IamService) ListInstanceProfilesForRole(
+	_ context.Context,
+	params *iam.ListInstanceProfilesForRoleInput,
+	_ ...func(*iam.Options),
+) (*iam.ListInstanceProfilesForRoleOutput, error) {
+	if params == nil {
+		params = &iam.ListInstanceProfilesForRoleInput{}
+	}
+	if _, ok := i.Roles.Load(aws.ToString(params.RoleName)); !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	return &iam.ListInstanceProfilesForRoleOutput{
+		InstanceProfiles: i.roleInstanceProfiles(aws.ToString(params.RoleName)),
+	}, nil
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/fake"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("IamInstanceProfileService", func() {
+	var iamService = fake.NewIamService()
+	BeforeEach(func() {
+		iamService.Reset()
+	})
+
+	It("should create an instance profile", func() {
+		out, err := iamService.CreateInstanceProfile(ctx, &iam.CreateInstanceProfileInput{
+			InstanceProfileName: aws.String("should-create-a-profile"),
+			Path:                aws.String("/controller/"),
+		})
+		Expect(err).To(Succeed())
+		Expect(aws.ToString(out.InstanceProfile.Arn)).To(Equal(fmt.Sprintf("arn:aws:iam::%s:instance-profile/controller/should-create-a-profile", iamService.AccountID)))
+
+		_, err = iamService.CreateInstanceProfile(ctx, &iam.CreateInstanceProfileInput{InstanceProfileName: aws.String("should-create-a-profile")})
+		var e *iamtypes.EntityAlreadyExistsException
+		Expect(errors.As(err, &e)).To(BeTrue())
+	})
+	It("should only add one role to an instance profile", func() {
+		_, err := iamService.CreateInstanceProfile(ctx, &iam.CreateInstanceProfileInput{InstanceProfileName: aws.String("nodes")})
+		Expect(err).To(Succeed())
+		for _, name := range []string{"first", "second"} {
+			_, err = iamService.CreateRole(ctx, &iam.CreateRoleInput{RoleName: aws.String(name), AssumeRolePolicyDocument: aws.String("{}")})
+			Expect(err).To(Succeed())
+		}
+		_, err = iamService.AddRoleToInstanceProfile(ctx, &iam.AddRoleToInstanceProfileInput{
+			InstanceProfileName: aws.String("nodes"),
+			RoleName:            aws.String("first"),
+		})
+		Expect(err).To(Succeed())
+		_, err = iamService.AddRoleToInstanceProfile(ctx, &iam.AddRoleToInstanceProfileInput{
+			InstanceProfileName: aws.String("nodes"),
+			RoleName:            aws.String("second"),
+		})
+		var e *iamtypes.LimitExceededException
+		Expect(errors.As(err, &e)).To(BeTrue())
+
+		out, err := iamService.ListInstanceProfilesForRole(ctx, &iam.ListInstanceProfilesForRoleInput{RoleName: aws.String("first")})
+		Expect(err).To(Succeed())
+		Expect(out.InstanceProfiles).To(HaveLen(1))
+		Expect(aws.ToString(out.InstanceProfiles[0].InstanceProfileName)).To(Equal("nodes"))
+	})
+	It("should not delete a role that is in an instance profile", func() {
+		_, err := iamService.CreateInstanceProfile(ctx, &iam.CreateInstanceProfileInput{InstanceProfileName: aws.String("nodes")})
+		Expect(err).To(Succeed())
+		_, err = iamService.CreateRole(ctx, &iam.CreateRoleInput{RoleName: aws.String("node"), AssumeRolePolicyDocument: aws.String("{}")})
+		Expect(err).To(Succeed())
+		_, err = iamService.AddRoleToInstanceProfile(ctx, &iam.AddRoleToInstanceProfileInput{
+			InstanceProfileName: aws.String("nodes"),
+			RoleName:            aws.String("node"),
+		})
+		Expect(err).To(Succeed())
+
+		_, err = iamService.DeleteRole(ctx, &iam.DeleteRoleInput{RoleName: aws.String("node")})
+		var conflict *iamtypes.DeleteConflictException
+		Expect(errors.As(err, &conflict)).To(BeTrue())
+		_, err = iamService.DeleteInstanceProfile(ctx, &iam.DeleteInstanceProfileInput{InstanceProfileName: aws.String("nodes")})
+		Expect(errors.As(err, &conflict)).To(BeTrue())
+
+		_, err = iamService.RemoveRoleFromInstanceProfile(ctx, &iam.RemoveRoleFromInstanceProfileInput{
+			InstanceProfileName: aws.String("nodes"),
+			RoleName:            aws.String("node"),
+		})
+		Expect(err).To(Succeed())
+		_, err = iamService.DeleteRole(ctx, &iam.DeleteRoleInput{RoleName: aws.String("node")})
+		Expect(err).To(Succeed())
+		_, err = iamService.DeleteInstanceProfile(ctx, &iam.DeleteInstanceProfileInput{InstanceProfileName: aws.String("nodes")})
+		Expect(err).To(Succeed())
+	})
+})
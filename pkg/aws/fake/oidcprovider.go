@@ -0,0 +1,171 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// MaxThumbprints is the number of thumbprints an oidc provider can have
+const MaxThumbprints = 5
+
+// CreateOpenIDConnectProvider creates an oidc provider. Like AWS, the arn is
+// derived from the url without the scheme
+func (i *IamService) CreateOpenIDConnectProvider(
+	_ context.Context,
+	params *iam.CreateOpenIDConnectProviderInput,
+	_ ...func(*iam.Options),
+) (*iam.CreateOpenIDConnectProviderOutput, error) {
+	if params == nil {
+		params = &iam.CreateOpenIDConnectProviderInput{}
+	}
+	url := aws.ToString(params.Url)
+	if !strings.HasPrefix(url, "https://") || len(params.ThumbprintList) > MaxThumbprints {
+		return nil, &iamtypes.InvalidInputException{}
+	}
+	url = strings.TrimPrefix(url, "https://")
+	arn := fmt.Sprintf("arn:aws:iam::%s:oidc-provider/%s", i.AccountID, url)
+	if _, ok := i.OIDCProviders.Load(arn); ok {
+		return nil, &iamtypes.EntityAlreadyExistsException{}
+	}
+	i.OIDCProviders.Store(arn, &iam.GetOpenIDConnectProviderOutput{
+		ClientIDList:   sets.NewString(params.ClientIDList...).List(),
+		CreateDate:     aws.Time(time.Now()),
+		Tags:           params.Tags,
+		ThumbprintList: params.ThumbprintList,
+		Url:            aws.String(url),
+	})
+	return &iam.CreateOpenIDConnectProviderOutput{OpenIDConnectProviderArn: aws.String(arn), Tags: params.Tags}, nil
+}
+
+// oidcProvider returns the oidc provider with the arn
+func (i *IamService) oidcProvider(arn *string) (*iam.GetOpenIDConnectProviderOutput, error) {
+	v, ok := i.OIDCProviders.Load(aws.ToString(arn))
+	if !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	return v.(*iam.GetOpenIDConnectProviderOutput), nil
+}
+
+func (i *IamService) GetOpenIDConnectProvider(
+	_ context.Context,
+	params *iam.GetOpenIDConnectProviderInput,
+	_ ...func(*iam.Options),
+) (*iam.GetOpenIDConnectProviderOutput, error) {
+	if params == nil {
+		params = &iam.GetOpenIDConnectProviderInput{}
+	}
+	provider, err := i.oidcProvider(params.OpenIDConnectProviderArn)
+	if err != nil {
+		return nil, err
+	}
+	rv := *provider
+	return &rv, nil
+}
+
+func (i *IamService) DeleteOpenIDConnectProvider(
+	_ context.Context,
+	params *iam.DeleteOpenIDConnectProviderInput,
+	_ ...func(*iam.Options),
+) (*iam.DeleteOpenIDConnectProviderOutput, error) {
+	if params == nil {
+		params = &iam.DeleteOpenIDConnectProviderInput{}
+	}
+	if _, err := i.oidcProvider(params.OpenIDConnectProviderArn); err != nil {
+		return nil, err
+	}
+	i.OIDCProviders.Delete(aws.ToString(params.OpenIDConnectProviderArn))
+	return &iam.DeleteOpenIDConnectProviderOutput{}, nil
+}
+
+func (i *IamService) ListOpenIDConnectProviders(
+	_ context.Context,
+	_ *iam.ListOpenIDConnectProvidersInput,
+	_ ...func(*iam.Options),
+) (*iam.ListOpenIDConnectProvidersOutput, error) {
+	arns := sets.NewString()
+	i.OIDCProviders.Range(func(key interface{}, _ interface{}) bool {
+		arns.Insert(key.(string))
+		return true
+	})
+	rv := &iam.ListOpenIDConnectProvidersOutput{OpenIDConnectProviderList: []iamtypes.OpenIDConnectProviderListEntry{}}
+	for _, arn := range arns.List() {
+		rv.OpenIDConnectProviderList = append(rv.OpenIDConnectProviderList, iamtypes.OpenIDConnectProviderListEntry{
+			Arn: aws.String(arn),
+		})
+	}
+	return rv, nil
+}
+
+func (i *IamService) AddClientIDToOpenIDConnectProvider(
+	_ context.Context,
+	params *iam.AddClientIDToOpenIDConnectProviderInput,
+	_ ...func(*iam.Options),
+) (*iam.AddClientIDToOpenIDConnectProviderOutput, error) {
+	if params == nil {
+		params = &iam.AddClientIDToOpenIDConnectProviderInput{}
+	}
+	provider, err := i.oidcProvider(params.OpenIDConnectProviderArn)
+	if err != nil {
+		return nil, err
+	}
+	provider.ClientIDList = sets.NewString(provider.ClientIDList...).Insert(aws.ToString(params.ClientID)).List()
+	return &iam.AddClientIDToOpenIDConnectProviderOutput{}, nil
+}
+
+func (i *IamService) RemoveClientIDFromOpenIDConnectProvider(
+	_ context.Context,
+	params *iam.RemoveClientIDFromOpenIDConnectProviderInput,
+	_ ...func(*iam.Options),
+) (*iam.RemoveClientIDFromOpenIDConnectProviderOutput, error) {
+	if params == nil {
+		params = &iam.RemoveClientIDFromOpenIDConnectProviderInput{}
+	}
+	provider, err := i.oidcProvider(params.OpenIDConnectProviderArn)
+	if err != nil {
+		return nil, err
+	}
+	provider.ClientIDList = sets.NewString(provider.ClientIDList...).Delete(aws.ToString(params.ClientID)).List()
+	return &iam.RemoveClientIDFromOpenIDConnectProviderOutput{}, nil
+}
+
+func (i *IamService) UpdateOpenIDConnectProviderThumbprint(
+	_ context.Context,
+	params *iam.UpdateOpenIDConnectProviderThumbprintInput,
+	_ ...func(*iam.Options),
+) (*iam.UpdateOpenIDConnectProviderThumbprintOutput, error) {
+	if params == nil {
+		params = &iam.UpdateOpenIDConnectProviderThumbprintInput{}
+	}
+	provider, err := i.oidcProvider(params.OpenIDConnectProviderArn)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.ThumbprintList) > MaxThumbprints {
+		return nil, &iamtypes.InvalidInputException{}
+	}
+	provider.ThumbprintList = params.ThumbprintList
+	return &iam.UpdateOpenIDConnectProviderThumbprintOutput{}, nil
+}
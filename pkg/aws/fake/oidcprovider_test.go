@@ -0,0 +1,96 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/fake"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("IamOIDCProviderService", func() {
+	var iamService = fake.NewIamService()
+	BeforeEach(func() {
+		iamService.Reset()
+	})
+
+	It("should create an oidc provider", func() {
+		out, err := iamService.CreateOpenIDConnectProvider(ctx, &iam.CreateOpenIDConnectProviderInput{
+			Url:          aws.String("https://oidc.eks.us-east-1.amazonaws.com/id/EXAMPLE"),
+			ClientIDList: []string{"sts.amazonaws.com"},
+		})
+		Expect(err).To(Succeed())
+		arn := aws.ToString(out.OpenIDConnectProviderArn)
+		Expect(arn).To(Equal(fmt.Sprintf("arn:aws:iam::%s:oidc-provider/oidc.eks.us-east-1.amazonaws.com/id/EXAMPLE", iamService.AccountID)))
+
+		provider, err := iamService.GetOpenIDConnectProvider(ctx, &iam.GetOpenIDConnectProviderInput{OpenIDConnectProviderArn: aws.String(arn)})
+		Expect(err).To(Succeed())
+		Expect(aws.ToString(provider.Url)).To(Equal("oidc.eks.us-east-1.amazonaws.com/id/EXAMPLE"))
+		Expect(provider.ClientIDList).To(ConsistOf("sts.amazonaws.com"))
+
+		_, err = iamService.CreateOpenIDConnectProvider(ctx, &iam.CreateOpenIDConnectProviderInput{
+			Url: aws.String("https://oidc.eks.us-east-1.amazonaws.com/id/EXAMPLE"),
+		})
+		var e *iamtypes.EntityAlreadyExistsException
+		Expect(errors.As(err, &e)).To(BeTrue())
+
+		list, err := iamService.ListOpenIDConnectProviders(ctx, &iam.ListOpenIDConnectProvidersInput{})
+		Expect(err).To(Succeed())
+		Expect(list.OpenIDConnectProviderList).To(HaveLen(1))
+	})
+	It("should update the client ids and thumbprints", func() {
+		out, err := iamService.CreateOpenIDConnectProvider(ctx, &iam.CreateOpenIDConnectProviderInput{
+			Url:            aws.String("https://oidc.eks.us-east-1.amazonaws.com/id/EXAMPLE"),
+			ClientIDList:   []string{"sts.amazonaws.com"},
+			ThumbprintList: []string{"9e99a48a9960b14926bb7f3b02e22da2b0ab7280"},
+		})
+		Expect(err).To(Succeed())
+		_, err = iamService.AddClientIDToOpenIDConnectProvider(ctx, &iam.AddClientIDToOpenIDConnectProviderInput{
+			OpenIDConnectProviderArn: out.OpenIDConnectProviderArn,
+			ClientID:                 aws.String("vault"),
+		})
+		Expect(err).To(Succeed())
+		_, err = iamService.RemoveClientIDFromOpenIDConnectProvider(ctx, &iam.RemoveClientIDFromOpenIDConnectProviderInput{
+			OpenIDConnectProviderArn: out.OpenIDConnectProviderArn,
+			ClientID:                 aws.String("sts.amazonaws.com"),
+		})
+		Expect(err).To(Succeed())
+		_, err = iamService.UpdateOpenIDConnectProviderThumbprint(ctx, &iam.UpdateOpenIDConnectProviderThumbprintInput{
+			OpenIDConnectProviderArn: out.OpenIDConnectProviderArn,
+			ThumbprintList:           []string{"a031c46782e6e6c662c2c87c76da9aa62ccabd8e"},
+		})
+		Expect(err).To(Succeed())
+
+		provider, err := iamService.GetOpenIDConnectProvider(ctx, &iam.GetOpenIDConnectProviderInput{OpenIDConnectProviderArn: out.OpenIDConnectProviderArn})
+		Expect(err).To(Succeed())
+		Expect(provider.ClientIDList).To(ConsistOf("vault"))
+		Expect(provider.ThumbprintList).To(ConsistOf("a031c46782e6e6c662c2c87c76da9aa62ccabd8e"))
+
+		_, err = iamService.DeleteOpenIDConnectProvider(ctx, &iam.DeleteOpenIDConnectProviderInput{OpenIDConnectProviderArn: out.OpenIDConnectProviderArn})
+		Expect(err).To(Succeed())
+		_, err = iamService.GetOpenIDConnectProvider(ctx, &iam.GetOpenIDConnectProviderInput{OpenIDConnectProviderArn: out.OpenIDConnectProviderArn})
+		var e *iamtypes.NoSuchEntityException
+		Expect(errors.As(err, &e)).To(BeTrue())
+	})
+})
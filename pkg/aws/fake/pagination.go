@@ -0,0 +1,46 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// DefaultMaxItems is the page size of list calls that don't set MaxItems,
+// which is the same as AWS
+const DefaultMaxItems = 100
+
+// page returns the bounds of the page of n items that starts at the marker,
+// along with the marker of the next page. Markers are the index of the first
+// item on the page
+func page(n int, marker *string, maxItems *int32) (int, int, *string, bool) {
+	start, _ := strconv.Atoi(aws.ToString(marker))
+	if start > n {
+		start = n
+	}
+	size := DefaultMaxItems
+	if maxItems != nil && *maxItems > 0 {
+		size = int(*maxItems)
+	}
+	end := start + size
+	if end >= n {
+		return start, n, nil, false
+	}
+	return start, end, aws.String(strconv.Itoa(end)), true
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -40,13 +41,45 @@ func (i *IamService) ListPolicies(_ context.Context, params *iam.ListPoliciesInp
 	policies := make([]iamtypes.Policy, 0)
 	i.ManagedPolicies.Range(func(k interface{}, v interface{}) bool {
 		mp := v.(managedPolicy)
-		if params.PathPrefix == nil || aws.ToString(mp.policy.Path) == aws.ToString(params.PathPrefix) {
+		if params.PathPrefix == nil || strings.HasPrefix(aws.ToString(mp.policy.Path), aws.ToString(params.PathPrefix)) {
 			policies = append(policies, mp.policy)
 		}
 		return true
 	})
+	// Pages need a stable order
+	sort.Slice(policies, func(a, b int) bool {
+		return aws.ToString(policies[a].PolicyName) < aws.ToString(policies[b].PolicyName)
+	})
+	start, end, marker, truncated := page(len(policies), params.Marker, params.MaxItems)
+	return &iam.ListPoliciesOutput{
+		Policies:    policies[start:end],
+		Marker:      marker,
+		IsTruncated: truncated,
+	}, nil
+}
 
-	return &iam.ListPoliciesOutput{Policies: policies}, nil
+// attachedPolicies returns the policies with the arns that are under the
+// path prefix. Every policy is returned when the prefix is nil
+func (i *IamService) attachedPolicies(arns []string, pathPrefix *string) []iamtypes.AttachedPolicy {
+	policies := make([]iamtypes.AttachedPolicy, 0, len(arns))
+	for _, arn := range arns {
+		name, ok := i.policyArnMapping.Load(arn)
+		if !ok {
+			continue
+		}
+		v, ok := i.ManagedPolicies.Load(name)
+		if !ok {
+			continue
+		}
+		if pathPrefix != nil && !strings.HasPrefix(aws.ToString(v.(managedPolicy).policy.Path), aws.ToString(pathPrefix)) {
+			continue
+		}
+		policies = append(policies, iamtypes.AttachedPolicy{
+			PolicyArn:  aws.String(arn),
+			PolicyName: aws.String(name.(string)),
+		})
+	}
+	return policies
 }
 
 func (i *IamService) CreatePolicy(_ context.Context, p *iam.CreatePolicyInput, _ ...func(*iam.Options)) (*iam.CreatePolicyOutput, error) {
@@ -72,9 +105,10 @@ func (i *IamService) CreatePolicy(_ context.Context, p *iam.CreatePolicyInput, _
 		DefaultVersionId: aws.String("v1"),
 		Description:      p.Description,
 		IsAttachable:     true,
-		Path:             p.Path,
+		Path:             path,
 		PolicyId:         aws.String(randStringSuffix("ANPA")),
 		PolicyName:       p.PolicyName,
+		Tags:             addTags(nil, p.Tags),
 	}
 	out.Policy = &policy
 
@@ -207,4 +241,46 @@ func (i *IamService) GetPolicyVersion(_ context.Context, in *iam.GetPolicyVersio
 	return &iam.GetPolicyVersionOutput{PolicyVersion: version}, nil
 }
 
+func (i *IamService) ListPolicyVersions(_ context.Context, in *iam.ListPolicyVersionsInput, _ ...func(*iam.Options)) (*iam.ListPolicyVersionsOutput, error) {
+	name, ok := i.policyArnMapping.Load(aws.ToString(in.PolicyArn))
+	if !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	v, _ := i.ManagedPolicies.Load(name)
+	mp := v.(managedPolicy)
+	versions := make([]iamtypes.PolicyVersion, 0)
+	mp.versions.Range(func(_ interface{}, value interface{}) bool {
+		version := value.(iamtypes.PolicyVersion)
+		// Listing versions doesn't return the documents
+		version.Document = nil
+		versions = append(versions, version)
+		return true
+	})
+	sort.Slice(versions, func(a, b int) bool {
+		return versionNumber(versions[a].VersionId) > versionNumber(versions[b].VersionId)
+	})
+	return &iam.ListPolicyVersionsOutput{Versions: versions}, nil
+}
+
+func (i *IamService) SetDefaultPolicyVersion(_ context.Context, in *iam.SetDefaultPolicyVersionInput, _ ...func(*iam.Options)) (*iam.SetDefaultPolicyVersionOutput, error) {
+	name, ok := i.policyArnMapping.Load(aws.ToString(in.PolicyArn))
+	if !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	v, _ := i.ManagedPolicies.Load(name)
+	mp := v.(managedPolicy)
+	if _, ok := mp.versions.Load(aws.ToString(in.VersionId)); !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	mp.versions.Range(func(key interface{}, value interface{}) bool {
+		version := value.(iamtypes.PolicyVersion)
+		version.IsDefaultVersion = aws.ToString(version.VersionId) == aws.ToString(in.VersionId)
+		mp.versions.Store(key, version)
+		return true
+	})
+	mp.policy.DefaultVersionId = in.VersionId
+	i.Cache.ManagedPolicies.Store(name, mp)
+	return &iam.SetDefaultPolicyVersionOutput{}, nil
+}
+
 var _ pkgaws.IamPolicyService = &IamService{}
@@ -23,6 +23,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
 	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	pkgaws "github.com/johnhoman/aws-iam-controller/pkg/aws"
 	"github.com/johnhoman/aws-iam-controller/pkg/aws/fake"
 
 	. "github.com/onsi/ginkgo"
@@ -184,5 +185,68 @@ var _ = Describe("Iam Policy", func() {
 			Expect(errors.As(err, &er)).To(BeTrue())
 
 		})
+		It("should list policy versions newest first", func() {
+			for k := 0; k < 2; k++ {
+				_, err := service.CreatePolicyVersion(ctx, &iam.CreatePolicyVersionInput{
+					PolicyArn:      p.Arn,
+					PolicyDocument: aws.String(`{"Version":"2012-10-17","Statement":[]}`),
+				})
+				Expect(err).ShouldNot(HaveOccurred())
+			}
+			out, err := service.ListPolicyVersions(ctx, &iam.ListPolicyVersionsInput{PolicyArn: p.Arn})
+			Expect(err).ShouldNot(HaveOccurred())
+			ids := make([]string, 0, len(out.Versions))
+			for _, version := range out.Versions {
+				ids = append(ids, aws.ToString(version.VersionId))
+			}
+			Expect(ids).Should(Equal([]string{"v3", "v2", "v1"}))
+			Expect(out.Versions[2].IsDefaultVersion).Should(BeTrue())
+		})
+		It("should set the default policy version", func() {
+			_, err := service.CreatePolicyVersion(ctx, &iam.CreatePolicyVersionInput{
+				PolicyArn:      p.Arn,
+				PolicyDocument: aws.String(`{"Version":"2012-10-17","Statement":[]}`),
+				SetAsDefault:   true,
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			_, err = service.SetDefaultPolicyVersion(ctx, &iam.SetDefaultPolicyVersionInput{
+				PolicyArn: p.Arn,
+				VersionId: aws.String("v1"),
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			out, err := service.GetPolicy(ctx, &iam.GetPolicyInput{PolicyArn: p.Arn})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(aws.ToString(out.Policy.DefaultVersionId)).Should(Equal("v1"))
+			version, err := service.GetPolicyVersion(ctx, &iam.GetPolicyVersionInput{PolicyArn: p.Arn, VersionId: aws.String("v2")})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(version.PolicyVersion.IsDefaultVersion).Should(BeFalse())
+		})
+		It("should return not found when setting a default version that doesn't exist", func() {
+			_, err := service.SetDefaultPolicyVersion(ctx, &iam.SetDefaultPolicyVersionInput{
+				PolicyArn: p.Arn,
+				VersionId: aws.String("v10"),
+			})
+			er := &iamtypes.NoSuchEntityException{}
+			Expect(errors.As(err, &er)).To(BeTrue())
+		})
+		It("should tag and untag a policy", func() {
+			_, err := service.TagPolicy(ctx, &iam.TagPolicyInput{
+				PolicyArn: p.Arn,
+				Tags:      []iamtypes.Tag{{Key: aws.String("team"), Value: aws.String("payments")}},
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			out, err := service.GetPolicy(ctx, &iam.GetPolicyInput{PolicyArn: p.Arn})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(pkgaws.TagMap(out.Policy.Tags)).Should(Equal(map[string]string{"team": "payments"}))
+
+			_, err = service.UntagPolicy(ctx, &iam.UntagPolicyInput{
+				PolicyArn: p.Arn,
+				TagKeys:   []string{"team"},
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			out, err = service.GetPolicy(ctx, &iam.GetPolicyInput{PolicyArn: p.Arn})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(out.Policy.Tags).Should(BeEmpty())
+		})
 	})
 })
@@ -59,6 +59,51 @@ func (i *IamService) UpdateRole(
 	return &iam.UpdateRoleOutput{}, nil
 }
 
+func (i *IamService) PutRolePermissionsBoundary(
+	_ context.Context,
+	params *iam.PutRolePermissionsBoundaryInput,
+	_ ...func(*iam.Options),
+) (*iam.PutRolePermissionsBoundaryOutput, error) {
+	if params == nil {
+		params = &iam.PutRolePermissionsBoundaryInput{}
+	}
+	iRole, ok := i.Roles.Load(aws.ToString(params.RoleName))
+	if !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	if _, ok := i.policyArnMapping.Load(aws.ToString(params.PermissionsBoundary)); !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	role := iRole.(*iamtypes.Role)
+	role.PermissionsBoundary = &iamtypes.AttachedPermissionsBoundary{
+		PermissionsBoundaryArn:  params.PermissionsBoundary,
+		PermissionsBoundaryType: iamtypes.PermissionsBoundaryAttachmentTypePolicy,
+	}
+	i.Roles.Store(aws.ToString(params.RoleName), role)
+	return &iam.PutRolePermissionsBoundaryOutput{}, nil
+}
+
+func (i *IamService) DeleteRolePermissionsBoundary(
+	_ context.Context,
+	params *iam.DeleteRolePermissionsBoundaryInput,
+	_ ...func(*iam.Options),
+) (*iam.DeleteRolePermissionsBoundaryOutput, error) {
+	if params == nil {
+		params = &iam.DeleteRolePermissionsBoundaryInput{}
+	}
+	iRole, ok := i.Roles.Load(aws.ToString(params.RoleName))
+	if !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	role := iRole.(*iamtypes.Role)
+	if role.PermissionsBoundary == nil {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	role.PermissionsBoundary = nil
+	i.Roles.Store(aws.ToString(params.RoleName), role)
+	return &iam.DeleteRolePermissionsBoundaryOutput{}, nil
+}
+
 func (i *IamService) GetRole(
 	_ context.Context,
 	params *iam.GetRoleInput,
@@ -109,14 +154,20 @@ func (i *IamService) CreateRole(
 		iamRole.AssumeRolePolicyDocument = aws.String(url.QueryEscape(aws.ToString(params.AssumeRolePolicyDocument)))
 	}
 	iamRole.MaxSessionDuration = params.MaxSessionDuration
-	iamRole.PermissionsBoundary = &iamtypes.AttachedPermissionsBoundary{
-		PermissionsBoundaryArn: params.PermissionsBoundary,
+	iamRole.Tags = addTags(nil, params.Tags)
+	if params.PermissionsBoundary != nil {
+		iamRole.PermissionsBoundary = &iamtypes.AttachedPermissionsBoundary{
+			PermissionsBoundaryArn:  params.PermissionsBoundary,
+			PermissionsBoundaryType: iamtypes.PermissionsBoundaryAttachmentTypePolicy,
+		}
 	}
 
 	i.Roles.Store(aws.ToString(params.RoleName), iamRole)
 	return &iam.CreateRoleOutput{Role: iamRole}, nil
 }
 
+// DeleteRole deletes a role. Like AWS, a role that is in an instance profile
+// or still has managed or inline policies can't be deleted
 func (i *IamService) DeleteRole(
 	_ context.Context,
 	params *iam.DeleteRoleInput,
@@ -126,8 +177,19 @@ func (i *IamService) DeleteRole(
 	if !ok {
 		return nil, &iamtypes.NoSuchEntityException{}
 	}
+	if len(i.roleInstanceProfiles(aws.ToString(params.RoleName))) > 0 {
+		return nil, &iamtypes.DeleteConflictException{}
+	}
+	if v, ok := i.Attachments.Load(aws.ToString(params.RoleName)); ok && v.(sets.String).Len() > 0 {
+		return nil, &iamtypes.DeleteConflictException{}
+	}
+	if len(i.inlinePolicies(aws.ToString(params.RoleName))) > 0 {
+		return nil, &iamtypes.DeleteConflictException{}
+	}
 
 	i.Roles.Delete(aws.ToString(params.RoleName))
+	i.Attachments.Delete(aws.ToString(params.RoleName))
+	i.InlinePolicies.Delete(aws.ToString(params.RoleName))
 	return &iam.DeleteRoleOutput{}, nil
 }
 
@@ -199,22 +261,113 @@ func (i *IamService) ListAttachedRolePolicies(
 
 	key := aws.ToString(params.RoleName)
 	v, _ := i.Attachments.LoadOrStore(key, sets.NewString())
-	arns := v.(sets.String).List()
-	attachments := make([]iamtypes.AttachedPolicy, 0, len(arns))
-
-	for _, arn := range arns {
-		v, ok := i.policyArnMapping.Load(arn)
-		if !ok {
-			// TODO: This shouldn't be possible
-			continue
+	attachments := i.attachedPolicies(v.(sets.String).List(), params.PathPrefix)
+	start, end, marker, truncated := page(len(attachments), params.Marker, params.MaxItems)
+	rv.AttachedPolicies = attachments[start:end]
+	rv.Marker = marker
+	rv.IsTruncated = truncated
+	return rv, nil
+}
+
+// inlinePolicies returns a copy of the inline policy documents on a role
+// keyed by policy name
+func (i *IamService) inlinePolicies(roleName string) map[string]string {
+	policies := make(map[string]string)
+	if v, ok := i.InlinePolicies.Load(roleName); ok {
+		for name, document := range v.(map[string]string) {
+			policies[name] = document
 		}
-		attachments = append(attachments, iamtypes.AttachedPolicy{
-			PolicyArn:  aws.String(arn),
-			PolicyName: aws.String(v.(string)),
-		})
 	}
-	rv.AttachedPolicies = attachments
-	return rv, nil
+	return policies
+}
+
+func (i *IamService) PutRolePolicy(
+	_ context.Context,
+	params *iam.PutRolePolicyInput,
+	_ ...func(*iam.Options),
+) (*iam.PutRolePolicyOutput, error) {
+	if params == nil {
+		params = &iam.PutRolePolicyInput{}
+	}
+	if params.PolicyName == nil || params.PolicyDocument == nil {
+		return nil, &smithy.InvalidParamsError{}
+	}
+	key := aws.ToString(params.RoleName)
+	if _, ok := i.Roles.Load(key); !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	policies := i.inlinePolicies(key)
+	policies[aws.ToString(params.PolicyName)] = url.QueryEscape(aws.ToString(params.PolicyDocument))
+	i.InlinePolicies.Store(key, policies)
+	return &iam.PutRolePolicyOutput{}, nil
+}
+
+func (i *IamService) GetRolePolicy(
+	_ context.Context,
+	params *iam.GetRolePolicyInput,
+	_ ...func(*iam.Options),
+) (*iam.GetRolePolicyOutput, error) {
+	if params == nil {
+		params = &iam.GetRolePolicyInput{}
+	}
+	key := aws.ToString(params.RoleName)
+	if _, ok := i.Roles.Load(key); !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	document, ok := i.inlinePolicies(key)[aws.ToString(params.PolicyName)]
+	if !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	return &iam.GetRolePolicyOutput{
+		RoleName:       params.RoleName,
+		PolicyName:     params.PolicyName,
+		PolicyDocument: aws.String(document),
+	}, nil
+}
+
+func (i *IamService) DeleteRolePolicy(
+	_ context.Context,
+	params *iam.DeleteRolePolicyInput,
+	_ ...func(*iam.Options),
+) (*iam.DeleteRolePolicyOutput, error) {
+	if params == nil {
+		params = &iam.DeleteRolePolicyInput{}
+	}
+	key := aws.ToString(params.RoleName)
+	if _, ok := i.Roles.Load(key); !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	policies := i.inlinePolicies(key)
+	if _, ok := policies[aws.ToString(params.PolicyName)]; !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	delete(policies, aws.ToString(params.PolicyName))
+	i.InlinePolicies.Store(key, policies)
+	return &iam.DeleteRolePolicyOutput{}, nil
+}
+
+func (i *IamService) ListRolePolicies(
+	_ context.Context,
+	params *iam.ListRolePoliciesInput,
+	_ ...func(*iam.Options),
+) (*iam.ListRolePoliciesOutput, error) {
+	if params == nil {
+		params = &iam.ListRolePoliciesInput{}
+	}
+	key := aws.ToString(params.RoleName)
+	if _, ok := i.Roles.Load(key); !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	names := sets.NewString()
+	for name := range i.inlinePolicies(key) {
+		names.Insert(name)
+	}
+	start, end, marker, truncated := page(names.Len(), params.Marker, params.MaxItems)
+	return &iam.ListRolePoliciesOutput{
+		PolicyNames: names.List()[start:end],
+		Marker:      marker,
+		IsTruncated: truncated,
+	}, nil
 }
 
 var _ pkgaws.IamRoleService = &IamService{}
@@ -27,6 +27,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/iam"
 	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
 	"github.com/aws/smithy-go"
+	pkgaws "github.com/johnhoman/aws-iam-controller/pkg/aws"
 	"github.com/johnhoman/aws-iam-controller/pkg/aws/fake"
 
 	. "github.com/onsi/ginkgo"
@@ -235,6 +236,31 @@ var _ = Describe("IamRoleService", func() {
 		Expect(err).ShouldNot(HaveOccurred())
 		Expect(out.AttachedPolicies).Should(HaveLen(1))
 	})
+	It("should only list the attached policies under the path prefix", func() {
+		role, err := iamService.CreateRole(ctx, &iam.CreateRoleInput{
+			RoleName:                 aws.String("should-filter-attached-policies"),
+			AssumeRolePolicyDocument: aws.String("{}"),
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		for _, name := range []string{"AWSHealthFullAccess", "ClientVPNServiceRolePolicy"} {
+			policy, err := iamService.CreatePolicy(ctx, inputCache.Pop(name))
+			Expect(err).ShouldNot(HaveOccurred())
+			_, err = iamService.AttachRolePolicy(ctx, &iam.AttachRolePolicyInput{
+				PolicyArn: policy.Policy.Arn,
+				RoleName:  role.Role.RoleName,
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+		}
+
+		out, err := iamService.ListAttachedRolePolicies(ctx, &iam.ListAttachedRolePoliciesInput{
+			RoleName:   role.Role.RoleName,
+			PathPrefix: aws.String("/aws-service-role/"),
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(out.AttachedPolicies).Should(HaveLen(1))
+		Expect(aws.ToString(out.AttachedPolicies[0].PolicyName)).Should(Equal("ClientVPNServiceRolePolicy"))
+	})
 	It("should return an error when trying to attach a policy that doesn't exist", func() {
 		role, err := iamService.CreateRole(ctx, &iam.CreateRoleInput{
 			RoleName:                 aws.String("should-update-assume-role-policy-document"),
@@ -250,4 +276,116 @@ var _ = Describe("IamRoleService", func() {
 		Expect(err).Should(HaveOccurred())
 		Expect(attachment).Should(BeNil())
 	})
+	It("should put, list and delete inline policies", func() {
+		role, err := iamService.CreateRole(ctx, &iam.CreateRoleInput{
+			RoleName:                 aws.String("should-put-inline-policies"),
+			AssumeRolePolicyDocument: aws.String("{}"),
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		document := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:ListBucket","Resource":"*"}]}`
+		_, err = iamService.PutRolePolicy(ctx, &iam.PutRolePolicyInput{
+			RoleName:       role.Role.RoleName,
+			PolicyName:     aws.String("s3-list"),
+			PolicyDocument: aws.String(document),
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		list, err := iamService.ListRolePolicies(ctx, &iam.ListRolePoliciesInput{RoleName: role.Role.RoleName})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(list.PolicyNames).Should(Equal([]string{"s3-list"}))
+
+		out, err := iamService.GetRolePolicy(ctx, &iam.GetRolePolicyInput{
+			RoleName:   role.Role.RoleName,
+			PolicyName: aws.String("s3-list"),
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(aws.ToString(out.PolicyDocument)).Should(Equal(url.QueryEscape(document)))
+
+		_, err = iamService.DeleteRolePolicy(ctx, &iam.DeleteRolePolicyInput{
+			RoleName:   role.Role.RoleName,
+			PolicyName: aws.String("s3-list"),
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		_, err = iamService.GetRolePolicy(ctx, &iam.GetRolePolicyInput{
+			RoleName:   role.Role.RoleName,
+			PolicyName: aws.String("s3-list"),
+		})
+		er := &iamtypes.NoSuchEntityException{}
+		Expect(errors.As(err, &er)).To(BeTrue())
+	})
+	It("should return not found when putting an inline policy on a missing role", func() {
+		_, err := iamService.PutRolePolicy(ctx, &iam.PutRolePolicyInput{
+			RoleName:       aws.String("role-does-not-exist"),
+			PolicyName:     aws.String("s3-list"),
+			PolicyDocument: aws.String("{}"),
+		})
+		er := &iamtypes.NoSuchEntityException{}
+		Expect(errors.As(err, &er)).To(BeTrue())
+	})
+	It("should put and delete a permissions boundary", func() {
+		role, err := iamService.CreateRole(ctx, &iam.CreateRoleInput{
+			RoleName:                 aws.String("should-put-permissions-boundary"),
+			AssumeRolePolicyDocument: aws.String("{}"),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		policy, err := iamService.CreatePolicy(ctx, &iam.CreatePolicyInput{
+			PolicyName:     aws.String("boundary"),
+			PolicyDocument: aws.String("{}"),
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = iamService.PutRolePermissionsBoundary(ctx, &iam.PutRolePermissionsBoundaryInput{
+			RoleName:            role.Role.RoleName,
+			PermissionsBoundary: policy.Policy.Arn,
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		out, err := iamService.GetRole(ctx, &iam.GetRoleInput{RoleName: role.Role.RoleName})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(out.Role.PermissionsBoundary).ShouldNot(BeNil())
+		Expect(aws.ToString(out.Role.PermissionsBoundary.PermissionsBoundaryArn)).Should(Equal(aws.ToString(policy.Policy.Arn)))
+
+		_, err = iamService.DeleteRolePermissionsBoundary(ctx, &iam.DeleteRolePermissionsBoundaryInput{
+			RoleName: role.Role.RoleName,
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		out, err = iamService.GetRole(ctx, &iam.GetRoleInput{RoleName: role.Role.RoleName})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(out.Role.PermissionsBoundary).Should(BeNil())
+
+		_, err = iamService.DeleteRolePermissionsBoundary(ctx, &iam.DeleteRolePermissionsBoundaryInput{
+			RoleName: role.Role.RoleName,
+		})
+		er := &iamtypes.NoSuchEntityException{}
+		Expect(errors.As(err, &er)).To(BeTrue())
+	})
+	It("should tag and untag a role", func() {
+		role, err := iamService.CreateRole(ctx, &iam.CreateRoleInput{
+			RoleName:                 aws.String("should-tag-a-role"),
+			AssumeRolePolicyDocument: aws.String("{}"),
+			Tags:                     []iamtypes.Tag{{Key: aws.String("team"), Value: aws.String("payments")}},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = iamService.TagRole(ctx, &iam.TagRoleInput{
+			RoleName: role.Role.RoleName,
+			Tags: []iamtypes.Tag{
+				{Key: aws.String("team"), Value: aws.String("billing")},
+				{Key: aws.String("env"), Value: aws.String("prod")},
+			},
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		out, err := iamService.GetRole(ctx, &iam.GetRoleInput{RoleName: role.Role.RoleName})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(pkgaws.TagMap(out.Role.Tags)).Should(Equal(map[string]string{"team": "billing", "env": "prod"}))
+
+		_, err = iamService.UntagRole(ctx, &iam.UntagRoleInput{
+			RoleName: role.Role.RoleName,
+			TagKeys:  []string{"team"},
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		out, err = iamService.GetRole(ctx, &iam.GetRoleInput{RoleName: role.Role.RoleName})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(pkgaws.TagMap(out.Role.Tags)).Should(Equal(map[string]string{"env": "prod"}))
+	})
 })
@@ -0,0 +1,43 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+
+	pkgaws "github.com/johnhoman/aws-iam-controller/pkg/aws"
+)
+
+// GetCallerIdentity returns the identity of an assumed role in the fake
+// account
+func (i *IamService) GetCallerIdentity(
+	_ context.Context,
+	_ *sts.GetCallerIdentityInput,
+	_ ...func(*sts.Options),
+) (*sts.GetCallerIdentityOutput, error) {
+	return &sts.GetCallerIdentityOutput{
+		Account: aws.String(i.AccountID),
+		Arn:     aws.String(fmt.Sprintf("arn:aws:sts::%s:assumed-role/aws-iam-controller/fake", i.AccountID)),
+		UserId:  aws.String("AROAFAKE:fake"),
+	}, nil
+}
+
+var _ pkgaws.StsService = &IamService{}
@@ -0,0 +1,224 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+
+	pkgaws "github.com/johnhoman/aws-iam-controller/pkg/aws"
+)
+
+// addTags adds new tags to existing tags, overwriting the value of
+// any keys that already exist
+func addTags(existing []iamtypes.Tag, tags []iamtypes.Tag) []iamtypes.Tag {
+	m := pkgaws.TagMap(existing)
+	for key, value := range pkgaws.TagMap(tags) {
+		m[key] = value
+	}
+	return pkgaws.NewTags(m)
+}
+
+// removeTags removes tag keys from existing tags
+func removeTags(existing []iamtypes.Tag, keys []string) []iamtypes.Tag {
+	m := pkgaws.TagMap(existing)
+	for _, key := range keys {
+		delete(m, key)
+	}
+	return pkgaws.NewTags(m)
+}
+
+func (i *IamService) TagRole(
+	_ context.Context,
+	params *iam.TagRoleInput,
+	_ ...func(*iam.Options),
+) (*iam.TagRoleOutput, error) {
+	if params == nil {
+		params = &iam.TagRoleInput{}
+	}
+	iRole, ok := i.Roles.Load(aws.ToString(params.RoleName))
+	if !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	role := iRole.(*iamtypes.Role)
+	role.Tags = addTags(role.Tags, params.Tags)
+	i.Roles.Store(aws.ToString(params.RoleName), role)
+	return &iam.TagRoleOutput{}, nil
+}
+
+func (i *IamService) UntagRole(
+	_ context.Context,
+	params *iam.UntagRoleInput,
+	_ ...func(*iam.Options),
+) (*iam.UntagRoleOutput, error) {
+	if params == nil {
+		params = &iam.UntagRoleInput{}
+	}
+	iRole, ok := i.Roles.Load(aws.ToString(params.RoleName))
+	if !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	role := iRole.(*iamtypes.Role)
+	role.Tags = removeTags(role.Tags, params.TagKeys)
+	i.Roles.Store(aws.ToString(params.RoleName), role)
+	return &iam.UntagRoleOutput{}, nil
+}
+
+func (i *IamService) TagPolicy(
+	_ context.Context,
+	params *iam.TagPolicyInput,
+	_ ...func(*iam.Options),
+) (*iam.TagPolicyOutput, error) {
+	if params == nil {
+		params = &iam.TagPolicyInput{}
+	}
+	name, ok := i.policyArnMapping.Load(aws.ToString(params.PolicyArn))
+	if !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	v, _ := i.ManagedPolicies.Load(name)
+	mp := v.(managedPolicy)
+	mp.policy.Tags = addTags(mp.policy.Tags, params.Tags)
+	i.ManagedPolicies.Store(name, mp)
+	return &iam.TagPolicyOutput{}, nil
+}
+
+func (i *IamService) UntagPolicy(
+	_ context.Context,
+	params *iam.UntagPolicyInput,
+	_ ...func(*iam.Options),
+) (*iam.UntagPolicyOutput, error) {
+	if params == nil {
+		params = &iam.UntagPolicyInput{}
+	}
+	name, ok := i.policyArnMapping.Load(aws.ToString(params.PolicyArn))
+	if !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	v, _ := i.ManagedPolicies.Load(name)
+	mp := v.(managedPolicy)
+	mp.policy.Tags = removeTags(mp.policy.Tags, params.TagKeys)
+	i.ManagedPolicies.Store(name, mp)
+	return &iam.UntagPolicyOutput{}, nil
+}
+
+func (i *IamService) TagUser(
+	_ context.Context,
+	params *iam.TagUserInput,
+	_ ...func(*iam.Options),
+) (*iam.TagUserOutput, error) {
+	if params == nil {
+		params = &iam.TagUserInput{}
+	}
+	v, ok := i.Users.Load(aws.ToString(params.UserName))
+	if !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	user := v.(*iamtypes.User)
+	user.Tags = addTags(user.Tags, params.Tags)
+	i.Users.Store(aws.ToString(params.UserName), user)
+	return &iam.TagUserOutput{}, nil
+}
+
+func (i *IamService) UntagUser(
+	_ context.Context,
+	params *iam.UntagUserInput,
+	_ ...func(*iam.Options),
+) (*iam.UntagUserOutput, error) {
+	if params == nil {
+		params = &iam.UntagUserInput{}
+	}
+	v, ok := i.Users.Load(aws.ToString(params.UserName))
+	if !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	user := v.(*iamtypes.User)
+	user.Tags = removeTags(user.Tags, params.TagKeys)
+	i.Users.Store(aws.ToString(params.UserName), user)
+	return &iam.UntagUserOutput{}, nil
+}
+
+func (i *IamService) TagInstanceProfile(
+	_ context.Context,
+	params *iam.TagInstanceProfileInput,
+	_ ...func(*iam.Options),
+) (*iam.TagInstanceProfileOutput, error) {
+	if params == nil {
+		params = &iam.TagInstanceProfileInput{}
+	}
+	v, ok := i.InstanceProfiles.Load(aws.ToString(params.InstanceProfileName))
+	if !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	profile := v.(*iamtypes.InstanceProfile)
+	profile.Tags = addTags(profile.Tags, params.Tags)
+	i.InstanceProfiles.Store(aws.ToString(params.InstanceProfileName), profile)
+	return &iam.TagInstanceProfileOutput{}, nil
+}
+
+func (i *IamService) UntagInstanceProfile(
+	_ context.Context,
+	params *iam.UntagInstanceProfileInput,
+	_ ...func(*iam.Options),
+) (*iam.UntagInstanceProfileOutput, error) {
+	if params == nil {
+		params = &iam.UntagInstanceProfileInput{}
+	}
+	v, ok := i.InstanceProfiles.Load(aws.ToString(params.InstanceProfileName))
+	if !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	profile := v.(*iamtypes.InstanceProfile)
+	profile.Tags = removeTags(profile.Tags, params.TagKeys)
+	i.InstanceProfiles.Store(aws.ToString(params.InstanceProfileName), profile)
+	return &iam.UntagInstanceProfileOutput{}, nil
+}
+
+func (i *IamService) TagOpenIDConnectProvider(
+	_ context.Context,
+	params *iam.TagOpenIDConnectProviderInput,
+	_ ...func(*iam.Options),
+) (*iam.TagOpenIDConnectProviderOutput, error) {
+	if params == nil {
+		params = &iam.TagOpenIDConnectProviderInput{}
+	}
+	provider, err := i.oidcProvider(params.OpenIDConnectProviderArn)
+	if err != nil {
+		return nil, err
+	}
+	provider.Tags = addTags(provider.Tags, params.Tags)
+	return &iam.TagOpenIDConnectProviderOutput{}, nil
+}
+
+func (i *IamService) UntagOpenIDConnectProvider(
+	_ context.Context,
+	params *iam.UntagOpenIDConnectProviderInput,
+	_ ...func(*iam.Options),
+) (*iam.UntagOpenIDConnectProviderOutput, error) {
+	if params == nil {
+		params = &iam.UntagOpenIDConnectProviderInput{}
+	}
+	provider, err := i.oidcProvider(params.OpenIDConnectProviderArn)
+	if err != nil {
+		return nil, err
+	}
+	provider.Tags = removeTags(provider.Tags, params.TagKeys)
+	return &iam.UntagOpenIDConnectProviderOutput{}, nil
+}
@@ -0,0 +1,254 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// MaxAccessKeys is the number of access keys a user can have at once
+const MaxAccessKeys = 2
+
+func (i *IamService) CreateUser(
+	_ context.Context,
+	params *iam.CreateUserInput,
+	_ ...func(*iam.Options),
+) (*iam.CreateUserOutput, error) {
+	if params == nil {
+		params = &iam.CreateUserInput{}
+	}
+	if _, ok := i.Users.Load(aws.ToString(params.UserName)); ok {
+		return nil, &iamtypes.EntityAlreadyExistsException{}
+	}
+
+	path := "/"
+	if params.Path != nil {
+		path = aws.ToString(params.Path)
+		if !strings.HasPrefix(path, "/") || !strings.HasSuffix(path, "/") {
+			return nil, &iamtypes.InvalidInputException{}
+		}
+	}
+	user := &iamtypes.User{
+		Arn:        aws.String(fmt.Sprintf("arn:aws:iam::%s:user%s%s", i.AccountID, path, aws.ToString(params.UserName))),
+		CreateDate: aws.Time(time.Now()),
+		Path:       aws.String(path),
+		UserId:     aws.String(randStringSuffix("AIDA")),
+		UserName:   params.UserName,
+		Tags:       addTags(nil, params.Tags),
+	}
+	i.Users.Store(aws.ToString(params.UserName), user)
+	return &iam.CreateUserOutput{User: user}, nil
+}
+
+func (i *IamService) GetUser(
+	_ context.Context,
+	params *iam.GetUserInput,
+	_ ...func(*iam.Options),
+) (*iam.GetUserOutput, error) {
+	if params == nil {
+		params = &iam.GetUserInput{}
+	}
+	v, ok := i.Users.Load(aws.ToString(params.UserName))
+	if !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	return &iam.GetUserOutput{User: v.(*iamtypes.User)}, nil
+}
+
+// DeleteUser deletes a user. Like AWS, a user with attached policies,
+// access keys or groups can't be deleted
+func (i *IamService) DeleteUser(
+	_ context.Context,
+	params *iam.DeleteUserInput,
+	_ ...func(*iam.Options),
+) (*iam.DeleteUserOutput, error) {
+	if params == nil {
+		params = &iam.DeleteUserInput{}
+	}
+	name := aws.ToString(params.UserName)
+	if _, ok := i.Users.Load(name); !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	if v, ok := i.UserAttachments.Load(name); ok && v.(sets.String).Len() > 0 {
+		return nil, &iamtypes.DeleteConflictException{}
+	}
+	if len(i.accessKeys(name)) > 0 {
+		return nil, &iamtypes.DeleteConflictException{}
+	}
+	if len(i.userGroups(name)) > 0 {
+		return nil, &iamtypes.DeleteConflictException{}
+	}
+	i.Users.Delete(name)
+	i.UserAttachments.Delete(name)
+	i.AccessKeys.Delete(name)
+	return &iam.DeleteUserOutput{}, nil
+}
+
+func (i *IamService) AttachUserPolicy(
+	_ context.Context,
+	params *iam.AttachUserPolicyInput,
+	_ ...func(*iam.Options),
+) (*iam.AttachUserPolicyOutput, error) {
+	if params == nil {
+		params = &iam.AttachUserPolicyInput{}
+	}
+	name := aws.ToString(params.UserName)
+	if _, ok := i.Users.Load(name); !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	arn := aws.ToString(params.PolicyArn)
+	if _, ok := i.policyArnMapping.Load(arn); !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	v, _ := i.UserAttachments.LoadOrStore(name, sets.NewString())
+	v.(sets.String).Insert(arn)
+	return &iam.AttachUserPolicyOutput{}, nil
+}
+
+func (i *IamService) DetachUserPolicy(
+	_ context.Context,
+	params *iam.DetachUserPolicyInput,
+	_ ...func(*iam.Options),
+) (*iam.DetachUserPolicyOutput, error) {
+	if params == nil {
+		params = &iam.DetachUserPolicyInput{}
+	}
+	v, ok := i.UserAttachments.Load(aws.ToString(params.UserName))
+	if !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	policies := v.(sets.String)
+	arn := aws.ToString(params.PolicyArn)
+	if !policies.Has(arn) {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	policies.Delete(arn)
+	return &iam.DetachUserPolicyOutput{}, nil
+}
+
+func (i *IamService) ListAttachedUserPolicies(
+	_ context.Context,
+	params *iam.ListAttachedUserPoliciesInput,
+	_ ...func(*iam.Options),
+) (*iam.ListAttachedUserPoliciesOutput, error) {
+	if params == nil {
+		params = &iam.ListAttachedUserPoliciesInput{}
+	}
+	name := aws.ToString(params.UserName)
+	if _, ok := i.Users.Load(name); !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	rv := &iam.ListAttachedUserPoliciesOutput{}
+	v, ok := i.UserAttachments.Load(name)
+	if !ok {
+		return rv, nil
+	}
+	attachments := i.attachedPolicies(v.(sets.String).List(), params.PathPrefix)
+	start, end, marker, truncated := page(len(attachments), params.Marker, params.MaxItems)
+	rv.AttachedPolicies = attachments[start:end]
+	rv.Marker = marker
+	rv.IsTruncated = truncated
+	return rv, nil
+}
+
+// accessKeys returns a copy of the access keys for a user in the
+// order they were created
+func (i *IamService) accessKeys(userName string) []iamtypes.AccessKeyMetadata {
+	v, ok := i.AccessKeys.Load(userName)
+	if !ok {
+		return nil
+	}
+	keys := v.([]iamtypes.AccessKeyMetadata)
+	return append(make([]iamtypes.AccessKeyMetadata, 0, len(keys)), keys...)
+}
+
+// CreateAccessKey creates an access key for a user. Like AWS, a user can
+// have at most MaxAccessKeys keys
+func (i *IamService) CreateAccessKey(
+	_ context.Context,
+	params *iam.CreateAccessKeyInput,
+	_ ...func(*iam.Options),
+) (*iam.CreateAccessKeyOutput, error) {
+	if params == nil {
+		params = &iam.CreateAccessKeyInput{}
+	}
+	name := aws.ToString(params.UserName)
+	if _, ok := i.Users.Load(name); !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	keys := i.accessKeys(name)
+	if len(keys) >= MaxAccessKeys {
+		return nil, &iamtypes.LimitExceededException{}
+	}
+	key := iamtypes.AccessKey{
+		AccessKeyId:     aws.String(randStringSuffix("AKIA")[:20]),
+		SecretAccessKey: aws.String(randStringSuffix("") + randStringSuffix("") + randStringSuffix("")[:6]),
+		CreateDate:      aws.Time(time.Now()),
+		Status:          iamtypes.StatusTypeActive,
+		UserName:        params.UserName,
+	}
+	keys = append(keys, iamtypes.AccessKeyMetadata{
+		AccessKeyId: key.AccessKeyId,
+		CreateDate:  key.CreateDate,
+		Status:      key.Status,
+		UserName:    key.UserName,
+	})
+	i.AccessKeys.Store(name, keys)
+	return &iam.CreateAccessKeyOutput{AccessKey: &key}, nil
+}
+
+func (i *IamService) ListAccessKeys(
+	_ context.Context,
+	params *iam.ListAccessKeysInput,
+	_ ...func(*iam.Options),
+) (*iam.ListAccessKeysOutput, error) {
+	if params == nil {
+		params = &iam.ListAccessKeysInput{}
+	}
+	name := aws.ToString(params.UserName)
+	if _, ok := i.Users.Load(name); !ok {
+		return nil, &iamtypes.NoSuchEntityException{}
+	}
+	return &iam.ListAccessKeysOutput{AccessKeyMetadata: i.accessKeys(name)}, nil
+}
+
+func (i *IamService) DeleteAccessKey(
+	_ context.Context,
+	params *iam.DeleteAccessKeyInput,
+	_ ...func(*iam.Options),
+) (*iam.DeleteAccessKeyOutput, error) {
+	if params == nil {
+		params = &iam.DeleteAccessKeyInput{}
+	}
+	name := aws.ToString(params.UserName)
+	keys := i.accessKeys(name)
+	for k, key := range keys {
+		if aws.ToString(key.AccessKeyId) == aws.ToString(params.AccessKeyId) {
+			i.AccessKeys.Store(name, append(keys[:k], keys[k+1:]...))
+			return &iam.DeleteAccessKeyOutput{}, nil
+		}
+	}
+	return nil, &iamtypes.NoSuchEntityException{}
+}
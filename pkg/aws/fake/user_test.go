@@ -0,0 +1,124 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/fake"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("IamUserService", func() {
+	var iamService = fake.NewIamService()
+	var inputCache = policies{}
+	BeforeEach(func() {
+		iamService.Reset()
+		inputCache.Reset()
+	})
+
+	It("should create a user", func() {
+		out, err := iamService.CreateUser(ctx, &iam.CreateUserInput{
+			UserName: aws.String("should-create-a-user"),
+			Path:     aws.String("/controller/"),
+		})
+		Expect(err).To(Succeed())
+		Expect(aws.ToString(out.User.Arn)).To(Equal(fmt.Sprintf("arn:aws:iam::%s:user/controller/should-create-a-user", iamService.AccountID)))
+
+		_, err = iamService.CreateUser(ctx, &iam.CreateUserInput{UserName: aws.String("should-create-a-user")})
+		var e *iamtypes.EntityAlreadyExistsException
+		Expect(errors.As(err, &e)).To(BeTrue())
+	})
+	It("should not delete a user with attached policies", func() {
+		_, err := iamService.CreateUser(ctx, &iam.CreateUserInput{UserName: aws.String("should-not-delete")})
+		Expect(err).To(Succeed())
+		policy, err := iamService.CreatePolicy(ctx, inputCache.Pop("AWSHealthFullAccess"))
+		Expect(err).To(Succeed())
+		_, err = iamService.AttachUserPolicy(ctx, &iam.AttachUserPolicyInput{
+			UserName:  aws.String("should-not-delete"),
+			PolicyArn: policy.Policy.Arn,
+		})
+		Expect(err).To(Succeed())
+
+		out, err := iamService.ListAttachedUserPolicies(ctx, &iam.ListAttachedUserPoliciesInput{
+			UserName: aws.String("should-not-delete"),
+		})
+		Expect(err).To(Succeed())
+		Expect(out.AttachedPolicies).To(HaveLen(1))
+		Expect(aws.ToString(out.AttachedPolicies[0].PolicyArn)).To(Equal(aws.ToString(policy.Policy.Arn)))
+
+		_, err = iamService.DeleteUser(ctx, &iam.DeleteUserInput{UserName: aws.String("should-not-delete")})
+		var e *iamtypes.DeleteConflictException
+		Expect(errors.As(err, &e)).To(BeTrue())
+
+		_, err = iamService.DetachUserPolicy(ctx, &iam.DetachUserPolicyInput{
+			UserName:  aws.String("should-not-delete"),
+			PolicyArn: policy.Policy.Arn,
+		})
+		Expect(err).To(Succeed())
+		_, err = iamService.DeleteUser(ctx, &iam.DeleteUserInput{UserName: aws.String("should-not-delete")})
+		Expect(err).To(Succeed())
+	})
+	It("should limit a user to two access keys", func() {
+		_, err := iamService.CreateUser(ctx, &iam.CreateUserInput{UserName: aws.String("should-limit-keys")})
+		Expect(err).To(Succeed())
+		first, err := iamService.CreateAccessKey(ctx, &iam.CreateAccessKeyInput{UserName: aws.String("should-limit-keys")})
+		Expect(err).To(Succeed())
+		Expect(aws.ToString(first.AccessKey.SecretAccessKey)).ToNot(BeEmpty())
+		_, err = iamService.CreateAccessKey(ctx, &iam.CreateAccessKeyInput{UserName: aws.String("should-limit-keys")})
+		Expect(err).To(Succeed())
+		_, err = iamService.CreateAccessKey(ctx, &iam.CreateAccessKeyInput{UserName: aws.String("should-limit-keys")})
+		var e *iamtypes.LimitExceededException
+		Expect(errors.As(err, &e)).To(BeTrue())
+
+		_, err = iamService.DeleteAccessKey(ctx, &iam.DeleteAccessKeyInput{
+			UserName:    aws.String("should-limit-keys"),
+			AccessKeyId: first.AccessKey.AccessKeyId,
+		})
+		Expect(err).To(Succeed())
+		out, err := iamService.ListAccessKeys(ctx, &iam.ListAccessKeysInput{UserName: aws.String("should-limit-keys")})
+		Expect(err).To(Succeed())
+		Expect(out.AccessKeyMetadata).To(HaveLen(1))
+		Expect(out.AccessKeyMetadata[0].AccessKeyId).ToNot(Equal(first.AccessKey.AccessKeyId))
+	})
+	It("should tag a user", func() {
+		_, err := iamService.CreateUser(ctx, &iam.CreateUserInput{UserName: aws.String("should-tag-a-user")})
+		Expect(err).To(Succeed())
+		_, err = iamService.TagUser(ctx, &iam.TagUserInput{
+			UserName: aws.String("should-tag-a-user"),
+			Tags:     []iamtypes.Tag{{Key: aws.String("team"), Value: aws.String("a")}},
+		})
+		Expect(err).To(Succeed())
+		out, err := iamService.GetUser(ctx, &iam.GetUserInput{UserName: aws.String("should-tag-a-user")})
+		Expect(err).To(Succeed())
+		Expect(out.User.Tags).To(HaveLen(1))
+		_, err = iamService.UntagUser(ctx, &iam.UntagUserInput{
+			UserName: aws.String("should-tag-a-user"),
+			TagKeys:  []string{"team"},
+		})
+		Expect(err).To(Succeed())
+		out, err = iamService.GetUser(ctx, &iam.GetUserInput{UserName: aws.String("should-tag-a-user")})
+		Expect(err).To(Succeed())
+		Expect(out.User.Tags).To(BeEmpty())
+	})
+})
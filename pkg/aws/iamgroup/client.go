@@ -0,0 +1,140 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iamgroup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+
+	pkgaws "github.com/johnhoman/aws-iam-controller/pkg/aws"
+)
+
+type Client struct {
+	service pkgaws.IamGroupService
+	path    string
+}
+
+func (c *Client) Create(ctx context.Context, options *CreateOptions) (*IamGroup, error) {
+	out, err := c.service.CreateGroup(ctx, &iam.CreateGroupInput{
+		GroupName: aws.String(options.Name),
+		Path:      aws.String(c.fullPath(options.Path)),
+	})
+	if err != nil {
+		return &IamGroup{}, err
+	}
+	return c.Get(ctx, &GetOptions{Name: aws.ToString(out.Group.GroupName)})
+}
+
+// fullPath appends path to the client path
+func (c *Client) fullPath(path string) string {
+	if len(path) == 0 {
+		return c.path
+	}
+	return c.path + path + "/"
+}
+
+// Get returns the group along with the names of its members
+func (c *Client) Get(ctx context.Context, options *GetOptions) (*IamGroup, error) {
+	in := &iam.GetGroupInput{GroupName: aws.String(options.Name)}
+	rv := &IamGroup{Members: make([]string, 0)}
+	for {
+		out, err := c.service.GetGroup(ctx, in)
+		if err != nil {
+			return &IamGroup{}, err
+		}
+		rv.Arn = aws.ToString(out.Group.Arn)
+		rv.CreateDate = aws.ToTime(out.Group.CreateDate)
+		rv.Id = aws.ToString(out.Group.GroupId)
+		rv.Name = aws.ToString(out.Group.GroupName)
+		rv.Path = aws.ToString(out.Group.Path)
+		for _, user := range out.Users {
+			rv.Members = append(rv.Members, aws.ToString(user.UserName))
+		}
+		if !out.IsTruncated {
+			return rv, nil
+		}
+		in.Marker = out.Marker
+	}
+}
+
+func (c *Client) Delete(ctx context.Context, options *DeleteOptions) error {
+	_, err := c.service.DeleteGroup(ctx, &iam.DeleteGroupInput{
+		GroupName: aws.String(options.Name),
+	})
+	return err
+}
+
+func (c *Client) AddUser(ctx context.Context, options *MemberOptions) error {
+	_, err := c.service.AddUserToGroup(ctx, &iam.AddUserToGroupInput{
+		GroupName: aws.String(options.Name),
+		UserName:  aws.String(options.UserName),
+	})
+	return err
+}
+
+func (c *Client) RemoveUser(ctx context.Context, options *MemberOptions) error {
+	_, err := c.service.RemoveUserFromGroup(ctx, &iam.RemoveUserFromGroupInput{
+		GroupName: aws.String(options.Name),
+		UserName:  aws.String(options.UserName),
+	})
+	return err
+}
+
+func (c *Client) AttachPolicy(ctx context.Context, options *AttachOptions) error {
+	_, err := c.service.AttachGroupPolicy(ctx, &iam.AttachGroupPolicyInput{
+		GroupName: aws.String(options.Name),
+		PolicyArn: aws.String(options.PolicyArn),
+	})
+	return err
+}
+
+func (c *Client) DetachPolicy(ctx context.Context, options *DetachOptions) error {
+	_, err := c.service.DetachGroupPolicy(ctx, &iam.DetachGroupPolicyInput{
+		GroupName: aws.String(options.Name),
+		PolicyArn: aws.String(options.PolicyArn),
+	})
+	return err
+}
+
+// ListAttachedPolicies returns the arns of the managed policies attached
+// to the group
+func (c *Client) ListAttachedPolicies(ctx context.Context, options *ListOptions) ([]string, error) {
+	in := &iam.ListAttachedGroupPoliciesInput{GroupName: aws.String(options.Name)}
+	arns := make([]string, 0)
+	for {
+		out, err := c.service.ListAttachedGroupPolicies(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+		for _, policy := range out.AttachedPolicies {
+			arns = append(arns, aws.ToString(policy.PolicyArn))
+		}
+		if !out.IsTruncated {
+			return arns, nil
+		}
+		in.Marker = out.Marker
+	}
+}
+
+var _ Interface = &Client{}
+
+func New(service pkgaws.IamGroupService, path string) *Client {
+	return &Client{service: service, path: fmt.Sprintf("/%s/", path)}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iamgroup_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/google/uuid"
+
+	pkgaws "github.com/johnhoman/aws-iam-controller/pkg/aws"
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/fake"
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/iamgroup"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client", func() {
+	var service *fake.IamService
+	var client iamgroup.Interface
+	var path string
+	var ctx context.Context
+	BeforeEach(func() {
+		ctx = context.Background()
+		path = "testspace-" + uuid.New().String()[:8]
+		service = fake.NewIamService()
+		client = iamgroup.New(service, path)
+	})
+	It("Should create a group under a sub path", func() {
+		group, err := client.Create(ctx, &iamgroup.CreateOptions{Name: "should-create-a-group", Path: "owner"})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(group.Path).To(Equal(fmt.Sprintf("/%s/owner/", path)))
+		Expect(group.Arn).To(HaveSuffix(fmt.Sprintf(":group/%s/owner/should-create-a-group", path)))
+	})
+	It("Should create a group under the client path", func() {
+		group, err := client.Create(ctx, &iamgroup.CreateOptions{Name: "should-create-a-group"})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(group.Id).ShouldNot(BeEmpty())
+		Expect(group.Arn).To(HaveSuffix(fmt.Sprintf(":group/%s/should-create-a-group", path)))
+		Expect(group.Members).To(BeEmpty())
+
+		Expect(client.Delete(ctx, &iamgroup.DeleteOptions{Name: group.Name})).To(Succeed())
+		_, err = client.Get(ctx, &iamgroup.GetOptions{Name: group.Name})
+		Expect(pkgaws.IsNotFound(err)).To(BeTrue())
+	})
+	It("Should add and remove members", func() {
+		group, err := client.Create(ctx, &iamgroup.CreateOptions{Name: "should-add-members"})
+		Expect(err).ShouldNot(HaveOccurred())
+		_, err = service.CreateUser(ctx, &iam.CreateUserInput{UserName: aws.String("member")})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect(client.AddUser(ctx, &iamgroup.MemberOptions{Name: group.Name, UserName: "member"})).To(Succeed())
+		group, err = client.Get(ctx, &iamgroup.GetOptions{Name: group.Name})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(group.Members).To(ConsistOf("member"))
+
+		Expect(client.RemoveUser(ctx, &iamgroup.MemberOptions{Name: group.Name, UserName: "member"})).To(Succeed())
+		group, err = client.Get(ctx, &iamgroup.GetOptions{Name: group.Name})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(group.Members).To(BeEmpty())
+	})
+	It("Should attach and detach policies", func() {
+		group, err := client.Create(ctx, &iamgroup.CreateOptions{Name: "should-attach-policies"})
+		Expect(err).ShouldNot(HaveOccurred())
+		out, err := service.CreatePolicy(ctx, &iam.CreatePolicyInput{
+			PolicyName:     aws.String("should-attach-policies"),
+			PolicyDocument: aws.String(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`),
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		arn := aws.ToString(out.Policy.Arn)
+
+		Expect(client.AttachPolicy(ctx, &iamgroup.AttachOptions{Name: group.Name, PolicyArn: arn})).To(Succeed())
+		attached, err := client.ListAttachedPolicies(ctx, &iamgroup.ListOptions{Name: group.Name})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(attached).To(ConsistOf(arn))
+
+		Expect(client.DetachPolicy(ctx, &iamgroup.DetachOptions{Name: group.Name, PolicyArn: arn})).To(Succeed())
+		attached, err = client.ListAttachedPolicies(ctx, &iamgroup.ListOptions{Name: group.Name})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(attached).To(BeEmpty())
+	})
+})
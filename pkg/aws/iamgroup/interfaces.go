@@ -0,0 +1,30 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iamgroup
+
+import "context"
+
+type Interface interface {
+	Create(ctx context.Context, options *CreateOptions) (*IamGroup, error)
+	Get(ctx context.Context, options *GetOptions) (*IamGroup, error)
+	Delete(ctx context.Context, options *DeleteOptions) error
+	AddUser(ctx context.Context, options *MemberOptions) error
+	RemoveUser(ctx context.Context, options *MemberOptions) error
+	AttachPolicy(ctx context.Context, options *AttachOptions) error
+	DetachPolicy(ctx context.Context, options *DetachOptions) error
+	ListAttachedPolicies(ctx context.Context, options *ListOptions) ([]string, error)
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iamgroup
+
+import (
+	"time"
+)
+
+type CreateOptions struct {
+	Name string
+	// Path is appended to the client path
+	Path string
+}
+
+type GetOptions struct {
+	Name string
+}
+
+type DeleteOptions = GetOptions
+
+type ListOptions = GetOptions
+
+type MemberOptions struct {
+	Name     string
+	UserName string
+}
+
+type AttachOptions struct {
+	Name      string
+	PolicyArn string
+}
+
+type DetachOptions = AttachOptions
+
+type IamGroup struct {
+	Arn        string
+	CreateDate time.Time
+	Id         string
+	Name       string
+	Path       string
+	// Members are the names of the users in the group
+	Members []string
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iaminstanceprofile
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+
+	pkgaws "github.com/johnhoman/aws-iam-controller/pkg/aws"
+)
+
+type Client struct {
+	service pkgaws.IamInstanceProfileService
+	path    string
+}
+
+func (c *Client) Create(ctx context.Context, options *CreateOptions) (*IamInstanceProfile, error) {
+	out, err := c.service.CreateInstanceProfile(ctx, &iam.CreateInstanceProfileInput{
+		InstanceProfileName: aws.String(options.Name),
+		Path:                aws.String(c.path),
+		Tags:                pkgaws.NewTags(options.Tags),
+	})
+	if err != nil {
+		return &IamInstanceProfile{}, err
+	}
+	return c.Get(ctx, &GetOptions{Name: aws.ToString(out.InstanceProfile.InstanceProfileName)})
+}
+
+// Get returns the instance profile along with the names of its roles
+func (c *Client) Get(ctx context.Context, options *GetOptions) (*IamInstanceProfile, error) {
+	out, err := c.service.GetInstanceProfile(ctx, &iam.GetInstanceProfileInput{
+		InstanceProfileName: aws.String(options.Name),
+	})
+	if err != nil {
+		return &IamInstanceProfile{}, err
+	}
+	roles := make([]string, 0, len(out.InstanceProfile.Roles))
+	for _, role := range out.InstanceProfile.Roles {
+		roles = append(roles, aws.ToString(role.RoleName))
+	}
+	return &IamInstanceProfile{
+		Arn:        aws.ToString(out.InstanceProfile.Arn),
+		CreateDate: aws.ToTime(out.InstanceProfile.CreateDate),
+		Id:         aws.ToString(out.InstanceProfile.InstanceProfileId),
+		Name:       aws.ToString(out.InstanceProfile.InstanceProfileName),
+		Roles:      roles,
+		Tags:       pkgaws.TagMap(out.InstanceProfile.Tags),
+	}, nil
+}
+
+func (c *Client) Delete(ctx context.Context, options *DeleteOptions) error {
+	_, err := c.service.DeleteInstanceProfile(ctx, &iam.DeleteInstanceProfileInput{
+		InstanceProfileName: aws.String(options.Name),
+	})
+	return err
+}
+
+func (c *Client) Tag(ctx context.Context, options *TagOptions) error {
+	_, err := c.service.TagInstanceProfile(ctx, &iam.TagInstanceProfileInput{
+		InstanceProfileName: aws.String(options.Name),
+		Tags:                pkgaws.NewTags(options.Tags),
+	})
+	return err
+}
+
+func (c *Client) Untag(ctx context.Context, options *UntagOptions) error {
+	_, err := c.service.UntagInstanceProfile(ctx, &iam.UntagInstanceProfileInput{
+		InstanceProfileName: aws.String(options.Name),
+		TagKeys:             options.Keys,
+	})
+	return err
+}
+
+func (c *Client) AddRole(ctx context.Context, options *RoleOptions) error {
+	_, err := c.service.AddRoleToInstanceProfile(ctx, &iam.AddRoleToInstanceProfileInput{
+		InstanceProfileName: aws.String(options.Name),
+		RoleName:            aws.String(options.RoleName),
+	})
+	return err
+}
+
+func (c *Client) RemoveRole(ctx context.Context, options *RoleOptions) error {
+	_, err := c.service.RemoveRoleFromInstanceProfile(ctx, &iam.RemoveRoleFromInstanceProfileInput{
+		InstanceProfileName: aws.String(options.Name),
+		RoleName:            aws.String(options.RoleName),
+	})
+	return err
+}
+
+var _ Interface = &Client{}
+
+func New(service pkgaws.IamInstanceProfileService, path string) *Client {
+	return &Client{service: service, path: fmt.Sprintf("/%s/", path)}
+}
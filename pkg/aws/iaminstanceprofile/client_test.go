@@ -0,0 +1,85 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iaminstanceprofile_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/google/uuid"
+
+	pkgaws "github.com/johnhoman/aws-iam-controller/pkg/aws"
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/fake"
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/iaminstanceprofile"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client", func() {
+	var service *fake.IamService
+	var client iaminstanceprofile.Interface
+	var path string
+	var ctx context.Context
+	BeforeEach(func() {
+		ctx = context.Background()
+		path = "testspace-" + uuid.New().String()[:8]
+		service = fake.NewIamService()
+		client = iaminstanceprofile.New(service, path)
+	})
+	It("Should create an instance profile under the client path", func() {
+		profile, err := client.Create(ctx, &iaminstanceprofile.CreateOptions{
+			Name: "should-create-a-profile",
+			Tags: map[string]string{"team": "platform"},
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(profile.Id).ShouldNot(BeEmpty())
+		Expect(profile.Arn).To(HaveSuffix(fmt.Sprintf(":instance-profile/%s/should-create-a-profile", path)))
+		Expect(profile.Roles).To(BeEmpty())
+		Expect(profile.Tags).To(Equal(map[string]string{"team": "platform"}))
+
+		Expect(client.Untag(ctx, &iaminstanceprofile.UntagOptions{Name: profile.Name, Keys: []string{"team"}})).To(Succeed())
+		profile, err = client.Get(ctx, &iaminstanceprofile.GetOptions{Name: profile.Name})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(profile.Tags).To(BeEmpty())
+
+		Expect(client.Delete(ctx, &iaminstanceprofile.DeleteOptions{Name: profile.Name})).To(Succeed())
+		_, err = client.Get(ctx, &iaminstanceprofile.GetOptions{Name: profile.Name})
+		Expect(pkgaws.IsNotFound(err)).To(BeTrue())
+	})
+	It("Should add and remove the role", func() {
+		profile, err := client.Create(ctx, &iaminstanceprofile.CreateOptions{Name: "should-add-a-role"})
+		Expect(err).ShouldNot(HaveOccurred())
+		_, err = service.CreateRole(ctx, &iam.CreateRoleInput{
+			RoleName:                 aws.String("node"),
+			AssumeRolePolicyDocument: aws.String(`{"Version":"2012-10-17","Statement":[]}`),
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect(client.AddRole(ctx, &iaminstanceprofile.RoleOptions{Name: profile.Name, RoleName: "node"})).To(Succeed())
+		profile, err = client.Get(ctx, &iaminstanceprofile.GetOptions{Name: profile.Name})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(profile.Roles).To(ConsistOf("node"))
+
+		Expect(client.RemoveRole(ctx, &iaminstanceprofile.RoleOptions{Name: profile.Name, RoleName: "node"})).To(Succeed())
+		profile, err = client.Get(ctx, &iaminstanceprofile.GetOptions{Name: profile.Name})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(profile.Roles).To(BeEmpty())
+	})
+})
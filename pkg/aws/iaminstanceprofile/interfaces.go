@@ -0,0 +1,29 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iaminstanceprofile
+
+import "context"
+
+type Interface interface {
+	Create(ctx context.Context, options *CreateOptions) (*IamInstanceProfile, error)
+	Get(ctx context.Context, options *GetOptions) (*IamInstanceProfile, error)
+	Delete(ctx context.Context, options *DeleteOptions) error
+	Tag(ctx context.Context, options *TagOptions) error
+	Untag(ctx context.Context, options *UntagOptions) error
+	AddRole(ctx context.Context, options *RoleOptions) error
+	RemoveRole(ctx context.Context, options *RoleOptions) error
+}
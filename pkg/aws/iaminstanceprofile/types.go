@@ -0,0 +1,58 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iaminstanceprofile
+
+import (
+	"time"
+)
+
+type CreateOptions struct {
+	Name string
+	Tags map[string]string
+}
+
+type GetOptions struct {
+	Name string
+}
+
+type DeleteOptions = GetOptions
+
+type TagOptions struct {
+	Name string
+	Tags map[string]string
+}
+
+type UntagOptions struct {
+	Name string
+	Keys []string
+}
+
+type RoleOptions struct {
+	Name     string
+	RoleName string
+}
+
+type IamInstanceProfile struct {
+	Arn        string
+	CreateDate time.Time
+	Id         string
+	Name       string
+	// Roles are the names of the roles in the instance profile. An instance
+	// profile has at most one role
+	Roles []string
+	Tags  map[string]string
+}
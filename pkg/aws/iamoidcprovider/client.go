@@ -0,0 +1,165 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iamoidcprovider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+
+	pkgaws "github.com/johnhoman/aws-iam-controller/pkg/aws"
+)
+
+const resourcePrefix = "oidc-provider/"
+
+type Client struct {
+	service pkgaws.IamOIDCProviderService
+}
+
+func (c *Client) Create(ctx context.Context, options *CreateOptions) (*IamOIDCProvider, error) {
+	out, err := c.service.CreateOpenIDConnectProvider(ctx, &iam.CreateOpenIDConnectProviderInput{
+		Url:            aws.String(options.URL),
+		ClientIDList:   options.ClientIDs,
+		ThumbprintList: options.Thumbprints,
+		Tags:           pkgaws.NewTags(options.Tags),
+	})
+	if err != nil {
+		return &IamOIDCProvider{}, err
+	}
+	return c.Get(ctx, &GetOptions{Arn: aws.ToString(out.OpenIDConnectProviderArn)})
+}
+
+func (c *Client) Get(ctx context.Context, options *GetOptions) (*IamOIDCProvider, error) {
+	out, err := c.service.GetOpenIDConnectProvider(ctx, &iam.GetOpenIDConnectProviderInput{
+		OpenIDConnectProviderArn: aws.String(options.Arn),
+	})
+	if err != nil {
+		return &IamOIDCProvider{}, err
+	}
+	return &IamOIDCProvider{
+		Arn:         options.Arn,
+		CreateDate:  aws.ToTime(out.CreateDate),
+		URL:         TrimScheme(aws.ToString(out.Url)),
+		ClientIDs:   out.ClientIDList,
+		Thumbprints: out.ThumbprintList,
+		Tags:        pkgaws.TagMap(out.Tags),
+	}, nil
+}
+
+// Find returns the provider for the issuer url. Provider arns end with the
+// url, so the providers don't have to be fetched one at a time
+func (c *Client) Find(ctx context.Context, options *FindOptions) (*IamOIDCProvider, error) {
+	out, err := c.service.ListOpenIDConnectProviders(ctx, &iam.ListOpenIDConnectProvidersInput{})
+	if err != nil {
+		return &IamOIDCProvider{}, err
+	}
+	suffix := ":" + resourcePrefix + TrimScheme(options.URL)
+	for _, entry := range out.OpenIDConnectProviderList {
+		if strings.HasSuffix(aws.ToString(entry.Arn), suffix) {
+			return c.Get(ctx, &GetOptions{Arn: aws.ToString(entry.Arn)})
+		}
+	}
+	return &IamOIDCProvider{}, &iamtypes.NoSuchEntityException{}
+}
+
+func (c *Client) Delete(ctx context.Context, options *DeleteOptions) error {
+	_, err := c.service.DeleteOpenIDConnectProvider(ctx, &iam.DeleteOpenIDConnectProviderInput{
+		OpenIDConnectProviderArn: aws.String(options.Arn),
+	})
+	return err
+}
+
+func (c *Client) AddClientID(ctx context.Context, options *ClientIDOptions) error {
+	_, err := c.service.AddClientIDToOpenIDConnectProvider(ctx, &iam.AddClientIDToOpenIDConnectProviderInput{
+		OpenIDConnectProviderArn: aws.String(options.Arn),
+		ClientID:                 aws.String(options.ClientID),
+	})
+	return err
+}
+
+func (c *Client) RemoveClientID(ctx context.Context, options *ClientIDOptions) error {
+	_, err := c.service.RemoveClientIDFromOpenIDConnectProvider(ctx, &iam.RemoveClientIDFromOpenIDConnectProviderInput{
+		OpenIDConnectProviderArn: aws.String(options.Arn),
+		ClientID:                 aws.String(options.ClientID),
+	})
+	return err
+}
+
+func (c *Client) UpdateThumbprints(ctx context.Context, options *UpdateThumbprintsOptions) error {
+	_, err := c.service.UpdateOpenIDConnectProviderThumbprint(ctx, &iam.UpdateOpenIDConnectProviderThumbprintInput{
+		OpenIDConnectProviderArn: aws.String(options.Arn),
+		ThumbprintList:           options.Thumbprints,
+	})
+	return err
+}
+
+func (c *Client) Tag(ctx context.Context, options *TagOptions) error {
+	_, err := c.service.TagOpenIDConnectProvider(ctx, &iam.TagOpenIDConnectProviderInput{
+		OpenIDConnectProviderArn: aws.String(options.Arn),
+		Tags:                     pkgaws.NewTags(options.Tags),
+	})
+	return err
+}
+
+func (c *Client) Untag(ctx context.Context, options *UntagOptions) error {
+	_, err := c.service.UntagOpenIDConnectProvider(ctx, &iam.UntagOpenIDConnectProviderInput{
+		OpenIDConnectProviderArn: aws.String(options.Arn),
+		TagKeys:                  options.Keys,
+	})
+	return err
+}
+
+// TrimScheme returns the issuer url without the https scheme, which is how
+// IAM stores it
+func TrimScheme(url string) string {
+	return strings.TrimPrefix(url, "https://")
+}
+
+// ProviderArn returns the arn IAM gives the provider for the issuer url
+func ProviderArn(partition, accountID, url string) string {
+	return arn.ARN{
+		Partition: partition,
+		Service:   "iam",
+		AccountID: accountID,
+		Resource:  resourcePrefix + TrimScheme(url),
+	}.String()
+}
+
+// URLFromArn returns the issuer url of a provider arn without the scheme
+func URLFromArn(providerArn string) (string, error) {
+	parsed, err := arn.Parse(providerArn)
+	if err != nil {
+		return "", err
+	}
+	if parsed.Service != "iam" || !strings.HasPrefix(parsed.Resource, resourcePrefix) {
+		return "", fmt.Errorf("%s is not an iam oidc provider arn", providerArn)
+	}
+	return strings.TrimPrefix(parsed.Resource, resourcePrefix), nil
+}
+
+var _ Interface = &Client{}
+
+// New returns a client for oidc providers. Providers don't have a path, so
+// unlike the other clients there's no path
+func New(service pkgaws.IamOIDCProviderService) *Client {
+	return &Client{service: service}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iamoidcprovider_test
+
+import (
+	"context"
+
+	pkgaws "github.com/johnhoman/aws-iam-controller/pkg/aws"
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/fake"
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/iamoidcprovider"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client", func() {
+	var client iamoidcprovider.Interface
+	var ctx context.Context
+	BeforeEach(func() {
+		ctx = context.Background()
+		client = iamoidcprovider.New(fake.NewIamService())
+	})
+	It("Should create and find a provider", func() {
+		provider, err := client.Create(ctx, &iamoidcprovider.CreateOptions{
+			URL:       "https://oidc.eks.us-east-1.amazonaws.com/id/EXAMPLE",
+			ClientIDs: []string{"sts.amazonaws.com"},
+			Tags:      map[string]string{"team": "platform"},
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(provider.Arn).To(HaveSuffix(":oidc-provider/oidc.eks.us-east-1.amazonaws.com/id/EXAMPLE"))
+		Expect(provider.URL).To(Equal("oidc.eks.us-east-1.amazonaws.com/id/EXAMPLE"))
+		Expect(provider.ClientIDs).To(ConsistOf("sts.amazonaws.com"))
+		Expect(provider.Tags).To(Equal(map[string]string{"team": "platform"}))
+
+		found, err := client.Find(ctx, &iamoidcprovider.FindOptions{URL: "https://oidc.eks.us-east-1.amazonaws.com/id/EXAMPLE"})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(found.Arn).To(Equal(provider.Arn))
+
+		_, err = client.Find(ctx, &iamoidcprovider.FindOptions{URL: "https://oidc.eks.us-east-1.amazonaws.com/id/OTHER"})
+		Expect(pkgaws.IsNotFound(err)).To(BeTrue())
+
+		Expect(client.Delete(ctx, &iamoidcprovider.DeleteOptions{Arn: provider.Arn})).To(Succeed())
+		_, err = client.Get(ctx, &iamoidcprovider.GetOptions{Arn: provider.Arn})
+		Expect(pkgaws.IsNotFound(err)).To(BeTrue())
+	})
+	It("Should update the client ids and thumbprints", func() {
+		provider, err := client.Create(ctx, &iamoidcprovider.CreateOptions{
+			URL:       "https://oidc.eks.us-east-1.amazonaws.com/id/EXAMPLE",
+			ClientIDs: []string{"sts.amazonaws.com"},
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(client.AddClientID(ctx, &iamoidcprovider.ClientIDOptions{Arn: provider.Arn, ClientID: "vault"})).To(Succeed())
+		Expect(client.RemoveClientID(ctx, &iamoidcprovider.ClientIDOptions{Arn: provider.Arn, ClientID: "sts.amazonaws.com"})).To(Succeed())
+		Expect(client.UpdateThumbprints(ctx, &iamoidcprovider.UpdateThumbprintsOptions{
+			Arn:         provider.Arn,
+			Thumbprints: []string{"9e99a48a9960b14926bb7f3b02e22da2b0ab7280"},
+		})).To(Succeed())
+
+		provider, err = client.Get(ctx, &iamoidcprovider.GetOptions{Arn: provider.Arn})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(provider.ClientIDs).To(ConsistOf("vault"))
+		Expect(provider.Thumbprints).To(ConsistOf("9e99a48a9960b14926bb7f3b02e22da2b0ab7280"))
+	})
+	It("Should convert between provider arns and urls", func() {
+		providerArn := iamoidcprovider.ProviderArn("aws", "111122223333", "https://oidc.eks.us-east-1.amazonaws.com/id/EXAMPLE")
+		Expect(providerArn).To(Equal("arn:aws:iam::111122223333:oidc-provider/oidc.eks.us-east-1.amazonaws.com/id/EXAMPLE"))
+		url, err := iamoidcprovider.URLFromArn(providerArn)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(url).To(Equal("oidc.eks.us-east-1.amazonaws.com/id/EXAMPLE"))
+
+		_, err = iamoidcprovider.URLFromArn("arn:aws:iam::111122223333:role/EXAMPLE")
+		Expect(err).Should(HaveOccurred())
+	})
+})
@@ -0,0 +1,31 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iamoidcprovider
+
+import "context"
+
+type Interface interface {
+	Create(ctx context.Context, options *CreateOptions) (*IamOIDCProvider, error)
+	Get(ctx context.Context, options *GetOptions) (*IamOIDCProvider, error)
+	Find(ctx context.Context, options *FindOptions) (*IamOIDCProvider, error)
+	Delete(ctx context.Context, options *DeleteOptions) error
+	AddClientID(ctx context.Context, options *ClientIDOptions) error
+	RemoveClientID(ctx context.Context, options *ClientIDOptions) error
+	UpdateThumbprints(ctx context.Context, options *UpdateThumbprintsOptions) error
+	Tag(ctx context.Context, options *TagOptions) error
+	Untag(ctx context.Context, options *UntagOptions) error
+}
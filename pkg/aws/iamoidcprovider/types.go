@@ -0,0 +1,69 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iamoidcprovider
+
+import (
+	"time"
+)
+
+type CreateOptions struct {
+	// URL is the issuer url. It has to use the https scheme
+	URL         string
+	ClientIDs   []string
+	Thumbprints []string
+	Tags        map[string]string
+}
+
+type GetOptions struct {
+	Arn string
+}
+
+type DeleteOptions = GetOptions
+
+type FindOptions struct {
+	URL string
+}
+
+type ClientIDOptions struct {
+	Arn      string
+	ClientID string
+}
+
+type UpdateThumbprintsOptions struct {
+	Arn         string
+	Thumbprints []string
+}
+
+type TagOptions struct {
+	Arn  string
+	Tags map[string]string
+}
+
+type UntagOptions struct {
+	Arn  string
+	Keys []string
+}
+
+type IamOIDCProvider struct {
+	Arn        string
+	CreateDate time.Time
+	// URL is the issuer url without the https scheme
+	URL         string
+	ClientIDs   []string
+	Thumbprints []string
+	Tags        map[string]string
+}
@@ -18,9 +18,12 @@ package iampolicy
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/url"
 	"reflect"
+	"sort"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
@@ -30,7 +33,11 @@ import (
 	"k8s.io/apimachinery/pkg/util/json"
 )
 
-const DefaultCacheSize = 128
+const (
+	DefaultCacheSize = 128
+	// MaxVersions is the number of versions AWS keeps for a managed policy
+	MaxVersions = 5
+)
 
 type Client struct {
 	service   pkgaws.IamPolicyService
@@ -43,76 +50,176 @@ func (c *Client) Create(ctx context.Context, options *CreateOptions) (*IamPolicy
 		PolicyDocument: aws.String(options.Document),
 		PolicyName:     aws.String(options.Name),
 		Description:    aws.String(options.Description),
-		Path:           aws.String(c.path),
+		Path:           aws.String(c.fullPath(options.Path)),
+		Tags:           pkgaws.NewTags(options.Tags),
 	})
 	if err != nil {
+		// The policy was created by someone else after a miss was cached
+		c.nameCache.Remove(options.Name)
 		return nil, err
 	}
+	c.nameCache.Add(options.Name, aws.ToString(out.Policy.Arn))
 	return c.Get(ctx, &GetOptions{Arn: aws.ToString(out.Policy.Arn)})
 }
 
+// fullPath appends path to the client path
+func (c *Client) fullPath(path string) string {
+	if len(path) == 0 {
+		return c.path
+	}
+	return c.path + path + "/"
+}
+
 func (c *Client) Update(ctx context.Context, options *UpdateOptions) (*IamPolicy, error) {
 	policy, err := c.Get(ctx, &GetOptions{Arn: options.Arn})
 	if err != nil {
 		return nil, err
 	}
-	old := map[string]interface{}{}
-	updated := map[string]interface{}{}
-
-	if err := json.Unmarshal([]byte(policy.Document), &old); err != nil {
-		return nil, err
-	}
-
-	if err := json.Unmarshal([]byte(options.Document), &updated); err != nil {
+	equal, err := documentsEqual(policy.Document, options.Document)
+	if err != nil {
 		return nil, err
 	}
-	if reflect.DeepEqual(old, updated) {
+	if equal {
 		// if they're the same then do nothing
 		return policy, nil
 	}
 
-	_, err = c.service.CreatePolicyVersion(ctx, &iam.CreatePolicyVersionInput{
+	input := &iam.CreatePolicyVersionInput{
 		PolicyArn:      aws.String(policy.Arn),
 		PolicyDocument: aws.String(options.Document),
 		SetAsDefault:   true,
-	})
+	}
+	_, err = c.service.CreatePolicyVersion(ctx, input)
+	limitExceeded := &iamtypes.LimitExceededException{}
+	if errors.As(err, &limitExceeded) {
+		// Versions created outside the client can use up the versions
+		// AWS allows, so make room for the new one
+		if err := c.pruneVersions(ctx, policy.Arn, MaxVersions-1); err != nil {
+			return nil, err
+		}
+		_, err = c.service.CreatePolicyVersion(ctx, input)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	// If this fails it would require manual intervention
-	// this version id isn't tracked once a new version is created so
-	// the caller will have to
-	_, err = c.service.DeletePolicyVersion(ctx, &iam.DeletePolicyVersionInput{
-		PolicyArn: aws.String(policy.Arn),
-		VersionId: aws.String(policy.VersionId),
+	// Versions that fail to be deleted are pruned on the next update
+	if err := c.pruneVersions(ctx, policy.Arn, options.VersionLimit); err != nil {
+		return nil, err
+	}
+
+	return c.Get(ctx, &GetOptions{Arn: policy.Arn})
+}
+
+// pruneVersions deletes the oldest versions that aren't the default version
+// until at most limit versions are left
+func (c *Client) pruneVersions(ctx context.Context, arn string, limit int) error {
+	if limit < 1 {
+		limit = 1
+	}
+	versions, err := c.ListVersions(ctx, &ListVersionsOptions{Arn: arn})
+	if err != nil {
+		return err
+	}
+	// Versions are listed newest first
+	for k := len(versions) - 1; k >= 0 && len(versions) > limit; k-- {
+		if versions[k].IsDefault {
+			continue
+		}
+		if _, err := c.service.DeletePolicyVersion(ctx, &iam.DeletePolicyVersionInput{
+			PolicyArn: aws.String(arn),
+			VersionId: aws.String(versions[k].VersionId),
+		}); err != nil {
+			return err
+		}
+		versions = append(versions[:k], versions[k+1:]...)
+	}
+	return nil
+}
+
+// ListVersions returns the versions of the policy, newest first
+func (c *Client) ListVersions(ctx context.Context, options *ListVersionsOptions) ([]PolicyVersion, error) {
+	out, err := c.service.ListPolicyVersions(ctx, &iam.ListPolicyVersionsInput{
+		PolicyArn: aws.String(options.Arn),
 	})
 	if err != nil {
 		return nil, err
 	}
+	versions := make([]PolicyVersion, 0, len(out.Versions))
+	for _, version := range out.Versions {
+		versions = append(versions, PolicyVersion{
+			VersionId:  aws.ToString(version.VersionId),
+			CreateDate: aws.ToTime(version.CreateDate),
+			IsDefault:  version.IsDefaultVersion,
+		})
+	}
+	sort.SliceStable(versions, func(a, b int) bool {
+		return versions[a].CreateDate.After(versions[b].CreateDate)
+	})
+	return versions, nil
+}
 
-	return c.Get(ctx, &GetOptions{Arn: policy.Arn})
+// SetDefaultVersion makes an existing version the default version of the
+// policy, e.g. to roll back an update
+func (c *Client) SetDefaultVersion(ctx context.Context, options *SetDefaultVersionOptions) error {
+	if _, err := c.service.SetDefaultPolicyVersion(ctx, &iam.SetDefaultPolicyVersionInput{
+		PolicyArn: aws.String(options.Arn),
+		VersionId: aws.String(options.VersionId),
+	}); err != nil {
+		return err
+	}
+	return nil
 }
 
+// documentsEqual compares two policy documents. Documents that can't be
+// parsed into a Document are compared as raw json. The version isn't
+// defaulted, since a document without one is evaluated differently
+func documentsEqual(a, b string) (bool, error) {
+	docA, errA := parseDocument(a)
+	docB, errB := parseDocument(b)
+	if errA == nil && errB == nil {
+		return docA.Equals(docB)
+	}
+	old := map[string]interface{}{}
+	updated := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(a), &old); err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal([]byte(b), &updated); err != nil {
+		return false, err
+	}
+	return reflect.DeepEqual(old, updated), nil
+}
+
+// findArn returns the arn of the policy with the name under the client
+// path, or an empty string if there isn't one. Every policy listed is
+// cached, including misses, so each page is only listed once. Create and
+// Delete keep the cache in sync
 func (c *Client) findArn(ctx context.Context, options *GetOptions) (string, error) {
 	arn, ok := c.nameCache.Get(options.Name)
 	if ok {
 		return arn.(string), nil
 	}
-	out, err := c.service.ListPolicies(ctx, &iam.ListPoliciesInput{
+	found := ""
+	paginator := iam.NewListPoliciesPaginator(c.service, &iam.ListPoliciesInput{
 		PathPrefix: aws.String(c.path),
 	})
-	if err != nil {
-		return "", err
-	}
-	for _, policy := range out.Policies {
-		if aws.ToString(policy.PolicyName) == options.Name {
-			arn := aws.ToString(policy.Arn)
-			c.nameCache.Add(options.Name, arn)
-			return arn, nil
+	for paginator.HasMorePages() {
+		out, err := paginator.NextPage(ctx)
+		if err != nil {
+			return "", err
 		}
+		for _, policy := range out.Policies {
+			c.nameCache.Add(aws.ToString(policy.PolicyName), aws.ToString(policy.Arn))
+			if aws.ToString(policy.PolicyName) == options.Name {
+				found = aws.ToString(policy.Arn)
+			}
+		}
+	}
+	if len(found) == 0 {
+		c.nameCache.Add(options.Name, "")
 	}
-	return "", nil
+	return found, nil
 }
 
 func (c *Client) Get(ctx context.Context, options *GetOptions) (*IamPolicy, error) {
@@ -158,6 +265,7 @@ func (c *Client) Get(ctx context.Context, options *GetOptions) (*IamPolicy, erro
 	iamPolicy.Id = aws.ToString(out.Policy.PolicyId)
 	iamPolicy.VersionId = aws.ToString(out.Policy.DefaultVersionId)
 	iamPolicy.Document = document
+	iamPolicy.Tags = pkgaws.TagMap(out.Policy.Tags)
 
 	return iamPolicy, nil
 }
@@ -167,6 +275,27 @@ func (c *Client) Delete(ctx context.Context, options *DeleteOptions) error {
 	if err != nil {
 		return err
 	}
+	c.nameCache.Remove(options.Arn[strings.LastIndex(options.Arn, "/")+1:])
+	return nil
+}
+
+func (c *Client) Tag(ctx context.Context, options *TagOptions) error {
+	if _, err := c.service.TagPolicy(ctx, &iam.TagPolicyInput{
+		PolicyArn: aws.String(options.Arn),
+		Tags:      pkgaws.NewTags(options.Tags),
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *Client) Untag(ctx context.Context, options *UntagOptions) error {
+	if _, err := c.service.UntagPolicy(ctx, &iam.UntagPolicyInput{
+		PolicyArn: aws.String(options.Arn),
+		TagKeys:   options.Keys,
+	}); err != nil {
+		return err
+	}
 	return nil
 }
 
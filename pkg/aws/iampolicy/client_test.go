@@ -17,6 +17,10 @@ limitations under the License.
 package iampolicy_test
 
 import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
 	pkgaws "github.com/johnhoman/aws-iam-controller/pkg/aws"
 	"github.com/johnhoman/aws-iam-controller/pkg/aws/fake"
 	"github.com/johnhoman/aws-iam-controller/pkg/aws/iampolicy"
@@ -41,6 +45,19 @@ var _ = Describe("Client", func() {
 		Expect(err).ShouldNot(HaveOccurred())
 		Expect(out).ShouldNot(BeNil())
 	})
+	It("should create an iam policy under a sub path", func() {
+		out, err := client.Create(ctx, &iampolicy.CreateOptions{
+			Name:     "iam-policy",
+			Document: `{"Version": "2012-10-17", "Statement": [{"Sid": "S3FullAccess"}]}`,
+			Path:     "team-a",
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(out.Arn).Should(HaveSuffix(":policy/controller-test/team-a/iam-policy"))
+
+		got, err := client.Get(ctx, &iampolicy.GetOptions{Name: "iam-policy"})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(got.Arn).Should(Equal(out.Arn))
+	})
 	When("the policy exists", func() {
 		var p *iampolicy.IamPolicy
 		BeforeEach(func() {
@@ -105,5 +122,155 @@ var _ = Describe("Client", func() {
 			Expect(out.Document).Should(Equal(doc))
 			Expect(out.VersionId).Should(Equal(p.VersionId))
 		})
+		It("should update the policy document if only the version changed", func() {
+			doc := `{"Version": "2008-10-17", "Statement": [{"Sid": "S3FullAccess"}]}`
+			_, err := client.Update(ctx, &iampolicy.UpdateOptions{
+				Arn:      p.Arn,
+				Document: doc,
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			out, err := client.Get(ctx, &iampolicy.GetOptions{Arn: p.Arn})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(out.Document).Should(Equal(doc))
+			Expect(out.VersionId).ShouldNot(Equal(p.VersionId))
+		})
+		It("should not update the policy document if only condition value types changed", func() {
+			doc := `{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Action":"s3:*","Resource":"*","Condition":{"Bool":{"aws:SecureTransport":false}}}]}`
+			updated, err := client.Update(ctx, &iampolicy.UpdateOptions{Arn: p.Arn, Document: doc})
+			Expect(err).ShouldNot(HaveOccurred())
+			_, err = client.Update(ctx, &iampolicy.UpdateOptions{
+				Arn:      updated.Arn,
+				Document: `{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Action":["s3:*"],"Resource":["*"],"Condition":{"Bool":{"aws:SecureTransport":["false"]}}}]}`,
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			out, err := client.Get(ctx, &iampolicy.GetOptions{Arn: updated.Arn})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(out.Document).Should(Equal(doc))
+			Expect(out.VersionId).Should(Equal(updated.VersionId))
+		})
+		It("should only keep the default version without a version limit", func() {
+			_, err := client.Update(ctx, &iampolicy.UpdateOptions{
+				Arn:      p.Arn,
+				Document: `{"Version": "2012-10-17", "Statement": [{"Sid": "S3NoAccess"}]}`,
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			versions, err := client.ListVersions(ctx, &iampolicy.ListVersionsOptions{Arn: p.Arn})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(versions).Should(HaveLen(1))
+			Expect(versions[0].IsDefault).Should(BeTrue())
+		})
+		It("should keep versions up to the version limit", func() {
+			for k := 0; k < 3; k++ {
+				_, err := client.Update(ctx, &iampolicy.UpdateOptions{
+					Arn:          p.Arn,
+					Document:     fmt.Sprintf(`{"Version": "2012-10-17", "Statement": [{"Sid": "Update%d"}]}`, k),
+					VersionLimit: 3,
+				})
+				Expect(err).ShouldNot(HaveOccurred())
+			}
+			versions, err := client.ListVersions(ctx, &iampolicy.ListVersionsOptions{Arn: p.Arn})
+			Expect(err).ShouldNot(HaveOccurred())
+			ids := make([]string, 0, len(versions))
+			for _, version := range versions {
+				ids = append(ids, version.VersionId)
+			}
+			Expect(ids).Should(Equal([]string{"v4", "v3", "v2"}))
+			Expect(versions[0].IsDefault).Should(BeTrue())
+		})
+		It("should make room for a new version when the version limit is exceeded", func() {
+			for k := 0; k < iampolicy.MaxVersions-1; k++ {
+				_, err := service.CreatePolicyVersion(ctx, &iam.CreatePolicyVersionInput{
+					PolicyArn:      aws.String(p.Arn),
+					PolicyDocument: aws.String(`{"Version": "2012-10-17", "Statement": [{"Sid": "Manual"}]}`),
+				})
+				Expect(err).ShouldNot(HaveOccurred())
+			}
+			doc := `{"Version": "2012-10-17", "Statement": [{"Sid": "S3NoAccess"}]}`
+			updated, err := client.Update(ctx, &iampolicy.UpdateOptions{
+				Arn:          p.Arn,
+				Document:     doc,
+				VersionLimit: iampolicy.MaxVersions,
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(updated.Document).Should(Equal(doc))
+			versions, err := client.ListVersions(ctx, &iampolicy.ListVersionsOptions{Arn: p.Arn})
+			Expect(err).ShouldNot(HaveOccurred())
+			ids := make([]string, 0, len(versions))
+			for _, version := range versions {
+				ids = append(ids, version.VersionId)
+			}
+			Expect(ids).Should(Equal([]string{"v6", "v5", "v4", "v3", "v1"}))
+		})
+		It("should set the default version", func() {
+			_, err := client.Update(ctx, &iampolicy.UpdateOptions{
+				Arn:          p.Arn,
+				Document:     `{"Version": "2012-10-17", "Statement": [{"Sid": "S3NoAccess"}]}`,
+				VersionLimit: 2,
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(client.SetDefaultVersion(ctx, &iampolicy.SetDefaultVersionOptions{
+				Arn:       p.Arn,
+				VersionId: p.VersionId,
+			})).Should(Succeed())
+			out, err := client.Get(ctx, &iampolicy.GetOptions{Arn: p.Arn})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(out.VersionId).Should(Equal(p.VersionId))
+			Expect(out.Document).Should(Equal(p.Document))
+		})
+		It("should tag and untag the policy", func() {
+			Expect(client.Tag(ctx, &iampolicy.TagOptions{
+				Arn:  p.Arn,
+				Tags: map[string]string{"team": "payments", "env": "prod"},
+			})).Should(Succeed())
+			out, err := client.Get(ctx, &iampolicy.GetOptions{Arn: p.Arn})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(out.Tags).Should(Equal(map[string]string{"team": "payments", "env": "prod"}))
+
+			Expect(client.Untag(ctx, &iampolicy.UntagOptions{
+				Arn:  p.Arn,
+				Keys: []string{"env"},
+			})).Should(Succeed())
+			out, err = client.Get(ctx, &iampolicy.GetOptions{Arn: p.Arn})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(out.Tags).Should(Equal(map[string]string{"team": "payments"}))
+		})
+	})
+	It("should create an iam policy with tags", func() {
+		out, err := client.Create(ctx, &iampolicy.CreateOptions{
+			Name:     "iam-policy-tags",
+			Document: `{"Version": "2012-10-17", "Statement": [{"Sid": "S3FullAccess"}]}`,
+			Tags:     map[string]string{"team": "payments"},
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(out.Tags).Should(Equal(map[string]string{"team": "payments"}))
+	})
+	It("should find a policy by name past the first page", func() {
+		for k := 0; k < fake.DefaultMaxItems+5; k++ {
+			_, err := service.CreatePolicy(ctx, &iam.CreatePolicyInput{
+				PolicyName:     aws.String(fmt.Sprintf("iam-policy-%03d", k)),
+				PolicyDocument: aws.String(`{"Version": "2012-10-17", "Statement": [{"Sid": "S3FullAccess"}]}`),
+				Path:           aws.String("/controller-test/"),
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+		}
+		name := fmt.Sprintf("iam-policy-%03d", fake.DefaultMaxItems+4)
+		out, err := client.Get(ctx, &iampolicy.GetOptions{Name: name})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(out.Name).Should(Equal(name))
+	})
+	It("should create a policy after it wasn't found by name", func() {
+		_, err := client.Get(ctx, &iampolicy.GetOptions{Name: "iam-policy-missing"})
+		Expect(pkgaws.IsNotFound(err)).Should(BeTrue())
+		out, err := client.Create(ctx, &iampolicy.CreateOptions{
+			Name:     "iam-policy-missing",
+			Document: `{"Version": "2012-10-17", "Statement": [{"Sid": "S3FullAccess"}]}`,
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		got, err := client.Get(ctx, &iampolicy.GetOptions{Name: "iam-policy-missing"})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(got.Arn).Should(Equal(out.Arn))
+		Expect(client.Delete(ctx, &iampolicy.DeleteOptions{Arn: out.Arn})).Should(Succeed())
+		_, err = client.Get(ctx, &iampolicy.GetOptions{Name: "iam-policy-missing"})
+		Expect(pkgaws.IsNotFound(err)).Should(BeTrue())
 	})
 })
@@ -0,0 +1,172 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iampolicy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	QualifierForAllValues = "ForAllValues"
+	QualifierForAnyValue  = "ForAnyValue"
+)
+
+// conditions has the same fields as Conditions without the custom
+// marshalling
+type conditions Conditions
+
+// MarshalJSON writes the qualified operators as "<qualifier>:<operator>"
+// alongside the unqualified operators
+func (c Conditions) MarshalJSON() ([]byte, error) {
+	if c.ForAllValues == nil && c.ForAnyValue == nil {
+		return json.Marshal(conditions(c))
+	}
+	m, err := toRawMap(&c)
+	if err != nil {
+		return nil, err
+	}
+	for qualifier, set := range c.qualified() {
+		if set == nil {
+			continue
+		}
+		raw, err := toRawMap(set)
+		if err != nil {
+			return nil, err
+		}
+		for operator, value := range raw {
+			m[qualifier+":"+operator] = value
+		}
+	}
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON reads operators prefixed with ForAllValues or ForAnyValue
+// into the matching qualified set
+func (c *Conditions) UnmarshalJSON(b []byte) error {
+	m := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return err
+	}
+	unqualified := map[string]json.RawMessage{}
+	qualified := map[string]map[string]json.RawMessage{}
+	for key, value := range m {
+		parts := strings.SplitN(key, ":", 2)
+		if len(parts) == 2 && (parts[0] == QualifierForAllValues || parts[0] == QualifierForAnyValue) {
+			if qualified[parts[0]] == nil {
+				qualified[parts[0]] = map[string]json.RawMessage{}
+			}
+			qualified[parts[0]][parts[1]] = value
+			continue
+		}
+		unqualified[key] = value
+	}
+	*c = Conditions{}
+	if err := fromRawMap(unqualified, c); err != nil {
+		return err
+	}
+	for qualifier, raw := range qualified {
+		set := &Conditions{}
+		if err := fromRawMap(raw, set); err != nil {
+			return err
+		}
+		if qualifier == QualifierForAllValues {
+			c.ForAllValues = set
+		} else {
+			c.ForAnyValue = set
+		}
+	}
+	return nil
+}
+
+func (c *Conditions) qualified() map[string]*Conditions {
+	return map[string]*Conditions{
+		QualifierForAllValues: c.ForAllValues,
+		QualifierForAnyValue:  c.ForAnyValue,
+	}
+}
+
+// toRawMap returns the unqualified operators of c keyed by operator name
+func toRawMap(c *Conditions) (map[string]json.RawMessage, error) {
+	raw, err := json.Marshal((*conditions)(c))
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]json.RawMessage{}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func fromRawMap(m map[string]json.RawMessage, c *Conditions) error {
+	normalized := make(map[string]map[string][]string, len(m))
+	for operator, raw := range m {
+		values, err := conditionValues(raw)
+		if err != nil {
+			return fmt.Errorf("%s: %w", operator, err)
+		}
+		normalized[operator] = values
+	}
+	raw, err := json.Marshal(normalized)
+	if err != nil {
+		return err
+	}
+	// Unknown operators are an error rather than being dropped, otherwise
+	// documents that differ only by those operators would compare equal
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode((*conditions)(c))
+}
+
+// conditionValues reads the values of a condition operator keyed by
+// condition key. IAM compares condition values as strings and accepts a
+// single value in place of a list, so {"aws:SecureTransport": false} is read
+// the same as {"aws:SecureTransport": ["false"]}
+func conditionValues(raw json.RawMessage) (map[string][]string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	m := map[string]interface{}{}
+	if err := decoder.Decode(&m); err != nil {
+		return nil, err
+	}
+	rv := make(map[string][]string, len(m))
+	for key, value := range m {
+		items, ok := value.([]interface{})
+		if !ok {
+			items = []interface{}{value}
+		}
+		values := make([]string, 0, len(items))
+		for _, item := range items {
+			switch item := item.(type) {
+			case string:
+				values = append(values, item)
+			case bool:
+				values = append(values, strconv.FormatBool(item))
+			case json.Number:
+				values = append(values, item.String())
+			default:
+				return nil, fmt.Errorf("condition %s: unsupported value %v", key, item)
+			}
+		}
+		rv[key] = values
+	}
+	return rv, nil
+}
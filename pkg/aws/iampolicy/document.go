@@ -31,6 +31,10 @@ type Document interface {
 }
 
 type Conditions struct {
+	ArnEquals                         map[string][]string `json:",omitempty"` // nolint: tagliatelle
+	ArnEqualsIfExists                 map[string][]string `json:",omitempty"` // nolint: tagliatelle
+	ArnNotEquals                      map[string][]string `json:",omitempty"` // nolint: tagliatelle
+	ArnNotEqualsIfExists              map[string][]string `json:",omitempty"` // nolint: tagliatelle
 	ArnLike                           map[string][]string `json:",omitempty"` // nolint: tagliatelle
 	ArnLikeIfExists                   map[string][]string `json:",omitempty"` // nolint: tagliatelle
 	ArnNotLike                        map[string][]string `json:",omitempty"` // nolint: tagliatelle
@@ -80,19 +84,32 @@ type Conditions struct {
 	StringEqualsIgnoreCaseIfExists    map[string][]string `json:",omitempty"` // nolint: tagliatelle
 	StringNotEqualsIgnoreCase         map[string][]string `json:",omitempty"` // nolint: tagliatelle
 	StringNotEqualsIgnoreCaseIfExists map[string][]string `json:",omitempty"` // nolint: tagliatelle
+	// ForAllValues and ForAnyValue hold the operators qualified with a set
+	// operator, e.g. ForAllValues:StringEquals
+	ForAllValues *Conditions `json:"-"`
+	ForAnyValue  *Conditions `json:"-"`
 }
 
+// Principal maps a principal type such as AWS or Service to the principals
+// of that type
+type Principal map[string]interface{}
+
 type Statement struct {
-	Sid        string      `json:",omitempty"` // nolint: tagliatelle
-	Effect     string      // Allow/Deny
-	Action     interface{} `json:",omitempty"` // nolint: tagliatelle
-	Resource   interface{}
-	Conditions *Conditions `json:"Condition,omitempty"` // nolint: tagliatelle
+	Sid          string      `json:",omitempty"` // nolint: tagliatelle
+	Effect       string      // Allow/Deny
+	Principal    interface{} `json:",omitempty"`          // nolint: tagliatelle
+	NotPrincipal interface{} `json:",omitempty"`          // nolint: tagliatelle
+	Action       interface{} `json:",omitempty"`          // nolint: tagliatelle
+	NotAction    interface{} `json:",omitempty"`          // nolint: tagliatelle
+	Resource     interface{} `json:",omitempty"`          // nolint: tagliatelle
+	NotResource  interface{} `json:",omitempty"`          // nolint: tagliatelle
+	Conditions   *Conditions `json:"Condition,omitempty"` // nolint: tagliatelle
 }
 
 type document struct {
-	Version    string
-	Statements []Statement `json:"Statement"` // nolint: tagliatelle
+	Version    string     `json:",omitempty"` // nolint: tagliatelle
+	Id         string     `json:",omitempty"` // nolint: tagliatelle
+	Statements statements `json:"Statement"`  // nolint: tagliatelle
 }
 
 func (d *document) Marshal() (string, error) {
@@ -118,18 +135,43 @@ func (d *document) SetVersion(s string) {
 func (d *document) SetStatements(statements []Statement) {
 	for k := 0; k < len(statements); k++ {
 		statement := statements[k]
-		if _, ok := statement.Action.(string); ok {
-			statement.Action = []interface{}{statement.Action}
-		}
-		if _, ok := statement.Resource.(string); ok {
-			statement.Resource = []interface{}{statement.Resource}
-		}
+		statement.Action = toList(statement.Action)
+		statement.NotAction = toList(statement.NotAction)
+		statement.Resource = toList(statement.Resource)
+		statement.NotResource = toList(statement.NotResource)
+		statement.Principal = toPrincipal(statement.Principal)
+		statement.NotPrincipal = toPrincipal(statement.NotPrincipal)
 
 		statements[k] = statement
 	}
 	d.Statements = statements
 }
 
+// toList converts a single string element to a list so both forms compare
+// equal
+func toList(value interface{}) interface{} {
+	if _, ok := value.(string); ok {
+		return []interface{}{value}
+	}
+	return value
+}
+
+// toPrincipal converts a principal to its map form. The principal "*" is
+// the same as the aws principal "*"
+func toPrincipal(value interface{}) interface{} {
+	switch principal := value.(type) {
+	case string:
+		return Principal{"AWS": []interface{}{principal}}
+	case map[string]interface{}:
+		rv := make(Principal, len(principal))
+		for key, item := range principal {
+			rv[key] = toList(item)
+		}
+		return rv
+	}
+	return value
+}
+
 func (d *document) GetStatements() []Statement {
 	return d.Statements
 }
@@ -155,4 +197,26 @@ func NewDocumentFromString(doc string) (*document, error) {
 	return d, nil
 }
 
+// parseDocument reads doc without defaulting the version. A document
+// without a version is evaluated by IAM as 2008-10-17, which doesn't
+// support policy variables, so it has to be written back without one
+func parseDocument(doc string) (*document, error) {
+	d := &document{}
+	if err := d.unmarshal(doc); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// NormalizeDocument returns doc in the form the controller writes policy
+// documents, e.g. single actions and resources written as lists. The
+// version is kept as it is
+func NormalizeDocument(doc string) (string, error) {
+	d, err := parseDocument(doc)
+	if err != nil {
+		return "", err
+	}
+	return d.Marshal()
+}
+
 var _ Document = &document{}
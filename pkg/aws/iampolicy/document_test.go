@@ -88,4 +88,96 @@ var _ = Describe("Document", func() {
 		doc.SetVersion("2021-12-10")
 		Expect(doc.GetVersion()).Should(Equal("2021-12-10"))
 	})
+	It("should marshal negated elements and principals", func() {
+		doc := iampolicy.NewDocument()
+		doc.SetVersion("2008-10-17")
+		doc.SetStatements([]iampolicy.Statement{{
+			Effect:       "Deny",
+			NotPrincipal: iampolicy.Principal{"AWS": []string{"arn:aws:iam::111122223333:root"}},
+			NotAction:    []string{"iam:*"},
+			NotResource:  "arn:aws:s3:::BUCKET-NAME",
+		}})
+		out, err := doc.Marshal()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(out).Should(Equal(
+			`{"Version":"2008-10-17","Statement":[{"Effect":"Deny","NotPrincipal":{"AWS":["arn:aws:iam::111122223333:root"]},"NotAction":["iam:*"],"NotResource":["arn:aws:s3:::BUCKET-NAME"]}]}`,
+		))
+	})
+	It("should consider the principal * to be the same as an aws principal *", func() {
+		doc, err := iampolicy.NewDocumentFromString(
+			`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":"*","NotAction":"s3:*","Resource":"*"}]}`,
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+		doc2, err := iampolicy.NewDocumentFromString(
+			`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":["*"]},"NotAction":["s3:*"],"Resource":["*"]}]}`,
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(doc.Equals(doc2)).Should(BeTrue())
+	})
+	It("should compare the version", func() {
+		doc, err := iampolicy.NewDocumentFromString(
+			`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:*","Resource":"*"}]}`,
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+		doc2, err := iampolicy.NewDocumentFromString(
+			`{"Version":"2008-10-17","Statement":[{"Effect":"Allow","Action":"s3:*","Resource":"*"}]}`,
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(doc.Equals(doc2)).Should(BeFalse())
+	})
+	It("should marshal set qualified condition operators", func() {
+		doc := iampolicy.NewDocument()
+		doc.SetStatements([]iampolicy.Statement{{
+			Effect:   "Allow",
+			Action:   []string{"ec2:CreateTags"},
+			Resource: []string{"*"},
+			Conditions: &iampolicy.Conditions{
+				StringEquals: map[string][]string{"aws:RequestTag/team": {"payments"}},
+				ForAllValues: &iampolicy.Conditions{
+					StringEquals: map[string][]string{"aws:TagKeys": {"team", "env"}},
+				},
+				ForAnyValue: &iampolicy.Conditions{
+					StringLike: map[string][]string{"aws:TagKeys": {"cost-*"}},
+				},
+			},
+		}})
+		out, err := doc.Marshal()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(out).Should(ContainSubstring(`"ForAllValues:StringEquals":{"aws:TagKeys":["team","env"]}`))
+		Expect(out).Should(ContainSubstring(`"ForAnyValue:StringLike":{"aws:TagKeys":["cost-*"]}`))
+		Expect(out).Should(ContainSubstring(`"StringEquals":{"aws:RequestTag/team":["payments"]}`))
+
+		doc2, err := iampolicy.NewDocumentFromString(out)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(doc2.Marshal()).Should(Equal(out))
+	})
+	It("should unmarshal set qualified condition operators", func() {
+		doc, err := iampolicy.NewDocumentFromString(
+			`{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Action":["ec2:CreateTags"],"Resource":["*"],"Condition":{"ForAllValues:StringEquals":{"aws:TagKeys":["team"]}}}]}`,
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+		conditions := doc.GetStatements()[0].Conditions
+		Expect(conditions.StringEquals).Should(BeNil())
+		Expect(conditions.ForAnyValue).Should(BeNil())
+		Expect(conditions.ForAllValues).ShouldNot(BeNil())
+		Expect(conditions.ForAllValues.StringEquals).Should(Equal(map[string][]string{"aws:TagKeys": {"team"}}))
+	})
+	It("should compare typed and single condition values as strings", func() {
+		doc, err := iampolicy.NewDocumentFromString(
+			`{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Action":"s3:*","Resource":"*","Condition":{"Bool":{"aws:SecureTransport":false},"NumericLessThan":{"s3:max-keys":[10]}}}]}`,
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+		doc2, err := iampolicy.NewDocumentFromString(
+			`{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Action":"s3:*","Resource":"*","Condition":{"Bool":{"aws:SecureTransport":["false"]},"NumericLessThan":{"s3:max-keys":"10"}}}]}`,
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(doc.Equals(doc2)).Should(BeTrue())
+		Expect(doc.GetStatements()[0].Conditions.Bool).Should(Equal(map[string][]string{"aws:SecureTransport": {"false"}}))
+	})
+	It("should not read object condition values", func() {
+		_, err := iampolicy.NewDocumentFromString(
+			`{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Action":"s3:*","Resource":"*","Condition":{"Bool":{"aws:SecureTransport":{"value":false}}}}]}`,
+		)
+		Expect(err).Should(HaveOccurred())
+	})
 })
@@ -25,4 +25,8 @@ type Interface interface {
 	Update(ctx context.Context, options *UpdateOptions) (*IamPolicy, error)
 	Get(ctx context.Context, options *GetOptions) (*IamPolicy, error)
 	Delete(ctx context.Context, options *DeleteOptions) error
+	ListVersions(ctx context.Context, options *ListVersionsOptions) ([]PolicyVersion, error)
+	SetDefaultVersion(ctx context.Context, options *SetDefaultVersionOptions) error
+	Tag(ctx context.Context, options *TagOptions) error
+	Untag(ctx context.Context, options *UntagOptions) error
 }
@@ -0,0 +1,108 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iampolicy
+
+import (
+	"errors"
+	"fmt"
+	"unicode"
+)
+
+const (
+	// MaxDocumentSize is the number of characters, not counting whitespace,
+	// AWS allows in a managed policy document
+	MaxDocumentSize = 6144
+	// MaxNameLength is the longest name AWS allows for a managed policy
+	MaxNameLength = 128
+)
+
+// ErrStatementTooLarge is returned when a single statement doesn't fit in a
+// managed policy, so the document can't be sharded
+var ErrStatementTooLarge = errors.New("statement is larger than the managed policy size limit")
+
+// Shard splits doc into documents that each fit in a managed policy.
+// Statements are packed in order, so the same document always produces the
+// same shards. A document that already fits is returned as the only shard
+func Shard(doc string) ([]string, error) {
+	d, err := parseDocument(doc)
+	if err != nil {
+		return nil, err
+	}
+	whole, err := d.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	if documentSize(whole) <= MaxDocumentSize {
+		return []string{whole}, nil
+	}
+
+	shards := make([]string, 0)
+	current := &document{Version: d.Version, Id: d.Id}
+	last := ""
+	for k, statement := range d.Statements {
+		current.Statements = append(current.Statements, statement)
+		raw, err := current.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		if documentSize(raw) <= MaxDocumentSize {
+			last = raw
+			continue
+		}
+		if len(current.Statements) == 1 {
+			return nil, fmt.Errorf("statement %d: %w", k, ErrStatementTooLarge)
+		}
+		// The statement doesn't fit in the current shard, so close it and
+		// start the next one with the statement
+		shards = append(shards, last)
+		current = &document{Version: d.Version, Id: d.Id, Statements: []Statement{statement}}
+		last, err = current.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		if documentSize(last) > MaxDocumentSize {
+			return nil, fmt.Errorf("statement %d: %w", k, ErrStatementTooLarge)
+		}
+	}
+	return append(shards, last), nil
+}
+
+// documentSize is the size of the document the way AWS counts it against
+// the managed policy limit
+func documentSize(doc string) int {
+	size := 0
+	for _, r := range doc {
+		if !unicode.IsSpace(r) {
+			size++
+		}
+	}
+	return size
+}
+
+// ShardName returns the name of the managed policy holding shard index of
+// the policy name. The first shard keeps the policy name so policies that
+// fit in a single document aren't renamed
+func ShardName(name string, index int) string {
+	if index == 0 {
+		return name
+	}
+	suffix := fmt.Sprintf("-shard-%d", index)
+	if len(name)+len(suffix) > MaxNameLength {
+		name = name[:MaxNameLength-len(suffix)]
+	}
+	return name + suffix
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iampolicy_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/iampolicy"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Shard", func() {
+	// statements returns a document with n statements that each take up
+	// roughly a tenth of a managed policy
+	statements := func(n int) string {
+		items := make([]string, 0, n)
+		for k := 0; k < n; k++ {
+			items = append(items, fmt.Sprintf(`{"Sid":"S%d","Effect":"Allow","Action":["s3:GetObject"],"Resource":["arn:aws:s3:::%s"]}`, k, strings.Repeat("a", 600)))
+		}
+		return `{"Version":"2012-10-17","Statement":[` + strings.Join(items, ",") + `]}`
+	}
+	It("should return a document that fits as the only shard", func() {
+		doc := `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "*"}]}`
+		shards, err := iampolicy.Shard(doc)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(shards).Should(HaveLen(1))
+		normalized, err := iampolicy.NormalizeDocument(doc)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(shards[0]).Should(Equal(normalized))
+	})
+	It("should split large documents into shards that fit", func() {
+		shards, err := iampolicy.Shard(statements(25))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(len(shards)).Should(BeNumerically(">", 1))
+		sids := make([]string, 0)
+		for _, shard := range shards {
+			Expect(len(shard)).Should(BeNumerically("<=", iampolicy.MaxDocumentSize))
+			doc, err := iampolicy.NewDocumentFromString(shard)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(doc.GetVersion()).Should(Equal("2012-10-17"))
+			for _, statement := range doc.GetStatements() {
+				sids = append(sids, statement.Sid)
+			}
+		}
+		// Statements should stay in order across the shards
+		expected := make([]string, 0, 25)
+		for k := 0; k < 25; k++ {
+			expected = append(expected, fmt.Sprintf("S%d", k))
+		}
+		Expect(sids).Should(Equal(expected))
+	})
+	It("should shard the same document the same way", func() {
+		first, err := iampolicy.Shard(statements(25))
+		Expect(err).ShouldNot(HaveOccurred())
+		second, err := iampolicy.Shard(statements(25))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(second).Should(Equal(first))
+	})
+	It("should return an error for a statement that doesn't fit", func() {
+		doc := fmt.Sprintf(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"arn:aws:s3:::%s"}]}`, strings.Repeat("a", iampolicy.MaxDocumentSize))
+		_, err := iampolicy.Shard(doc)
+		Expect(errors.Is(err, iampolicy.ErrStatementTooLarge)).Should(BeTrue())
+	})
+})
+
+var _ = Describe("ShardName", func() {
+	It("should keep the name for the first shard", func() {
+		Expect(iampolicy.ShardName("webservice", 0)).Should(Equal("webservice"))
+	})
+	It("should add the shard index to the name", func() {
+		Expect(iampolicy.ShardName("webservice", 2)).Should(Equal("webservice-shard-2"))
+	})
+	It("should truncate long names", func() {
+		name := iampolicy.ShardName(strings.Repeat("a", iampolicy.MaxNameLength), 1)
+		Expect(name).Should(HaveLen(iampolicy.MaxNameLength))
+		Expect(name).Should(HaveSuffix("-shard-1"))
+	})
+})
@@ -0,0 +1,44 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iampolicy
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// statements is the Statement element of a policy document. IAM accepts a
+// single statement object in place of a list
+type statements []Statement
+
+// UnmarshalJSON reads a list of statements or a single statement. Unknown
+// elements are rejected, since the decoder a document is read with doesn't
+// pass DisallowUnknownFields on to custom unmarshalling
+func (s *statements) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if len(b) > 0 && b[0] == '{' {
+		b = append(append([]byte{'['}, b...), ']')
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.DisallowUnknownFields()
+	items := []Statement{}
+	if err := decoder.Decode(&items); err != nil {
+		return err
+	}
+	*s = items
+	return nil
+}
@@ -22,6 +22,9 @@ type CreateOptions struct {
 	Name        string
 	Document    string
 	Description string
+	// Path is appended to the client path
+	Path string
+	Tags map[string]string
 }
 
 type DeleteOptions struct {
@@ -36,6 +39,29 @@ type GetOptions struct {
 type UpdateOptions struct {
 	Arn      string
 	Document string
+	// VersionLimit is the number of versions to keep, including the new
+	// default version. Older versions are deleted. Anything less than one
+	// keeps only the default version
+	VersionLimit int
+}
+
+type ListVersionsOptions struct {
+	Arn string
+}
+
+type SetDefaultVersionOptions struct {
+	Arn       string
+	VersionId string
+}
+
+type TagOptions struct {
+	Arn  string
+	Tags map[string]string
+}
+
+type UntagOptions struct {
+	Arn  string
+	Keys []string
 }
 
 type IamPolicy struct {
@@ -48,4 +74,11 @@ type IamPolicy struct {
 	VersionId string
 	Name      string
 	Id        string
+	Tags      map[string]string
+}
+
+type PolicyVersion struct {
+	VersionId  string
+	CreateDate time.Time
+	IsDefault  bool
 }
@@ -0,0 +1,61 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iampolicy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// ValidateDocument returns an error if doc is not an iam policy document.
+// Elements the document type doesn't know about are rejected since they
+// would be dropped when the document is normalized
+func ValidateDocument(doc string) error {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(doc)))
+	decoder.DisallowUnknownFields()
+	d := &document{}
+	if err := decoder.Decode(d); err != nil {
+		return err
+	}
+	switch d.Version {
+	case "", "2012-10-17", "2008-10-17":
+	default:
+		return fmt.Errorf("unsupported version %q", d.Version)
+	}
+	if len(d.Statements) == 0 {
+		return fmt.Errorf("at least one statement is required")
+	}
+	for k, statement := range d.Statements {
+		if statement.Effect != "Allow" && statement.Effect != "Deny" {
+			return fmt.Errorf("statement %d: effect must be Allow or Deny", k)
+		}
+		if statement.Action != nil && statement.NotAction != nil {
+			return fmt.Errorf("statement %d: only one of Action or NotAction may be specified", k)
+		}
+		if statement.Resource != nil && statement.NotResource != nil {
+			return fmt.Errorf("statement %d: only one of Resource or NotResource may be specified", k)
+		}
+		if statement.Principal != nil && statement.NotPrincipal != nil {
+			return fmt.Errorf("statement %d: only one of Principal or NotPrincipal may be specified", k)
+		}
+		if statement.Action == nil && statement.NotAction == nil {
+			return fmt.Errorf("statement %d: one of Action or NotAction is required", k)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iampolicy_test
+
+import (
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/iampolicy"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ValidateDocument", func() {
+	It("should accept valid documents", func() {
+		for name, doc := range map[string]string{
+			"single action":       `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:*","Resource":"*"}]}`,
+			"policy id":           `{"Version":"2012-10-17","Id":"S3Policy","Statement":[{"Effect":"Allow","Action":"s3:*","Resource":"*"}]}`,
+			"arn condition":       `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"sns:Publish","Resource":"*","Condition":{"ArnEquals":{"aws:SourceArn":"arn:aws:s3:::bucket"}}}]}`,
+			"qualified condition": `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"ec2:CreateTags","Resource":"*","Condition":{"ForAllValues:StringEquals":{"aws:TagKeys":["team"]}}}]}`,
+			"single statement":    `{"Version":"2012-10-17","Statement":{"Effect":"Allow","Action":"s3:*","Resource":"*"}}`,
+		} {
+			Expect(iampolicy.ValidateDocument(doc)).Should(Succeed(), name)
+		}
+	})
+	It("should reject invalid documents", func() {
+		for name, doc := range map[string]string{
+			"not json":                   `Version: 2012-10-17`,
+			"no statements":              `{"Version":"2012-10-17","Statement":[]}`,
+			"unknown version":            `{"Version":"2021-12-10","Statement":[{"Effect":"Allow","Action":"s3:*","Resource":"*"}]}`,
+			"unknown element":            `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Actions":"s3:*","Resource":"*"}]}`,
+			"unknown single element":     `{"Version":"2012-10-17","Statement":{"Effect":"Allow","Actions":"s3:*","Resource":"*"}}`,
+			"unknown condition operator": `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:*","Resource":"*","Condition":{"StringMatches":{"aws:username":"x"}}}]}`,
+			"unknown effect":             `{"Version":"2012-10-17","Statement":[{"Effect":"Maybe","Action":"s3:*","Resource":"*"}]}`,
+			"no action":                  `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Resource":"*"}]}`,
+			"action and notAction":       `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:*","NotAction":"iam:*","Resource":"*"}]}`,
+		} {
+			Expect(iampolicy.ValidateDocument(doc)).Should(HaveOccurred(), name)
+		}
+	})
+	It("should normalize a document", func() {
+		out, err := iampolicy.NormalizeDocument(
+			`{"Statement":[{"Effect":"Allow","Principal":"*","Action":"s3:GetObject","Resource":"arn:aws:s3:::bucket/*"}]}`,
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(out).Should(Equal(
+			`{"Statement":[{"Effect":"Allow","Principal":{"AWS":["*"]},"Action":["s3:GetObject"],"Resource":["arn:aws:s3:::bucket/*"]}]}`,
+		))
+	})
+	It("should keep the version of a document", func() {
+		out, err := iampolicy.NormalizeDocument(
+			`{"Version":"2008-10-17","Statement":{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}}`,
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(out).Should(Equal(
+			`{"Version":"2008-10-17","Statement":[{"Effect":"Allow","Action":["s3:GetObject"],"Resource":["*"]}]}`,
+		))
+	})
+})
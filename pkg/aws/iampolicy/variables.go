@@ -0,0 +1,70 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iampolicy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExpandVariables replaces every ${name} in s with the value lookup returns
+// for name. IAM policy variables such as ${aws:username} and the special
+// characters ${*}, ${?} and ${$} are left for IAM to resolve. $${ is written
+// as ${ without expanding what follows, so $${name} becomes ${name}.
+// Substituted values aren't expanded again
+func ExpandVariables(s string, lookup func(name string) (string, bool)) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		if strings.HasPrefix(s[i:], "$${") {
+			b.WriteString("${")
+			i += len("$${")
+			continue
+		}
+		if !strings.HasPrefix(s[i:], "${") {
+			b.WriteByte(s[i])
+			i++
+			continue
+		}
+		end := strings.IndexByte(s[i:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated variable in %q", s)
+		}
+		name := s[i+len("${") : i+end]
+		if isPolicyVariable(name) {
+			b.WriteString(s[i : i+end+1])
+		} else {
+			value, ok := lookup(name)
+			if !ok {
+				return "", fmt.Errorf("unknown variable %q", name)
+			}
+			b.WriteString(value)
+		}
+		i += end + 1
+	}
+	return b.String(), nil
+}
+
+// isPolicyVariable returns true if name is resolved by IAM when the policy
+// is evaluated. Policy variables are condition keys, which always have a
+// service prefix
+func isPolicyVariable(name string) bool {
+	switch name {
+	case "*", "?", "$":
+		return true
+	}
+	return strings.Contains(name, ":")
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iampolicy_test
+
+import (
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/iampolicy"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ExpandVariables", func() {
+	variables := map[string]string{
+		"bucket":        "webservice",
+		"aws.accountId": "111122223333",
+		"nested":        "${bucket}",
+	}
+	lookup := func(name string) (string, bool) {
+		value, ok := variables[name]
+		return value, ok
+	}
+	It("should expand variables", func() {
+		out, err := iampolicy.ExpandVariables("arn:aws:s3:::${bucket}-${aws.accountId}/*", lookup)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(out).Should(Equal("arn:aws:s3:::webservice-111122223333/*"))
+	})
+	It("should leave iam policy variables", func() {
+		out, err := iampolicy.ExpandVariables("arn:aws:s3:::${bucket}/home/${aws:username}/${*}${?}${$}", lookup)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(out).Should(Equal("arn:aws:s3:::webservice/home/${aws:username}/${*}${?}${$}"))
+	})
+	It("should not expand escaped variables", func() {
+		out, err := iampolicy.ExpandVariables("$${bucket} $${unknown}", lookup)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(out).Should(Equal("${bucket} ${unknown}"))
+	})
+	It("should not expand substituted values", func() {
+		out, err := iampolicy.ExpandVariables("${nested}", lookup)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(out).Should(Equal("${bucket}"))
+	})
+	It("should return an error for unknown variables", func() {
+		_, err := iampolicy.ExpandVariables("${unknown}", lookup)
+		Expect(err).Should(HaveOccurred())
+	})
+	It("should return an error for unterminated variables", func() {
+		_, err := iampolicy.ExpandVariables("arn:aws:s3:::${bucket", lookup)
+		Expect(err).Should(HaveOccurred())
+	})
+})
@@ -19,23 +19,36 @@ type Client struct {
 
 func (c *Client) Create(ctx context.Context, options *CreateOptions) (*IamRole, error) {
 	rv := &IamRole{}
-	out, err := c.service.CreateRole(ctx, &iam.CreateRoleInput{
+	in := &iam.CreateRoleInput{
 		AssumeRolePolicyDocument: aws.String(options.PolicyDocument),
 		RoleName:                 aws.String(options.Name),
 		Description:              aws.String(options.Description),
 		MaxSessionDuration:       aws.Int32(options.MaxDurationSeconds),
-		Path:                     aws.String(fmt.Sprintf("/%s/", c.path)),
-	})
+		Path:                     aws.String(c.fullPath(options.Path)),
+		Tags:                     pkgaws.NewTags(options.Tags),
+	}
+	if len(options.PermissionsBoundary) > 0 {
+		in.PermissionsBoundary = aws.String(options.PermissionsBoundary)
+	}
+	out, err := c.service.CreateRole(ctx, in)
 	if err != nil {
 		return rv, err
 	}
 	return c.Get(ctx, &GetOptions{Name: aws.ToString(out.Role.RoleName)})
 }
 
+// fullPath appends path to the client path
+func (c *Client) fullPath(path string) string {
+	if len(path) == 0 {
+		return fmt.Sprintf("/%s/", c.path)
+	}
+	return fmt.Sprintf("/%s/%s/", c.path, path)
+}
+
 func (c *Client) Update(ctx context.Context, options *UpdateOptions) (*IamRole, error) {
 	in := &iam.UpdateRoleInput{RoleName: aws.String(options.Name)}
-	if len(options.Description) > 0 {
-		in.Description = aws.String(options.Description)
+	if options.Description != nil {
+		in.Description = options.Description
 	}
 	if len(options.PolicyDocument) > 0 {
 		// Update the policy document
@@ -47,6 +60,15 @@ func (c *Client) Update(ctx context.Context, options *UpdateOptions) (*IamRole,
 			return &IamRole{}, err
 		}
 	}
+	if len(options.PermissionsBoundary) > 0 {
+		_, err := c.service.PutRolePermissionsBoundary(ctx, &iam.PutRolePermissionsBoundaryInput{
+			RoleName:            aws.String(options.Name),
+			PermissionsBoundary: aws.String(options.PermissionsBoundary),
+		})
+		if err != nil {
+			return &IamRole{}, err
+		}
+	}
 	if options.MaxDurationSeconds > 0 {
 		in.MaxSessionDuration = aws.Int32(options.MaxDurationSeconds)
 	}
@@ -70,14 +92,20 @@ func (c *Client) Get(ctx context.Context, options *GetOptions) (*IamRole, error)
 	if err != nil {
 		return &IamRole{}, err
 	}
-	return &IamRole{
-		TrustPolicy: policy,
-		Arn:         aws.ToString(out.Role.Arn),
-		Id:          aws.ToString(out.Role.RoleId),
-		CreateDate:  aws.ToTime(out.Role.CreateDate),
-		Name:        aws.ToString(out.Role.RoleName),
-		Description: aws.ToString(out.Role.Description),
-	}, nil
+	rv := &IamRole{
+		TrustPolicy:        policy,
+		Arn:                aws.ToString(out.Role.Arn),
+		Id:                 aws.ToString(out.Role.RoleId),
+		CreateDate:         aws.ToTime(out.Role.CreateDate),
+		Name:               aws.ToString(out.Role.RoleName),
+		Description:        aws.ToString(out.Role.Description),
+		Tags:               pkgaws.TagMap(out.Role.Tags),
+		MaxDurationSeconds: aws.ToInt32(out.Role.MaxSessionDuration),
+	}
+	if out.Role.PermissionsBoundary != nil {
+		rv.PermissionsBoundary = aws.ToString(out.Role.PermissionsBoundary.PermissionsBoundaryArn)
+	}
+	return rv, nil
 }
 
 func (c *Client) Delete(ctx context.Context, options *DeleteOptions) error {
@@ -87,6 +115,33 @@ func (c *Client) Delete(ctx context.Context, options *DeleteOptions) error {
 	return err
 }
 
+func (c *Client) DeletePermissionsBoundary(ctx context.Context, options *DeletePermissionsBoundaryOptions) error {
+	_, err := c.service.DeleteRolePermissionsBoundary(ctx, &iam.DeleteRolePermissionsBoundaryInput{
+		RoleName: aws.String(options.Name),
+	})
+	return err
+}
+
+func (c *Client) Tag(ctx context.Context, options *TagOptions) error {
+	if _, err := c.service.TagRole(ctx, &iam.TagRoleInput{
+		RoleName: aws.String(options.Name),
+		Tags:     pkgaws.NewTags(options.Tags),
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *Client) Untag(ctx context.Context, options *UntagOptions) error {
+	if _, err := c.service.UntagRole(ctx, &iam.UntagRoleInput{
+		RoleName: aws.String(options.Name),
+		TagKeys:  options.Keys,
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
 func (c *Client) AttachPolicy(ctx context.Context, options *AttachOptions) error {
 	if _, err := c.service.AttachRolePolicy(ctx, &iam.AttachRolePolicyInput{
 		RoleName:  aws.String(options.Name),
@@ -111,21 +166,106 @@ func (c *Client) ListAttachedPolicies(ctx context.Context, options *ListOptions)
 	if len(options.Name) == 0 {
 		return nil, &iamtypes.InvalidInputException{}
 	}
-	out, err := c.service.ListAttachedRolePolicies(ctx, &iam.ListAttachedRolePoliciesInput{
-		RoleName: aws.String(options.Name),
-		// This is actually probably not a good idea. The policies are created
-		// with a different client so the path prefix aren't the same value
-		PathPrefix: aws.String(c.path),
+	// Policies are attached by arn from any path, including the aws managed
+	// policies, so the attachments aren't filtered by the client path
+	in := &iam.ListAttachedRolePoliciesInput{RoleName: aws.String(options.Name)}
+	rv := AttachedPolicies{}
+	for {
+		out, err := c.service.ListAttachedRolePolicies(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range out.AttachedPolicies {
+			rv.Insert(aws.ToString(p.PolicyName), aws.ToString(p.PolicyArn))
+		}
+		if !out.IsTruncated {
+			return rv, nil
+		}
+		in.Marker = out.Marker
+	}
+}
+
+func (c *Client) PutInlinePolicy(ctx context.Context, options *PutInlinePolicyOptions) error {
+	if _, err := c.service.PutRolePolicy(ctx, &iam.PutRolePolicyInput{
+		RoleName:       aws.String(options.Name),
+		PolicyName:     aws.String(options.PolicyName),
+		PolicyDocument: aws.String(options.Document),
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *Client) GetInlinePolicy(ctx context.Context, options *GetInlinePolicyOptions) (*InlinePolicy, error) {
+	out, err := c.service.GetRolePolicy(ctx, &iam.GetRolePolicyInput{
+		RoleName:   aws.String(options.Name),
+		PolicyName: aws.String(options.PolicyName),
 	})
 	if err != nil {
 		return nil, err
 	}
-	rv := AttachedPolicies{}
-	for _, p := range out.AttachedPolicies {
-		rv.Insert(aws.ToString(p.PolicyName), aws.ToString(p.PolicyArn))
+	document, err := url.QueryUnescape(aws.ToString(out.PolicyDocument))
+	if err != nil {
+		return nil, err
 	}
+	return &InlinePolicy{Name: aws.ToString(out.PolicyName), Document: document}, nil
+}
 
-	return rv, nil
+func (c *Client) DeleteInlinePolicy(ctx context.Context, options *DeleteInlinePolicyOptions) error {
+	if _, err := c.service.DeleteRolePolicy(ctx, &iam.DeleteRolePolicyInput{
+		RoleName:   aws.String(options.Name),
+		PolicyName: aws.String(options.PolicyName),
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *Client) ListInlinePolicies(ctx context.Context, options *ListOptions) ([]string, error) {
+	if len(options.Name) == 0 {
+		return nil, &iamtypes.InvalidInputException{}
+	}
+	in := &iam.ListRolePoliciesInput{RoleName: aws.String(options.Name)}
+	names := make([]string, 0)
+	for {
+		out, err := c.service.ListRolePolicies(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, out.PolicyNames...)
+		if !out.IsTruncated {
+			return names, nil
+		}
+		in.Marker = out.Marker
+	}
+}
+
+// ListInstanceProfiles returns the names of the instance profiles the role
+// is in
+func (c *Client) ListInstanceProfiles(ctx context.Context, options *ListOptions) ([]string, error) {
+	in := &iam.ListInstanceProfilesForRoleInput{RoleName: aws.String(options.Name)}
+	names := make([]string, 0)
+	for {
+		out, err := c.service.ListInstanceProfilesForRole(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+		for _, profile := range out.InstanceProfiles {
+			names = append(names, aws.ToString(profile.InstanceProfileName))
+		}
+		if !out.IsTruncated {
+			return names, nil
+		}
+		in.Marker = out.Marker
+	}
+}
+
+func (c *Client) RemoveFromInstanceProfile(ctx context.Context, options *RemoveFromInstanceProfileOptions) error {
+	_, err := c.service.RemoveRoleFromInstanceProfile(ctx, &iam.RemoveRoleFromInstanceProfileInput{
+		RoleName:            aws.String(options.Name),
+		InstanceProfileName: aws.String(options.InstanceProfileName),
+	})
+	return err
 }
 
 var _ Interface = &Client{}
@@ -50,6 +50,17 @@ var _ = Describe("Client", func() {
 		policy = string(out)
 	})
 	AfterEach(func() {
+		// Policies have to be removed before the role can be deleted
+		if policies, err := client.ListAttachedPolicies(ctx, &iamrole.ListOptions{Name: role.Name}); err == nil {
+			for _, p := range policies {
+				Expect(client.DetachPolicy(ctx, &iamrole.DetachOptions{Name: role.Name, PolicyArn: p.Arn})).To(Succeed())
+			}
+		}
+		if names, err := client.ListInlinePolicies(ctx, &iamrole.ListOptions{Name: role.Name}); err == nil {
+			for _, name := range names {
+				Expect(client.DeleteInlinePolicy(ctx, &iamrole.DeleteInlinePolicyOptions{Name: role.Name, PolicyName: name})).To(Succeed())
+			}
+		}
 		err := client.Delete(ctx, &iamrole.DeleteOptions{Name: role.Name})
 		if err != nil {
 			expected := &iamtypes.NoSuchEntityException{}
@@ -86,6 +97,19 @@ var _ = Describe("Client", func() {
 		Expect(aws.ToString(upstream.Role.RoleId)).Should(Equal(role.Id))
 		Expect(aws.ToString(upstream.Role.Description)).Should(Equal(role.Description))
 	})
+	It("Should create a role under a sub path", func() {
+		var err error
+		role, err = client.Create(ctx, &iamrole.CreateOptions{
+			Name:               "should-create-a-role",
+			MaxDurationSeconds: 3600,
+			PolicyDocument:     policy,
+			Path:               "team-a",
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(role.Arn).To(HaveSuffix(fmt.Sprintf(
+			":role/%s/team-a/should-create-a-role", namespace,
+		)))
+	})
 	It("Should update the role", func() {
 		var err error
 		role, err = client.Create(ctx, &iamrole.CreateOptions{
@@ -122,6 +146,33 @@ var _ = Describe("Client", func() {
 		Expect(aws.ToString(upstream.Role.RoleId)).Should(Equal(role.Id))
 		Expect(aws.ToString(upstream.Role.Description)).Should(Equal(role.Description))
 		Expect(aws.ToInt32(upstream.Role.MaxSessionDuration)).Should(Equal(int32(7200)))
+		Expect(updated.MaxDurationSeconds).Should(Equal(int32(7200)))
+	})
+	It("Should update the role description", func() {
+		var err error
+		role, err = client.Create(ctx, &iamrole.CreateOptions{
+			Name:               "should-update-the-description",
+			Description:        "Should create a role",
+			MaxDurationSeconds: 3600,
+			PolicyDocument:     policy,
+		})
+		Expect(err).Should(Succeed())
+		Expect(role.MaxDurationSeconds).Should(Equal(int32(3600)))
+
+		updated, err := client.Update(ctx, &iamrole.UpdateOptions{
+			Name:        role.Name,
+			Description: aws.String("Should update the description"),
+		})
+		Expect(err).Should(Succeed())
+		Expect(updated.Description).Should(Equal("Should update the description"))
+		Expect(updated.MaxDurationSeconds).Should(Equal(int32(3600)))
+
+		updated, err = client.Update(ctx, &iamrole.UpdateOptions{
+			Name:        role.Name,
+			Description: aws.String(""),
+		})
+		Expect(err).Should(Succeed())
+		Expect(updated.Description).Should(BeEmpty())
 	})
 	It("can attach a policy to the role", func() {
 		var policyClient = iampolicy.New(service, namespace)
@@ -187,9 +238,180 @@ var _ = Describe("Client", func() {
 		Expect(err).ShouldNot(HaveOccurred())
 		Expect(policies.Len()).Should(Equal(2))
 	})
+	It("should list attached policies outside of the client path", func() {
+		var err error
+		role, err = client.Create(ctx, &iamrole.CreateOptions{
+			Name:               fmt.Sprintf("iam-role-%s", uuid.New().String()),
+			MaxDurationSeconds: 3600,
+			PolicyDocument:     policy,
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		out, err := service.CreatePolicy(ctx, &iam.CreatePolicyInput{
+			PolicyName:     aws.String("iam-policy-" + uuid.New().String()[:8]),
+			PolicyDocument: aws.String(`{"Version": "2012-10-17", "Statement": [{"Sid": "S3FullAccess"}]}`),
+			Path:           aws.String("/aws-service-role/"),
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(client.AttachPolicy(ctx, &iamrole.AttachOptions{
+			Name:      role.Name,
+			PolicyArn: aws.ToString(out.Policy.Arn),
+		})).Should(Succeed())
+		policies, err := client.ListAttachedPolicies(ctx, &iamrole.ListOptions{Name: role.Name})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(policies.Len()).Should(Equal(1))
+	})
 	It("should return error when input is invalid", func() {
 		out, err := client.ListAttachedPolicies(ctx, &iamrole.ListOptions{Name: ""})
 		Expect(err).Should(HaveOccurred())
 		Expect(out).Should(BeNil())
 	})
+	It("should manage inline policies", func() {
+		var err error
+		role, err = client.Create(ctx, &iamrole.CreateOptions{
+			Name:               fmt.Sprintf("iam-role-%s", uuid.New().String()),
+			Description:        "aws iam controller tests",
+			MaxDurationSeconds: 3600,
+			PolicyDocument:     policy,
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		document := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:*"],"Resource":["*"]}]}`
+		Expect(client.PutInlinePolicy(ctx, &iamrole.PutInlinePolicyOptions{
+			Name:       role.Name,
+			PolicyName: "s3-full-access",
+			Document:   document,
+		})).Should(Succeed())
+
+		names, err := client.ListInlinePolicies(ctx, &iamrole.ListOptions{Name: role.Name})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(names).Should(ConsistOf("s3-full-access"))
+
+		inline, err := client.GetInlinePolicy(ctx, &iamrole.GetInlinePolicyOptions{
+			Name:       role.Name,
+			PolicyName: "s3-full-access",
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(inline.Document).Should(Equal(document))
+
+		Expect(client.DeleteInlinePolicy(ctx, &iamrole.DeleteInlinePolicyOptions{
+			Name:       role.Name,
+			PolicyName: "s3-full-access",
+		})).Should(Succeed())
+		names, err = client.ListInlinePolicies(ctx, &iamrole.ListOptions{Name: role.Name})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(names).Should(BeEmpty())
+	})
+	It("should list every page of inline policies", func() {
+		var err error
+		role, err = client.Create(ctx, &iamrole.CreateOptions{
+			Name:               fmt.Sprintf("iam-role-%s", uuid.New().String()),
+			MaxDurationSeconds: 3600,
+			PolicyDocument:     policy,
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		document := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:*"],"Resource":["*"]}]}`
+		for k := 0; k < fake.DefaultMaxItems+5; k++ {
+			Expect(client.PutInlinePolicy(ctx, &iamrole.PutInlinePolicyOptions{
+				Name:       role.Name,
+				PolicyName: fmt.Sprintf("policy-%03d", k),
+				Document:   document,
+			})).Should(Succeed())
+		}
+		names, err := client.ListInlinePolicies(ctx, &iamrole.ListOptions{Name: role.Name})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(names).Should(HaveLen(fake.DefaultMaxItems + 5))
+	})
+	It("should manage the permissions boundary", func() {
+		var policyClient = iampolicy.New(service, namespace)
+		boundary, err := policyClient.Create(ctx, &iampolicy.CreateOptions{
+			Name:        "iam-boundary-" + uuid.New().String()[:8],
+			Document:    `{"Version": "2012-10-17", "Statement": [{"Sid": "S3FullAccess"}]}`,
+			Description: "iam test boundary",
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		role, err = client.Create(ctx, &iamrole.CreateOptions{
+			Name:                fmt.Sprintf("iam-role-%s", uuid.New().String()),
+			Description:         "aws iam controller tests",
+			MaxDurationSeconds:  3600,
+			PolicyDocument:      policy,
+			PermissionsBoundary: boundary.Arn,
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(role.PermissionsBoundary).Should(Equal(boundary.Arn))
+
+		Expect(client.DeletePermissionsBoundary(ctx, &iamrole.DeletePermissionsBoundaryOptions{
+			Name: role.Name,
+		})).Should(Succeed())
+		out, err := client.Get(ctx, &iamrole.GetOptions{Name: role.Name})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(out.PermissionsBoundary).Should(BeEmpty())
+
+		_, err = client.Update(ctx, &iamrole.UpdateOptions{
+			Name:                role.Name,
+			PermissionsBoundary: boundary.Arn,
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		out, err = client.Get(ctx, &iamrole.GetOptions{Name: role.Name})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(out.PermissionsBoundary).Should(Equal(boundary.Arn))
+	})
+	It("should tag and untag the role", func() {
+		var err error
+		role, err = client.Create(ctx, &iamrole.CreateOptions{
+			Name:               fmt.Sprintf("iam-role-%s", uuid.New().String()),
+			Description:        "aws iam controller tests",
+			MaxDurationSeconds: 3600,
+			PolicyDocument:     policy,
+			Tags:               map[string]string{"team": "payments"},
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(role.Tags).Should(Equal(map[string]string{"team": "payments"}))
+
+		Expect(client.Tag(ctx, &iamrole.TagOptions{
+			Name: role.Name,
+			Tags: map[string]string{"team": "billing", "env": "prod"},
+		})).Should(Succeed())
+		out, err := client.Get(ctx, &iamrole.GetOptions{Name: role.Name})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(out.Tags).Should(Equal(map[string]string{"team": "billing", "env": "prod"}))
+
+		Expect(client.Untag(ctx, &iamrole.UntagOptions{
+			Name: role.Name,
+			Keys: []string{"team"},
+		})).Should(Succeed())
+		out, err = client.Get(ctx, &iamrole.GetOptions{Name: role.Name})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(out.Tags).Should(Equal(map[string]string{"env": "prod"}))
+	})
+	It("should remove the role from its instance profiles", func() {
+		var err error
+		role, err = client.Create(ctx, &iamrole.CreateOptions{
+			Name:               fmt.Sprintf("iam-role-%s", uuid.New().String()),
+			Description:        "aws iam controller tests",
+			MaxDurationSeconds: 3600,
+			PolicyDocument:     policy,
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		_, err = service.CreateInstanceProfile(ctx, &iam.CreateInstanceProfileInput{InstanceProfileName: aws.String(role.Name)})
+		Expect(err).ShouldNot(HaveOccurred())
+		_, err = service.AddRoleToInstanceProfile(ctx, &iam.AddRoleToInstanceProfileInput{
+			InstanceProfileName: aws.String(role.Name),
+			RoleName:            aws.String(role.Name),
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		names, err := client.ListInstanceProfiles(ctx, &iamrole.ListOptions{Name: role.Name})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(names).Should(ConsistOf(role.Name))
+		Expect(client.RemoveFromInstanceProfile(ctx, &iamrole.RemoveFromInstanceProfileOptions{
+			Name:                role.Name,
+			InstanceProfileName: role.Name,
+		})).Should(Succeed())
+		names, err = client.ListInstanceProfiles(ctx, &iamrole.ListOptions{Name: role.Name})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(names).Should(BeEmpty())
+	})
 })
@@ -7,7 +7,16 @@ type Interface interface {
 	Update(ctx context.Context, options *UpdateOptions) (*IamRole, error)
 	Get(ctx context.Context, options *GetOptions) (*IamRole, error)
 	Delete(ctx context.Context, options *DeleteOptions) error
+	DeletePermissionsBoundary(ctx context.Context, options *DeletePermissionsBoundaryOptions) error
+	Tag(ctx context.Context, options *TagOptions) error
+	Untag(ctx context.Context, options *UntagOptions) error
 	AttachPolicy(ctx context.Context, options *AttachOptions) error
 	DetachPolicy(ctx context.Context, options *DetachOptions) error
 	ListAttachedPolicies(ctx context.Context, options *ListOptions) (AttachedPolicies, error)
+	PutInlinePolicy(ctx context.Context, options *PutInlinePolicyOptions) error
+	GetInlinePolicy(ctx context.Context, options *GetInlinePolicyOptions) (*InlinePolicy, error)
+	DeleteInlinePolicy(ctx context.Context, options *DeleteInlinePolicyOptions) error
+	ListInlinePolicies(ctx context.Context, options *ListOptions) ([]string, error)
+	ListInstanceProfiles(ctx context.Context, options *ListOptions) ([]string, error)
+	RemoveFromInstanceProfile(ctx context.Context, options *RemoveFromInstanceProfileOptions) error
 }
@@ -13,10 +13,14 @@ type DetachOptions = AttachOptions
 type ListOptions = GetOptions
 
 type CreateOptions struct {
-	Name               string
-	Description        string
-	MaxDurationSeconds int32
-	PolicyDocument     string
+	Name                string
+	Description         string
+	MaxDurationSeconds  int32
+	PolicyDocument      string
+	PermissionsBoundary string
+	// Path is appended to the client path
+	Path string
+	Tags map[string]string
 }
 
 type GetOptions struct {
@@ -24,23 +28,64 @@ type GetOptions struct {
 }
 
 type UpdateOptions struct {
-	Name               string
-	Description        string
-	MaxDurationSeconds int32
-	PolicyDocument     string
+	Name string
+	// Description is left unchanged when nil. An empty string removes
+	// the description
+	Description         *string
+	MaxDurationSeconds  int32
+	PolicyDocument      string
+	PermissionsBoundary string
 }
 
 type DeleteOptions struct {
 	Name string
 }
 
+type DeletePermissionsBoundaryOptions = DeleteOptions
+
+type TagOptions struct {
+	Name string
+	Tags map[string]string
+}
+
+type UntagOptions struct {
+	Name string
+	Keys []string
+}
+
+type PutInlinePolicyOptions struct {
+	Name       string
+	PolicyName string
+	Document   string
+}
+
+type GetInlinePolicyOptions struct {
+	Name       string
+	PolicyName string
+}
+
+type DeleteInlinePolicyOptions = GetInlinePolicyOptions
+
+type RemoveFromInstanceProfileOptions struct {
+	Name                string
+	InstanceProfileName string
+}
+
+type InlinePolicy struct {
+	Name     string
+	Document string
+}
+
 type IamRole struct {
-	Arn         string
-	CreateDate  time.Time
-	Description string
-	Id          string
-	Name        string
-	TrustPolicy string
+	Arn                 string
+	CreateDate          time.Time
+	Description         string
+	Id                  string
+	Name                string
+	MaxDurationSeconds  int32
+	TrustPolicy         string
+	PermissionsBoundary string
+	Tags                map[string]string
 }
 
 type AttachedPolicy struct {
@@ -0,0 +1,199 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iamuser
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+
+	pkgaws "github.com/johnhoman/aws-iam-controller/pkg/aws"
+)
+
+type Client struct {
+	service pkgaws.IamUserService
+	path    string
+}
+
+func (c *Client) Create(ctx context.Context, options *CreateOptions) (*IamUser, error) {
+	out, err := c.service.CreateUser(ctx, &iam.CreateUserInput{
+		UserName: aws.String(options.Name),
+		Path:     aws.String(c.path),
+		Tags:     pkgaws.NewTags(options.Tags),
+	})
+	if err != nil {
+		return &IamUser{}, err
+	}
+	return c.Get(ctx, &GetOptions{Name: aws.ToString(out.User.UserName)})
+}
+
+func (c *Client) Get(ctx context.Context, options *GetOptions) (*IamUser, error) {
+	out, err := c.service.GetUser(ctx, &iam.GetUserInput{
+		UserName: aws.String(options.Name),
+	})
+	if err != nil {
+		return &IamUser{}, err
+	}
+	return &IamUser{
+		Arn:        aws.ToString(out.User.Arn),
+		CreateDate: aws.ToTime(out.User.CreateDate),
+		Id:         aws.ToString(out.User.UserId),
+		Name:       aws.ToString(out.User.UserName),
+		Tags:       pkgaws.TagMap(out.User.Tags),
+	}, nil
+}
+
+func (c *Client) Delete(ctx context.Context, options *DeleteOptions) error {
+	_, err := c.service.DeleteUser(ctx, &iam.DeleteUserInput{
+		UserName: aws.String(options.Name),
+	})
+	return err
+}
+
+func (c *Client) Tag(ctx context.Context, options *TagOptions) error {
+	_, err := c.service.TagUser(ctx, &iam.TagUserInput{
+		UserName: aws.String(options.Name),
+		Tags:     pkgaws.NewTags(options.Tags),
+	})
+	return err
+}
+
+func (c *Client) Untag(ctx context.Context, options *UntagOptions) error {
+	_, err := c.service.UntagUser(ctx, &iam.UntagUserInput{
+		UserName: aws.String(options.Name),
+		TagKeys:  options.Keys,
+	})
+	return err
+}
+
+func (c *Client) AttachPolicy(ctx context.Context, options *AttachOptions) error {
+	_, err := c.service.AttachUserPolicy(ctx, &iam.AttachUserPolicyInput{
+		UserName:  aws.String(options.Name),
+		PolicyArn: aws.String(options.PolicyArn),
+	})
+	return err
+}
+
+func (c *Client) DetachPolicy(ctx context.Context, options *DetachOptions) error {
+	_, err := c.service.DetachUserPolicy(ctx, &iam.DetachUserPolicyInput{
+		UserName:  aws.String(options.Name),
+		PolicyArn: aws.String(options.PolicyArn),
+	})
+	return err
+}
+
+// ListAttachedPolicies returns the arns of the managed policies attached
+// to the user
+func (c *Client) ListAttachedPolicies(ctx context.Context, options *ListOptions) ([]string, error) {
+	in := &iam.ListAttachedUserPoliciesInput{UserName: aws.String(options.Name)}
+	arns := make([]string, 0)
+	for {
+		out, err := c.service.ListAttachedUserPolicies(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+		for _, policy := range out.AttachedPolicies {
+			arns = append(arns, aws.ToString(policy.PolicyArn))
+		}
+		if !out.IsTruncated {
+			return arns, nil
+		}
+		in.Marker = out.Marker
+	}
+}
+
+func (c *Client) CreateAccessKey(ctx context.Context, options *CreateAccessKeyOptions) (*AccessKey, error) {
+	out, err := c.service.CreateAccessKey(ctx, &iam.CreateAccessKeyInput{
+		UserName: aws.String(options.Name),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &AccessKey{
+		AccessKeyId:     aws.ToString(out.AccessKey.AccessKeyId),
+		SecretAccessKey: aws.ToString(out.AccessKey.SecretAccessKey),
+		CreateDate:      aws.ToTime(out.AccessKey.CreateDate),
+	}, nil
+}
+
+// ListAccessKeys returns the user's access keys, newest first
+func (c *Client) ListAccessKeys(ctx context.Context, options *ListAccessKeysOptions) ([]AccessKeyMetadata, error) {
+	out, err := c.service.ListAccessKeys(ctx, &iam.ListAccessKeysInput{
+		UserName: aws.String(options.Name),
+	})
+	if err != nil {
+		return nil, err
+	}
+	// AWS lists keys oldest first, reversing them keeps keys created in
+	// the same second newest first after sorting
+	keys := make([]AccessKeyMetadata, 0, len(out.AccessKeyMetadata))
+	for k := len(out.AccessKeyMetadata) - 1; k >= 0; k-- {
+		key := out.AccessKeyMetadata[k]
+		keys = append(keys, AccessKeyMetadata{
+			AccessKeyId: aws.ToString(key.AccessKeyId),
+			CreateDate:  aws.ToTime(key.CreateDate),
+		})
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		return keys[i].CreateDate.After(keys[j].CreateDate)
+	})
+	return keys, nil
+}
+
+func (c *Client) DeleteAccessKey(ctx context.Context, options *DeleteAccessKeyOptions) error {
+	_, err := c.service.DeleteAccessKey(ctx, &iam.DeleteAccessKeyInput{
+		UserName:    aws.String(options.Name),
+		AccessKeyId: aws.String(options.AccessKeyId),
+	})
+	return err
+}
+
+// ListGroups returns the names of the groups the user is in
+func (c *Client) ListGroups(ctx context.Context, options *ListGroupsOptions) ([]string, error) {
+	in := &iam.ListGroupsForUserInput{UserName: aws.String(options.Name)}
+	names := make([]string, 0)
+	for {
+		out, err := c.service.ListGroupsForUser(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+		for _, group := range out.Groups {
+			names = append(names, aws.ToString(group.GroupName))
+		}
+		if !out.IsTruncated {
+			return names, nil
+		}
+		in.Marker = out.Marker
+	}
+}
+
+func (c *Client) RemoveFromGroup(ctx context.Context, options *RemoveFromGroupOptions) error {
+	_, err := c.service.RemoveUserFromGroup(ctx, &iam.RemoveUserFromGroupInput{
+		UserName:  aws.String(options.Name),
+		GroupName: aws.String(options.GroupName),
+	})
+	return err
+}
+
+var _ Interface = &Client{}
+
+func New(service pkgaws.IamUserService, path string) *Client {
+	return &Client{service: service, path: fmt.Sprintf("/%s/", path)}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iamuser_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/google/uuid"
+
+	pkgaws "github.com/johnhoman/aws-iam-controller/pkg/aws"
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/fake"
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/iamuser"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client", func() {
+	var service *fake.IamService
+	var client iamuser.Interface
+	var path string
+	var ctx context.Context
+	BeforeEach(func() {
+		ctx = context.Background()
+		path = "testspace-" + uuid.New().String()[:8]
+		service = fake.NewIamService()
+		client = iamuser.New(service, path)
+	})
+	It("Should create a user under the client path", func() {
+		user, err := client.Create(ctx, &iamuser.CreateOptions{
+			Name: "should-create-a-user",
+			Tags: map[string]string{"team": "a"},
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(user.Id).ShouldNot(BeEmpty())
+		Expect(user.Arn).To(HaveSuffix(fmt.Sprintf(":user/%s/should-create-a-user", path)))
+		Expect(user.Tags).To(Equal(map[string]string{"team": "a"}))
+
+		Expect(client.Delete(ctx, &iamuser.DeleteOptions{Name: user.Name})).To(Succeed())
+		_, err = client.Get(ctx, &iamuser.GetOptions{Name: user.Name})
+		Expect(pkgaws.IsNotFound(err)).To(BeTrue())
+	})
+	It("Should attach and detach policies", func() {
+		user, err := client.Create(ctx, &iamuser.CreateOptions{Name: "should-attach-policies"})
+		Expect(err).ShouldNot(HaveOccurred())
+		out, err := service.CreatePolicy(ctx, &iam.CreatePolicyInput{
+			PolicyName:     aws.String("should-attach-policies"),
+			PolicyDocument: aws.String(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`),
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		arn := aws.ToString(out.Policy.Arn)
+
+		Expect(client.AttachPolicy(ctx, &iamuser.AttachOptions{Name: user.Name, PolicyArn: arn})).To(Succeed())
+		attached, err := client.ListAttachedPolicies(ctx, &iamuser.ListOptions{Name: user.Name})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(attached).To(ConsistOf(arn))
+
+		Expect(client.DetachPolicy(ctx, &iamuser.DetachOptions{Name: user.Name, PolicyArn: arn})).To(Succeed())
+		attached, err = client.ListAttachedPolicies(ctx, &iamuser.ListOptions{Name: user.Name})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(attached).To(BeEmpty())
+	})
+	It("Should list access keys newest first", func() {
+		user, err := client.Create(ctx, &iamuser.CreateOptions{Name: "should-list-access-keys"})
+		Expect(err).ShouldNot(HaveOccurred())
+		first, err := client.CreateAccessKey(ctx, &iamuser.CreateAccessKeyOptions{Name: user.Name})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(first.SecretAccessKey).ShouldNot(BeEmpty())
+		second, err := client.CreateAccessKey(ctx, &iamuser.CreateAccessKeyOptions{Name: user.Name})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		keys, err := client.ListAccessKeys(ctx, &iamuser.ListAccessKeysOptions{Name: user.Name})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(keys).To(HaveLen(2))
+		Expect(keys[0].AccessKeyId).To(Equal(second.AccessKeyId))
+		Expect(keys[1].AccessKeyId).To(Equal(first.AccessKeyId))
+
+		Expect(client.DeleteAccessKey(ctx, &iamuser.DeleteAccessKeyOptions{
+			Name:        user.Name,
+			AccessKeyId: first.AccessKeyId,
+		})).To(Succeed())
+		keys, err = client.ListAccessKeys(ctx, &iamuser.ListAccessKeysOptions{Name: user.Name})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(keys).To(HaveLen(1))
+		Expect(keys[0].AccessKeyId).To(Equal(second.AccessKeyId))
+	})
+	It("Should list and leave the user's groups", func() {
+		user, err := client.Create(ctx, &iamuser.CreateOptions{Name: "should-list-groups"})
+		Expect(err).ShouldNot(HaveOccurred())
+		_, err = service.CreateGroup(ctx, &iam.CreateGroupInput{GroupName: aws.String("admins")})
+		Expect(err).ShouldNot(HaveOccurred())
+		_, err = service.AddUserToGroup(ctx, &iam.AddUserToGroupInput{
+			GroupName: aws.String("admins"),
+			UserName:  aws.String(user.Name),
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		groups, err := client.ListGroups(ctx, &iamuser.ListGroupsOptions{Name: user.Name})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(groups).To(ConsistOf("admins"))
+
+		Expect(client.RemoveFromGroup(ctx, &iamuser.RemoveFromGroupOptions{Name: user.Name, GroupName: "admins"})).To(Succeed())
+		groups, err = client.ListGroups(ctx, &iamuser.ListGroupsOptions{Name: user.Name})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(groups).To(BeEmpty())
+	})
+})
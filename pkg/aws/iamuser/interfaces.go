@@ -0,0 +1,35 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iamuser
+
+import "context"
+
+type Interface interface {
+	Create(ctx context.Context, options *CreateOptions) (*IamUser, error)
+	Get(ctx context.Context, options *GetOptions) (*IamUser, error)
+	Delete(ctx context.Context, options *DeleteOptions) error
+	Tag(ctx context.Context, options *TagOptions) error
+	Untag(ctx context.Context, options *UntagOptions) error
+	AttachPolicy(ctx context.Context, options *AttachOptions) error
+	DetachPolicy(ctx context.Context, options *DetachOptions) error
+	ListAttachedPolicies(ctx context.Context, options *ListOptions) ([]string, error)
+	CreateAccessKey(ctx context.Context, options *CreateAccessKeyOptions) (*AccessKey, error)
+	ListAccessKeys(ctx context.Context, options *ListAccessKeysOptions) ([]AccessKeyMetadata, error)
+	DeleteAccessKey(ctx context.Context, options *DeleteAccessKeyOptions) error
+	ListGroups(ctx context.Context, options *ListGroupsOptions) ([]string, error)
+	RemoveFromGroup(ctx context.Context, options *RemoveFromGroupOptions) error
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iamuser
+
+import (
+	"time"
+)
+
+type CreateOptions struct {
+	Name string
+	Tags map[string]string
+}
+
+type GetOptions struct {
+	Name string
+}
+
+type DeleteOptions = GetOptions
+
+type ListOptions = GetOptions
+
+type TagOptions struct {
+	Name string
+	Tags map[string]string
+}
+
+type UntagOptions struct {
+	Name string
+	Keys []string
+}
+
+type AttachOptions struct {
+	Name      string
+	PolicyArn string
+}
+
+type DetachOptions = AttachOptions
+
+type CreateAccessKeyOptions = GetOptions
+
+type ListAccessKeysOptions = GetOptions
+
+type DeleteAccessKeyOptions struct {
+	Name        string
+	AccessKeyId string
+}
+
+type ListGroupsOptions = GetOptions
+
+type RemoveFromGroupOptions struct {
+	Name      string
+	GroupName string
+}
+
+type IamUser struct {
+	Arn        string
+	CreateDate time.Time
+	Id         string
+	Name       string
+	Tags       map[string]string
+}
+
+// AccessKey is a newly created access key. The secret is only available
+// when the key is created
+type AccessKey struct {
+	AccessKeyId     string
+	SecretAccessKey string
+	CreateDate      time.Time
+}
+
+// AccessKeyMetadata describes an existing access key
+type AccessKeyMetadata struct {
+	AccessKeyId string
+	CreateDate  time.Time
+}
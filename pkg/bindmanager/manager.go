@@ -4,48 +4,51 @@ import (
 	"context"
 	"fmt"
 	"golang.org/x/text/language"
-	"reflect"
 	"strings"
 
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	"golang.org/x/text/cases"
 
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/iamoidcprovider"
 	"github.com/johnhoman/aws-iam-controller/pkg/aws/iamrole"
 )
 
 const (
 	EffectAllow                     = "Allow"
+	EffectDeny                      = "Deny"
 	ActionAssumeRoleWithWebIdentity = "sts:AssumeRoleWithWebIdentity"
+	ActionAssumeRole                = "sts:AssumeRole"
+	ActionSetSourceIdentity         = "sts:SetSourceIdentity"
 	SidLabelFormat                  = "Allow Service Account %s %s"
+	ServiceSidLabelFormat           = "Allow Services %s %s"
+	PrincipalSidLabelFormat         = "Allow Principals %s %s"
 	SubjectFormat                   = "system:serviceaccount:%s:%s"
+	// DenyAllAWSSid is the sid of the statement in the default trust policy
+	// that denies every AWS principal. It's removed while the role trusts
+	// AWS principals, since a deny can't be overridden by an allow
+	DenyAllAWSSid = "DenyAllAWS"
 )
 
 type BindManager struct {
 	iamrole.Interface
-	oidcArn string
-	issuer  string
+	provider Provider
 }
 
 // Bind will establish a trust relationship between a role and a service account
-// by allowing the service account to AssumeRoleWithWebIdentity
+// by allowing the service account to AssumeRoleWithWebIdentity. The services
+// and principals in the binding are allowed to AssumeRole. Statements that
+// weren't written by the manager are kept
 func (b *BindManager) Bind(ctx context.Context, binding *Binding) error {
-	sid := sidLabel(binding.Role.Name, binding.Role.Namespace)
-	upstream, err := b.Get(ctx, &iamrole.GetOptions{Name: binding.Role.GetName()})
+	upstream, err := b.Get(ctx, &iamrole.GetOptions{Name: binding.RoleName})
 	if err != nil {
 		return err
 	}
-	original := &policyDocument{}
+
 	doc := &policyDocument{}
 	// TODO: make sure trust policy is not empty
 	if err := doc.Unmarshal(upstream.TrustPolicy); err != nil {
 		return err
 	}
-	*original = *doc
-	found := -1
-	for k, st := range doc.Statements {
-		if strings.Compare(st.Sid, sid) == 0 {
-			found = k
-		}
-	}
 
 	serviceAccounts := make([]string, 0, len(binding.ServiceAccounts))
 	for _, ref := range binding.ServiceAccounts {
@@ -55,47 +58,82 @@ func (b *BindManager) Bind(ctx context.Context, binding *Binding) error {
 		)
 	}
 	// If there's no service account then remove the statement
-	var accounts interface{} = serviceAccounts
-	if len(serviceAccounts) == 1 {
-		accounts = serviceAccounts[0]
+	var stmt *statement
+	if len(serviceAccounts) > 0 {
+		var accounts interface{} = serviceAccounts
+		if len(serviceAccounts) == 1 {
+			accounts = serviceAccounts[0]
+		}
+		oidcArn, err := b.provider.ProviderArn(ctx)
+		if err != nil {
+			return err
+		}
+		issuer, err := iamoidcprovider.URLFromArn(oidcArn)
+		if err != nil {
+			return err
+		}
+		stmt = &statement{
+			Sid:       sidLabel(binding.Role.GetName(), binding.Role.GetNamespace()),
+			Effect:    EffectAllow,
+			Principal: principal{Federated: oidcArn},
+			Action:    ActionAssumeRoleWithWebIdentity,
+			Condition: &condition{
+				StringEquals: map[string]interface{}{issuer + ":sub": accounts},
+			},
+		}
 	}
+	changed := doc.setStatement(sidLabel(binding.Role.GetName(), binding.Role.GetNamespace()), stmt)
 
-	stmt := statement{
-		Sid:       sid,
-		Effect:    EffectAllow,
-		Principal: principal{Federated: b.oidcArn},
-		Action:    ActionAssumeRoleWithWebIdentity,
-		Condition: condition{
-			StringEquals: map[string]interface{}{b.issuer: accounts},
-		},
-	}
-	if found < 0 {
-		// Not found
-		if len(binding.ServiceAccounts) > 0 {
-			doc.Statements = append(doc.Statements, stmt)
+	stmt = nil
+	if len(binding.Services) > 0 {
+		var services interface{} = binding.Services
+		if len(binding.Services) == 1 {
+			services = binding.Services[0]
+		}
+		stmt = &statement{
+			Sid:       serviceSidLabel(binding.Role.GetName(), binding.Role.GetNamespace()),
+			Effect:    EffectAllow,
+			Principal: principal{Service: services},
+			Action:    ActionAssumeRole,
 		}
-	} else {
-		// Found
-		if len(binding.ServiceAccounts) == 0 {
-			statements := make([]statement, 0, len(doc.Statements)-1)
-			for k, stm := range doc.Statements {
-				if k != found {
-					statements = append(statements, stm)
-				}
-			}
-			doc.Statements = statements
-		} else {
-			doc.Statements[found] = stmt
+	}
+	if doc.setStatement(serviceSidLabel(binding.Role.GetName(), binding.Role.GetNamespace()), stmt) {
+		changed = true
+	}
+
+	stmt, err = principalStatement(binding, upstream.Arn)
+	if err != nil {
+		return err
+	}
+	if doc.setStatement(principalSidLabel(binding.Role.GetName(), binding.Role.GetNamespace()), stmt) {
+		changed = true
+	}
+	if stmt != nil && doc.setStatement(DenyAllAWSSid, nil) {
+		changed = true
+		binding.DenyAllAWSRemoved = true
+	}
+	if stmt == nil && binding.DenyAllAWSRemoved {
+		// The role no longer trusts any AWS principals, so the statement
+		// denying them is restored. Roles that never had it, e.g. adopted
+		// roles, are left alone
+		if doc.setStatement(DenyAllAWSSid, &statement{
+			Sid:       DenyAllAWSSid,
+			Effect:    EffectDeny,
+			Principal: principal{AWS: "*"},
+			Action:    ActionAssumeRole,
+		}) {
+			changed = true
 		}
+		binding.DenyAllAWSRemoved = false
 	}
 
-	if !reflect.DeepEqual(doc, original) {
+	if changed {
 		trust, err := doc.Marshal()
 		if err != nil {
 			return err
 		}
 		if _, err := b.Update(ctx, &iamrole.UpdateOptions{
-			Name:           binding.Role.GetName(),
+			Name:           binding.RoleName,
 			PolicyDocument: trust,
 		}); err != nil {
 			return err
@@ -104,12 +142,62 @@ func (b *BindManager) Bind(ctx context.Context, binding *Binding) error {
 	return nil
 }
 
+// principalStatement returns the statement that allows the AWS principals in
+// the binding to assume the role, or nil when there aren't any. Account ids
+// are written as the account root arn, since that's how IAM stores them
+func principalStatement(binding *Binding, roleArn string) (*statement, error) {
+	if len(binding.Principals) == 0 {
+		return nil, nil
+	}
+	parsed, err := arn.Parse(roleArn)
+	if err != nil {
+		return nil, err
+	}
+	principals := make([]string, 0, len(binding.Principals))
+	for _, value := range binding.Principals {
+		if !arn.IsARN(value) {
+			value = fmt.Sprintf("arn:%s:iam::%s:root", parsed.Partition, value)
+		}
+		principals = append(principals, value)
+	}
+	var aws interface{} = principals
+	if len(principals) == 1 {
+		aws = principals[0]
+	}
+	stmt := &statement{
+		Sid:       principalSidLabel(binding.Role.GetName(), binding.Role.GetNamespace()),
+		Effect:    EffectAllow,
+		Principal: principal{AWS: aws},
+		Action:    ActionAssumeRole,
+	}
+	if conditions := binding.Conditions; conditions != nil {
+		cond := &condition{}
+		if len(conditions.ExternalID) > 0 {
+			cond.StringEquals = map[string]interface{}{"sts:ExternalId": conditions.ExternalID}
+		}
+		if conditions.RequireMFA {
+			cond.Bool = map[string]interface{}{"aws:MultiFactorAuthPresent": "true"}
+		}
+		if len(conditions.SourceIdentity) > 0 {
+			// The principal has to be allowed to set the source identity
+			// it's required to have
+			stmt.Action = []string{ActionAssumeRole, ActionSetSourceIdentity}
+			cond.StringLike = map[string]interface{}{"sts:SourceIdentity": conditions.SourceIdentity}
+		}
+		if len(cond.Bool) > 0 || len(cond.StringEquals) > 0 || len(cond.StringLike) > 0 {
+			stmt.Condition = cond
+		}
+	}
+	return stmt, nil
+}
+
 var _ Manager = &BindManager{}
 
-// New returns a new BindManager instance
-func New(p iamrole.Interface, oidcArn string) *BindManager {
-	issuer := oidcArn[strings.Index(oidcArn, "/")+1:] + ":sub"
-	return &BindManager{Interface: p, oidcArn: oidcArn, issuer: issuer}
+// New returns a new BindManager instance. The oidc provider arn is resolved
+// when a service account is bound, so the provider can be created after the
+// controller starts
+func New(p iamrole.Interface, provider Provider) *BindManager {
+	return &BindManager{Interface: p, provider: provider}
 }
 
 func serviceAccountFormat(namespace, name string) string {
@@ -117,7 +205,19 @@ func serviceAccountFormat(namespace, name string) string {
 }
 
 func sidLabel(name, namespace string) string {
-	sid := fmt.Sprintf(SidLabelFormat, namespace, name)
+	return formatSid(SidLabelFormat, name, namespace)
+}
+
+func serviceSidLabel(name, namespace string) string {
+	return formatSid(ServiceSidLabelFormat, name, namespace)
+}
+
+func principalSidLabel(name, namespace string) string {
+	return formatSid(PrincipalSidLabelFormat, name, namespace)
+}
+
+func formatSid(format, name, namespace string) string {
+	sid := fmt.Sprintf(format, namespace, name)
 	sid = strings.ReplaceAll(sid, "-", " ")
 	sid = cases.Title(language.English).String(sid)
 	sid = strings.ReplaceAll(sid, " ", "")
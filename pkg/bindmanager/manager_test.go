@@ -1,8 +1,13 @@
 package bindmanager
 
 import (
+	"context"
 	"github.com/stretchr/testify/require"
 	"testing"
+
+	"github.com/johnhoman/aws-iam-controller/api/v1alpha1"
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/fake"
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/iamrole"
 )
 
 func Test_SidLabel(t *testing.T) {
@@ -30,3 +35,132 @@ func Test_SidLabel(t *testing.T) {
 		})
 	}
 }
+
+func Test_SetStatement(t *testing.T) {
+	doc := &policyDocument{}
+	require.NoError(t, doc.Unmarshal(`{"Version":"2012-10-17","Statement":[{"Sid":"AllowServiceAccountNode","Effect":"Allow","Principal":{"Federated":"arn"},"Action":"sts:AssumeRoleWithWebIdentity","Condition":{"StringEquals":{"issuer:sub":["a","b"]}}}]}`))
+
+	sid := serviceSidLabel("node", "")
+	require.Equal(t, "AllowServicesNode", sid)
+	stmt := &statement{
+		Sid:       sid,
+		Effect:    EffectAllow,
+		Principal: principal{Service: "ec2.amazonaws.com"},
+		Action:    ActionAssumeRole,
+	}
+	require.True(t, doc.setStatement(sid, stmt))
+	require.Len(t, doc.Statements, 2)
+	require.False(t, doc.setStatement(sid, stmt))
+
+	unchanged := &statement{
+		Sid:       "AllowServiceAccountNode",
+		Effect:    EffectAllow,
+		Principal: principal{Federated: "arn"},
+		Action:    ActionAssumeRoleWithWebIdentity,
+		Condition: &condition{StringEquals: map[string]interface{}{"issuer:sub": []string{"a", "b"}}},
+	}
+	require.False(t, doc.setStatement(unchanged.Sid, unchanged))
+
+	require.True(t, doc.setStatement(sid, nil))
+	require.Len(t, doc.Statements, 1)
+	require.False(t, doc.setStatement(sid, nil))
+}
+
+func Test_SetStatementKeepsUnmanagedStatements(t *testing.T) {
+	custom := `{"Sid":"Custom","Effect":"Allow","Principal":"*","Action":["sts:AssumeRole"],"Condition":{"ArnLike":{"aws:PrincipalArn":"arn:aws:iam::*:role/ci"}}}`
+	doc := &policyDocument{}
+	require.NoError(t, doc.Unmarshal(`{"Version":"2012-10-17","Statement":[`+custom+`]}`))
+
+	sid := principalSidLabel("node", "")
+	require.True(t, doc.setStatement(sid, &statement{
+		Sid:       sid,
+		Effect:    EffectAllow,
+		Principal: principal{AWS: "arn:aws:iam::111122223333:root"},
+		Action:    ActionAssumeRole,
+	}))
+	raw, err := doc.Marshal()
+	require.NoError(t, err)
+	require.Contains(t, raw, custom)
+}
+
+func Test_PrincipalStatement(t *testing.T) {
+	role := &v1alpha1.IamRole{}
+	role.SetName("node")
+	binding := &Binding{
+		Role:       role,
+		Principals: []string{"111122223333", "arn:aws-cn:iam::444455556666:role/ci"},
+		Conditions: &v1alpha1.TrustConditions{ExternalID: "team-a", SourceIdentity: "ci-*"},
+	}
+	stmt, err := principalStatement(binding, "arn:aws-cn:iam::444455556666:role/node")
+	require.NoError(t, err)
+	require.Equal(t, "AllowPrincipalsNode", stmt.Sid)
+	require.Equal(t, []string{"arn:aws-cn:iam::111122223333:root", "arn:aws-cn:iam::444455556666:role/ci"}, stmt.Principal.AWS)
+	require.Equal(t, []string{ActionAssumeRole, ActionSetSourceIdentity}, stmt.Action)
+	require.Equal(t, map[string]interface{}{"sts:ExternalId": "team-a"}, stmt.Condition.StringEquals)
+	require.Equal(t, map[string]interface{}{"sts:SourceIdentity": "ci-*"}, stmt.Condition.StringLike)
+	require.Nil(t, stmt.Condition.Bool)
+
+	stmt, err = principalStatement(&Binding{Role: role}, "arn:aws:iam::444455556666:role/node")
+	require.NoError(t, err)
+	require.Nil(t, stmt)
+}
+
+func Test_BindRestoresDenyAllAWS(t *testing.T) {
+	ctx := context.Background()
+	roles := iamrole.New(fake.NewIamService(), "bindmanager-test")
+	upstream, err := roles.Create(ctx, &iamrole.CreateOptions{
+		Name:               "node",
+		MaxDurationSeconds: 3600,
+		PolicyDocument:     `{"Version":"2012-10-17","Statement":[{"Sid":"DenyAllAWS","Effect":"Deny","Principal":{"AWS":"*"},"Action":"sts:AssumeRole"}]}`,
+	})
+	require.NoError(t, err)
+	role := &v1alpha1.IamRole{}
+	role.SetName("node")
+	manager := New(roles, StaticProvider("arn:aws:iam::111122223333:oidc-provider/oidc.example.com/id/EXAMPLE"))
+
+	binding := &Binding{Role: role, RoleName: upstream.Name, Principals: []string{"111122223333"}}
+	require.NoError(t, manager.Bind(ctx, binding))
+	require.True(t, binding.DenyAllAWSRemoved)
+	upstream, err = roles.Get(ctx, &iamrole.GetOptions{Name: upstream.Name})
+	require.NoError(t, err)
+	require.NotContains(t, upstream.TrustPolicy, DenyAllAWSSid)
+	require.Contains(t, upstream.TrustPolicy, principalSidLabel("node", ""))
+
+	binding = &Binding{Role: role, RoleName: upstream.Name, DenyAllAWSRemoved: binding.DenyAllAWSRemoved}
+	require.NoError(t, manager.Bind(ctx, binding))
+	require.False(t, binding.DenyAllAWSRemoved)
+	upstream, err = roles.Get(ctx, &iamrole.GetOptions{Name: upstream.Name})
+	require.NoError(t, err)
+	require.NotContains(t, upstream.TrustPolicy, principalSidLabel("node", ""))
+	require.Contains(t, upstream.TrustPolicy, `{"Sid":"DenyAllAWS","Effect":"Deny","Principal":{"AWS":"*"},"Action":"sts:AssumeRole"}`)
+}
+
+func Test_BindDoesNotAddDenyAllAWSToAdoptedRoles(t *testing.T) {
+	ctx := context.Background()
+	roles := iamrole.New(fake.NewIamService(), "bindmanager-test")
+	custom := `{"Sid":"Custom","Effect":"Allow","Principal":{"AWS":"arn:aws:iam::444455556666:role/ci"},"Action":"sts:AssumeRole"}`
+	upstream, err := roles.Create(ctx, &iamrole.CreateOptions{
+		Name:               "node",
+		MaxDurationSeconds: 3600,
+		PolicyDocument:     `{"Version":"2012-10-17","Statement":[` + custom + `]}`,
+	})
+	require.NoError(t, err)
+	role := &v1alpha1.IamRole{}
+	role.SetName("node")
+	manager := New(roles, StaticProvider("arn:aws:iam::111122223333:oidc-provider/oidc.example.com/id/EXAMPLE"))
+
+	binding := &Binding{Role: role, RoleName: upstream.Name, Principals: []string{"111122223333"}}
+	require.NoError(t, manager.Bind(ctx, binding))
+	require.False(t, binding.DenyAllAWSRemoved)
+	upstream, err = roles.Get(ctx, &iamrole.GetOptions{Name: upstream.Name})
+	require.NoError(t, err)
+	require.Contains(t, upstream.TrustPolicy, principalSidLabel("node", ""))
+
+	binding = &Binding{Role: role, RoleName: upstream.Name, DenyAllAWSRemoved: binding.DenyAllAWSRemoved}
+	require.NoError(t, manager.Bind(ctx, binding))
+	upstream, err = roles.Get(ctx, &iamrole.GetOptions{Name: upstream.Name})
+	require.NoError(t, err)
+	require.NotContains(t, upstream.TrustPolicy, principalSidLabel("node", ""))
+	require.NotContains(t, upstream.TrustPolicy, DenyAllAWSSid)
+	require.Contains(t, upstream.TrustPolicy, custom)
+}
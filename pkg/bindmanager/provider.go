@@ -0,0 +1,56 @@
+package bindmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/johnhoman/aws-iam-controller/api/v1alpha1"
+)
+
+// ErrProviderNotCreated is returned by providers that are waiting for the
+// oidc provider to be created
+var ErrProviderNotCreated = errors.New("oidc provider has not been created")
+
+// Provider resolves the arn of the oidc provider service accounts use to
+// assume roles
+type Provider interface {
+	ProviderArn(ctx context.Context) (string, error)
+}
+
+// StaticProvider is the arn of an oidc provider that is managed outside of
+// the cluster
+type StaticProvider string
+
+func (p StaticProvider) ProviderArn(_ context.Context) (string, error) {
+	return string(p), nil
+}
+
+var _ Provider = StaticProvider("")
+
+// ResourceProvider resolves the arn from the status of an IamOIDCProvider
+type ResourceProvider struct {
+	Client client.Reader
+	// Name is the name of the IamOIDCProvider
+	Name string
+}
+
+func (p *ResourceProvider) ProviderArn(ctx context.Context) (string, error) {
+	provider := &v1alpha1.IamOIDCProvider{}
+	if err := p.Client.Get(ctx, types.NamespacedName{Name: p.Name}, provider); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", fmt.Errorf("%s: %w", p.Name, ErrProviderNotCreated)
+		}
+		return "", err
+	}
+	if len(provider.Status.Arn) == 0 {
+		return "", fmt.Errorf("%s: %w", p.Name, ErrProviderNotCreated)
+	}
+	return provider.Status.Arn, nil
+}
+
+var _ Provider = &ResourceProvider{}
@@ -8,25 +8,73 @@ import (
 )
 
 type Binding struct {
-	Role            *v1alpha1.IamRole
+	Role v1alpha1.IamRoleObject
+	// RoleName is the name of the upstream iam role
+	RoleName        string
 	ServiceAccounts []corev1.ObjectReference
+	// Services are the service principals allowed to assume the role, e.g.
+	// ec2.amazonaws.com for roles in an instance profile
+	Services []string
+	// Principals are the AWS accounts, roles and users allowed to assume
+	// the role
+	Principals []string
+	// Conditions restrict when the Principals can assume the role
+	Conditions *v1alpha1.TrustConditions
+	// DenyAllAWSRemoved records whether the manager removed the DenyAllAWS
+	// statement from the trust policy. Bind updates it, so the caller can
+	// keep it between calls
+	DenyAllAWSRemoved bool
 }
 
 type condition struct {
+	Bool         map[string]interface{} `json:",omitempty"` // nolint: tagliatelle
 	StringEquals map[string]interface{} `json:",omitempty"` // nolint: tagliatelle
+	StringLike   map[string]interface{} `json:",omitempty"` // nolint: tagliatelle
 }
 
 type principal struct {
 	AWS       interface{} `json:",omitempty"` // nolint: tagliatelle
-	Federated string      `json:",omitempty"` // nolint: tagliatelle
+	Federated interface{} `json:",omitempty"` // nolint: tagliatelle
+	Service   interface{} `json:",omitempty"` // nolint: tagliatelle
 }
 
 type statement struct {
-	Sid       string    `json:",omitempty"` // nolint: tagliatelle
-	Effect    string    `json:",omitempty"` // nolint: tagliatelle
-	Principal principal `json:",omitempty"` // nolint: tagliatelle
-	Action    string    `json:",omitempty"` // nolint: tagliatelle
-	Condition condition `json:",omitempty"` // nolint: tagliatelle
+	Sid       string      `json:",omitempty"` // nolint: tagliatelle
+	Effect    string      `json:",omitempty"` // nolint: tagliatelle
+	Principal principal   `json:",omitempty"` // nolint: tagliatelle
+	Action    interface{} `json:",omitempty"` // nolint: tagliatelle
+	Condition *condition  `json:",omitempty"` // nolint: tagliatelle
+
+	// raw is the statement as it was read. Statements that aren't managed
+	// here are written back unchanged, including fields the manager doesn't
+	// know about
+	raw []byte
+}
+
+// knownStatement is a statement without the custom json methods
+type knownStatement statement
+
+func (s *statement) UnmarshalJSON(b []byte) error {
+	known := knownStatement{}
+	if err := json.Unmarshal(b, &known); err != nil {
+		// Only the sid is needed for statements that have a different
+		// shape than the ones the manager writes, e.g. "Principal": "*"
+		sid := struct{ Sid string }{}
+		if err := json.Unmarshal(b, &sid); err != nil {
+			return err
+		}
+		known = knownStatement{Sid: sid.Sid}
+	}
+	*s = statement(known)
+	s.raw = append([]byte{}, b...)
+	return nil
+}
+
+func (s statement) MarshalJSON() ([]byte, error) {
+	if s.raw != nil {
+		return s.raw, nil
+	}
+	return json.Marshal(knownStatement(s))
 }
 
 type policyDocument struct {
@@ -34,6 +82,42 @@ type policyDocument struct {
 	Statements []statement `json:"Statement"` // nolint: tagliatelle
 }
 
+// setStatement replaces the statement with the sid, or appends it when the
+// document doesn't have one. The statement is removed when stmt is nil.
+// It returns whether the document changed
+func (pd *policyDocument) setStatement(sid string, stmt *statement) bool {
+	statements := make([]statement, 0, len(pd.Statements)+1)
+	found := false
+	changed := false
+	for _, st := range pd.Statements {
+		if st.Sid != sid {
+			statements = append(statements, st)
+			continue
+		}
+		found = true
+		if stmt == nil {
+			changed = true
+			continue
+		}
+		// Compare the json since unmarshalled values don't have the same
+		// types as the statement, e.g. []interface{} instead of []string
+		current, _ := json.Marshal(knownStatement(st))
+		desired, _ := json.Marshal(knownStatement(*stmt))
+		if string(current) != string(desired) {
+			changed = true
+		}
+		statements = append(statements, *stmt)
+	}
+	if !found && stmt != nil {
+		statements = append(statements, *stmt)
+		changed = true
+	}
+	if changed {
+		pd.Statements = statements
+	}
+	return changed
+}
+
 func (pd *policyDocument) Marshal() (string, error) {
 	raw, err := json.Marshal(pd)
 	if err != nil {
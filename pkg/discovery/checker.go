@@ -0,0 +1,106 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	pkgaws "github.com/johnhoman/aws-iam-controller/pkg/aws"
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/iamoidcprovider"
+	"github.com/johnhoman/aws-iam-controller/pkg/bindmanager"
+)
+
+// DefaultCheckInterval is how long the result of a provider check is reused
+// by the readiness probe
+const DefaultCheckInterval = time.Minute
+
+// ErrProviderMismatch is returned by Check when the provider is confirmed to
+// be missing or to belong to a different issuer
+var ErrProviderMismatch = errors.New("invalid oidc provider")
+
+// ProviderChecker checks that the oidc provider service accounts assume
+// roles through exists in IAM and belongs to the issuer of the api server
+type ProviderChecker struct {
+	Provider        bindmanager.Provider
+	ProviderService iamoidcprovider.Interface
+	// Issuer is the discovered service account issuer. The provider url
+	// isn't compared when it's empty
+	Issuer string
+	// Interval is how long ReadyzCheck reuses a result, so readiness probes
+	// don't call IAM every time. Defaults to DefaultCheckInterval
+	Interval time.Duration
+
+	mu      sync.Mutex
+	checked time.Time
+	err     error
+}
+
+// Check returns an error when the provider doesn't exist or its url isn't
+// the issuer. A provider that is waiting for an IamOIDCProvider to be created
+// isn't an error, since the IamOIDCProvider reports its own status
+func (c *ProviderChecker) Check(ctx context.Context) error {
+	providerArn, err := c.Provider.ProviderArn(ctx)
+	if err != nil {
+		if errors.Is(err, bindmanager.ErrProviderNotCreated) {
+			return nil
+		}
+		return err
+	}
+	upstream, err := c.ProviderService.Get(ctx, &iamoidcprovider.GetOptions{Arn: providerArn})
+	if err != nil {
+		if pkgaws.IsNotFound(err) {
+			return fmt.Errorf("%w: %s doesn't exist", ErrProviderMismatch, providerArn)
+		}
+		return err
+	}
+	if len(c.Issuer) > 0 && upstream.URL != iamoidcprovider.TrimScheme(c.Issuer) {
+		return fmt.Errorf("%w: %s doesn't match the service account issuer %s", ErrProviderMismatch, providerArn, c.Issuer)
+	}
+	return nil
+}
+
+// ReadyzCheck is a healthz.Checker that fails while the provider is missing
+// or belongs to a different issuer. Other errors, e.g. IAM being throttled,
+// are logged and the last result is kept, so they don't take the controller
+// out of service
+func (c *ProviderChecker) ReadyzCheck(req *http.Request) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	interval := c.Interval
+	if interval == 0 {
+		interval = DefaultCheckInterval
+	}
+	if !c.checked.IsZero() && time.Since(c.checked) < interval {
+		return c.err
+	}
+	err := c.Check(req.Context())
+	if err != nil && !errors.Is(err, ErrProviderMismatch) {
+		log.FromContext(req.Context()).Error(err, "unable to check the oidc provider")
+		return c.err
+	}
+	c.err = err
+	c.checked = time.Now()
+	return c.err
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"k8s.io/client-go/rest"
+
+	pkgaws "github.com/johnhoman/aws-iam-controller/pkg/aws"
+)
+
+// OpenIDConfigurationPath is where the api server publishes the discovery
+// document of the service account issuer
+const OpenIDConfigurationPath = "/.well-known/openid-configuration"
+
+//+kubebuilder:rbac:urls=/.well-known/openid-configuration,verbs=get
+
+type openIDConfiguration struct {
+	Issuer string `json:"issuer"`
+}
+
+// Issuer returns the service account issuer url of the api server
+func Issuer(ctx context.Context, c rest.Interface) (string, error) {
+	raw, err := c.Get().AbsPath(OpenIDConfigurationPath).DoRaw(ctx)
+	if err != nil {
+		return "", err
+	}
+	config := &openIDConfiguration{}
+	if err := json.Unmarshal(raw, config); err != nil {
+		return "", err
+	}
+	if len(config.Issuer) == 0 {
+		return "", fmt.Errorf("%s doesn't have an issuer", OpenIDConfigurationPath)
+	}
+	return config.Issuer, nil
+}
+
+// Account returns the account id and partition of the credentials the
+// controller is running with
+func Account(ctx context.Context, service pkgaws.StsService) (string, string, error) {
+	out, err := service.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", "", err
+	}
+	parsed, err := arn.Parse(aws.ToString(out.Arn))
+	if err != nil {
+		return "", "", err
+	}
+	return aws.ToString(out.Account), parsed.Partition, nil
+}
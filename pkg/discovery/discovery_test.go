@@ -0,0 +1,111 @@
+/*
+Copyright 2022 John Homan
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/fake"
+	"github.com/johnhoman/aws-iam-controller/pkg/aws/iamoidcprovider"
+	"github.com/johnhoman/aws-iam-controller/pkg/bindmanager"
+)
+
+const testIssuer = "https://oidc.eks.us-west-2.amazonaws.com/id/EXAMPLED539D4633E53DE1B71EXAMPLE"
+
+func Test_Issuer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, OpenIDConfigurationPath, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"issuer":"` + testIssuer + `","jwks_uri":"https://example.com/openid/v1/jwks"}`))
+	}))
+	defer server.Close()
+
+	c := kubernetes.NewForConfigOrDie(&rest.Config{Host: server.URL}).Discovery().RESTClient()
+	issuer, err := Issuer(context.Background(), c)
+	require.NoError(t, err)
+	require.Equal(t, testIssuer, issuer)
+}
+
+func Test_Account(t *testing.T) {
+	service := fake.NewIamService()
+	accountID, partition, err := Account(context.Background(), service)
+	require.NoError(t, err)
+	require.Equal(t, service.AccountID, accountID)
+	require.Equal(t, "aws", partition)
+}
+
+func Test_ProviderChecker(t *testing.T) {
+	ctx := context.Background()
+	service := fake.NewIamService()
+	providerService := iamoidcprovider.New(service)
+	providerArn := iamoidcprovider.ProviderArn("aws", service.AccountID, testIssuer)
+
+	checker := &ProviderChecker{
+		Provider:        bindmanager.StaticProvider(providerArn),
+		ProviderService: providerService,
+		Issuer:          testIssuer,
+	}
+	require.Error(t, checker.Check(ctx))
+
+	_, err := providerService.Create(ctx, &iamoidcprovider.CreateOptions{URL: testIssuer, ClientIDs: []string{"sts.amazonaws.com"}})
+	require.NoError(t, err)
+	require.NoError(t, checker.Check(ctx))
+
+	checker.Issuer = "https://oidc.eks.us-west-2.amazonaws.com/id/OTHER"
+	require.Error(t, checker.Check(ctx))
+}
+
+// errProvider is a provider that can't be resolved
+type errProvider struct{ err error }
+
+func (p errProvider) ProviderArn(context.Context) (string, error) { return "", p.err }
+
+func Test_ProviderCheckerReadyz(t *testing.T) {
+	service := fake.NewIamService()
+	providerService := iamoidcprovider.New(service)
+	providerArn := iamoidcprovider.ProviderArn("aws", service.AccountID, testIssuer)
+	req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+
+	checker := &ProviderChecker{
+		Provider:        errProvider{err: errors.New("throttled")},
+		ProviderService: providerService,
+		Issuer:          testIssuer,
+		Interval:        time.Nanosecond,
+	}
+	require.NoError(t, checker.ReadyzCheck(req))
+
+	checker.Provider = bindmanager.StaticProvider(providerArn)
+	err := checker.ReadyzCheck(req)
+	require.ErrorIs(t, err, ErrProviderMismatch)
+
+	checker.Provider = errProvider{err: errors.New("throttled")}
+	require.ErrorIs(t, checker.ReadyzCheck(req), ErrProviderMismatch)
+
+	_, err = providerService.Create(context.Background(), &iamoidcprovider.CreateOptions{URL: testIssuer, ClientIDs: []string{"sts.amazonaws.com"}})
+	require.NoError(t, err)
+	checker.Provider = bindmanager.StaticProvider(providerArn)
+	require.NoError(t, checker.ReadyzCheck(req))
+}
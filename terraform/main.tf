@@ -27,11 +27,84 @@ resource "aws_iam_role_policy" "aws_iam_controller_policy" {
         "iam:UpdateRole",
         "iam:ListRolePolicies",
         "iam:GetRolePolicy",
+        "iam:PutRolePolicy",
+        "iam:DeleteRolePolicy",
+        "iam:PutRolePermissionsBoundary",
+        "iam:DeleteRolePermissionsBoundary",
+        "iam:TagRole",
+        "iam:UntagRole",
+        "iam:ListInstanceProfilesForRole",
+        "iam:PassRole",
       ]
       Effect = "Allow"
       Resource = ["arn:aws:iam::${var.account_id}:role/*"]
     },{
-      Action = ["iam:ListRoles"]
+      Action = [
+        "iam:GetInstanceProfile",
+        "iam:CreateInstanceProfile",
+        "iam:DeleteInstanceProfile",
+        "iam:TagInstanceProfile",
+        "iam:UntagInstanceProfile",
+        "iam:AddRoleToInstanceProfile",
+        "iam:RemoveRoleFromInstanceProfile",
+      ]
+      Effect = "Allow"
+      Resource = ["arn:aws:iam::${var.account_id}:instance-profile/*"]
+    },{
+      Action = [
+        "iam:TagPolicy",
+        "iam:UntagPolicy",
+      ]
+      Effect = "Allow"
+      Resource = ["arn:aws:iam::${var.account_id}:policy/*"]
+    },{
+      Action = [
+        "iam:GetUser",
+        "iam:CreateUser",
+        "iam:DeleteUser",
+        "iam:TagUser",
+        "iam:UntagUser",
+        "iam:AttachUserPolicy",
+        "iam:DetachUserPolicy",
+        "iam:ListAttachedUserPolicies",
+        "iam:CreateAccessKey",
+        "iam:ListAccessKeys",
+        "iam:DeleteAccessKey",
+        "iam:ListGroupsForUser",
+      ]
+      Effect = "Allow"
+      Resource = ["arn:aws:iam::${var.account_id}:user/*"]
+    },{
+      Action = [
+        "iam:GetGroup",
+        "iam:CreateGroup",
+        "iam:DeleteGroup",
+        "iam:AddUserToGroup",
+        "iam:RemoveUserFromGroup",
+        "iam:AttachGroupPolicy",
+        "iam:DetachGroupPolicy",
+        "iam:ListAttachedGroupPolicies",
+      ]
+      Effect = "Allow"
+      Resource = ["arn:aws:iam::${var.account_id}:group/*"]
+    },{
+      Action = [
+        "iam:GetOpenIDConnectProvider",
+        "iam:CreateOpenIDConnectProvider",
+        "iam:DeleteOpenIDConnectProvider",
+        "iam:AddClientIDToOpenIDConnectProvider",
+        "iam:RemoveClientIDFromOpenIDConnectProvider",
+        "iam:UpdateOpenIDConnectProviderThumbprint",
+        "iam:TagOpenIDConnectProvider",
+        "iam:UntagOpenIDConnectProvider",
+      ]
+      Effect = "Allow"
+      Resource = ["arn:aws:iam::${var.account_id}:oidc-provider/*"]
+    },{
+      Action = [
+        "iam:ListRoles",
+        "iam:ListOpenIDConnectProviders",
+      ]
       Effect = "Allow"
       Resource = ["*"]
 